@@ -14,7 +14,9 @@ import (
 	"github.com/veps-service-480701/api-gateway/internal/auth"
 	"github.com/veps-service-480701/api-gateway/internal/database"
 	"github.com/veps-service-480701/api-gateway/internal/handler"
+	"github.com/veps-service-480701/api-gateway/internal/idempotency"
 	"github.com/veps-service-480701/api-gateway/internal/secrets"
+	"github.com/veps-service-480701/api-gateway/internal/veto"
 )
 
 func main() {
@@ -37,15 +39,39 @@ func main() {
 
 	log.Printf("[Main] Database client initialized")
 
+	// Asynchronous submissions are kept for polling until the retention ends
+	dbClient.StartSubmissionPurge(config.SubmissionRetention)
+
+	// The Veto Service is only called to explain events; submission goes through the Boundary Adapter
+	var vetoClient *veto.Client
+	if config.VetoServiceURL != "" {
+		vetoClient = veto.NewClient(config.VetoServiceURL, 10*time.Second)
+		log.Printf("[Main] Veto client initialized (URL: %s)", config.VetoServiceURL)
+	} else {
+		log.Println("[Main] VETO_SERVICE_URL not set, event explain is disabled")
+	}
+
+	// Idempotency keys let clients retry submissions whose response was lost
+	idempotencyStore := idempotency.NewStore(dbClient, config.IdempotencyKeyTTL)
+	if config.IdempotencyKeyTTL > 0 {
+		log.Printf("[Main] Idempotency keys enabled (TTL: %s)", config.IdempotencyKeyTTL)
+	} else {
+		log.Println("[Main] IDEMPOTENCY_KEY_TTL is 0, Idempotency-Key headers are ignored")
+	}
+
 	// Initialize HTTP handler
-	h := handler.New(config.BoundaryURL, dbClient)
+	h := handler.New(config.BoundaryURL, dbClient, vetoClient, idempotencyStore)
+
+	// Submissions left pending by an instance that went away are failed and
+	// their callbacks sent
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	h.StartSubmissionSweeper(sweepCtx)
 
 	// Set up HTTP server
 	mux := http.NewServeMux()
 	h.RegisterRoutes(mux)
 
-	// Add middleware (auth -> logging -> cors)
-	wrappedMux := loggingMiddleware(auth.Middleware(keyStore, rateLimiter)(corsMiddleware(mux)))
+	wrappedMux := withMiddleware(mux, keyStore, rateLimiter)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%s", config.Port),
@@ -69,6 +95,7 @@ func main() {
 	<-quit
 
 	log.Println("[Main] Shutdown signal received, gracefully shutting down...")
+	stopSweeper()
 
 	// Give outstanding requests 30 seconds to complete
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -78,15 +105,24 @@ func main() {
 		log.Fatalf("[Main] Server forced to shutdown: %v", err)
 	}
 
+	// Accepted asynchronous submissions are finished within the same grace period
+	if err := h.WaitForSubmissions(shutdownCtx); err != nil {
+		log.Printf("[Main] Warning: asynchronous submissions still running at shutdown: %v", err)
+	}
+
 	log.Println("[Main] Server exited successfully")
 }
 
 // Config holds application configuration
 type Config struct {
-	Port         string
-	BoundaryURL  string
-	DatabaseURL  string
-	ProjectID    string
+	Port           string
+	BoundaryURL    string
+	VetoServiceURL string
+	DatabaseURL    string
+	ProjectID      string
+
+	IdempotencyKeyTTL   time.Duration // 0 disables Idempotency-Key handling
+	SubmissionRetention time.Duration // how long asynchronous submissions can be polled
 }
 
 // loadConfig loads configuration from environment variables
@@ -136,17 +172,42 @@ func loadConfig() Config {
 	databaseURL := fmt.Sprintf("host=/cloudsql/%s user=%s password=%s dbname=%s sslmode=disable",
 		dbInstance, dbUser, dbPassword, dbName)
 
+	idempotencyKeyTTL := 24 * time.Hour
+	if ttl := os.Getenv("IDEMPOTENCY_KEY_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil || parsed < 0 {
+			log.Fatalf("[Main] Invalid IDEMPOTENCY_KEY_TTL %q: must be a non-negative duration", ttl)
+		}
+		idempotencyKeyTTL = parsed
+	}
+
+	submissionRetention := 7 * 24 * time.Hour
+	if retention := os.Getenv("SUBMISSION_RETENTION"); retention != "" {
+		parsed, err := time.ParseDuration(retention)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("[Main] Invalid SUBMISSION_RETENTION %q: must be a positive duration", retention)
+		}
+		submissionRetention = parsed
+	}
+
 	log.Printf("[Main] Configuration loaded:")
 	log.Printf("  Port: %s", port)
 	log.Printf("  Boundary Adapter: %s", boundaryURL)
+	log.Printf("  Veto Service: %s", os.Getenv("VETO_SERVICE_URL"))
 	log.Printf("  Database: %s", maskConnectionString(databaseURL))
 	log.Printf("  Project: %s", projectID)
+	log.Printf("  Idempotency key TTL: %s", idempotencyKeyTTL)
+	log.Printf("  Submission retention: %s", submissionRetention)
 
 	return Config{
-		Port:        port,
-		BoundaryURL: boundaryURL,
-		DatabaseURL: databaseURL,
-		ProjectID:   projectID,
+		Port:           port,
+		BoundaryURL:    boundaryURL,
+		VetoServiceURL: os.Getenv("VETO_SERVICE_URL"),
+		DatabaseURL:    databaseURL,
+		ProjectID:      projectID,
+
+		IdempotencyKeyTTL:   idempotencyKeyTTL,
+		SubmissionRetention: submissionRetention,
 	}
 }
 
@@ -199,12 +260,20 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// withMiddleware wraps the routes in logging, then CORS, then auth
+// CORS sits outside auth: browsers send preflights without credentials, and
+// auth and rate-limit errors need the CORS headers to be readable
+func withMiddleware(mux http.Handler, keyStore *auth.KeyStore, rateLimiter *auth.RateLimiter) http.Handler {
+	return loggingMiddleware(corsMiddleware(auth.Middleware(keyStore, rateLimiter)(mux)))
+}
+
 // corsMiddleware adds CORS headers
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed, Location")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
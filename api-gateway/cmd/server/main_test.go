@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/veps-service-480701/api-gateway/internal/auth"
+)
+
+func TestPreflightSkipsAuth(t *testing.T) {
+	called := false
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+	handler := withMiddleware(mux, &auth.KeyStore{}, auth.NewRateLimiter())
+
+	// Browsers send preflights without the Authorization header
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/events", nil)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type, idempotency-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("preflight status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if allowed := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(allowed, "Idempotency-Key") {
+		t.Errorf("Access-Control-Allow-Headers = %q, want Idempotency-Key allowed", allowed)
+	}
+	if called {
+		t.Error("preflight reached the routes")
+	}
+
+	// A rejected request is still readable by the browser
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/events", nil))
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("unauthenticated request = %d with origin %q, want 401 with CORS headers", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
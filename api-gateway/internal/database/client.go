@@ -3,18 +3,21 @@ package database
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/veps-service-480701/api-gateway/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
 )
 
 // Client handles database operations
+// The events table is owned by the RDB Updater; reads go through the shared eventdb package
 type Client struct {
-	db *sql.DB
+	db     *sql.DB
+	events *eventdb.Store
 }
 
 // NewClient creates a new database client
@@ -34,7 +37,16 @@ func NewClient(connectionString string) (*Client, error) {
 
 	log.Println("[DB] Connected to PostgreSQL")
 
-	return &Client{db: db}, nil
+	// The RDB Updater migrates the schema; warn if it has not caught up yet
+	version, err := eventdb.CurrentVersion(ctx, db)
+	if err != nil {
+		log.Printf("[DB] Warning: failed to read schema version: %v", err)
+	} else if version < eventdb.LatestVersion() {
+		log.Printf("[DB] Warning: events schema is at version %d, expected %d; start the RDB Updater to migrate it",
+			version, eventdb.LatestVersion())
+	}
+
+	return &Client{db: db, events: eventdb.New(db)}, nil
 }
 
 // Close closes the database connection
@@ -42,206 +54,91 @@ func (c *Client) Close() error {
 	return c.db.Close()
 }
 
-// GetEventBySequence retrieves an event by its sequence number
+// GetEventBySequence retrieves an event by its ledger sequence number
 func (c *Client) GetEventBySequence(ctx context.Context, sequenceNumber uint64) (*models.EventSummary, error) {
-	query := `
-		SELECT 
-			id, 
-			type, 
-			source, 
-			timestamp, 
-			actor, 
-			evidence, 
-			vector_clock, 
-			metadata
-		FROM events
-		WHERE id = $1
-	`
-
-	var (
-		id          string
-		eventType   string
-		source      string
-		timestamp   time.Time
-		actorJSON   []byte
-		evidenceJSON []byte
-		vectorClockJSON []byte
-		metadataJSON []byte
-	)
-
-	err := c.db.QueryRowContext(ctx, query, sequenceNumber).Scan(
-		&id,
-		&eventType,
-		&source,
-		&timestamp,
-		&actorJSON,
-		&evidenceJSON,
-		&vectorClockJSON,
-		&metadataJSON,
-	)
-
-	if err == sql.ErrNoRows {
+	rec, err := c.events.GetBySequence(ctx, sequenceNumber)
+	if err == eventdb.ErrNotFound {
 		return nil, fmt.Errorf("event not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to query event: %w", err)
 	}
 
-	// Parse metadata to extract note_id and user_id
-	var metadata map[string]interface{}
-	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
-		log.Printf("[DB] Warning: failed to parse metadata: %v", err)
-		metadata = make(map[string]interface{})
-	}
-
-	var evidence map[string]interface{}
-	if err := json.Unmarshal(evidenceJSON, &evidence); err != nil {
-		log.Printf("[DB] Warning: failed to parse evidence: %v", err)
-		evidence = make(map[string]interface{})
-	}
-
-	var actor map[string]interface{}
-	if err := json.Unmarshal(actorJSON, &actor); err != nil {
-		log.Printf("[DB] Warning: failed to parse actor: %v", err)
-		actor = make(map[string]interface{})
-	}
-
-	// Extract note_id and user_id
-	var noteID *int
-	if nid, ok := evidence["note_id"].(float64); ok {
-		val := int(nid)
-		noteID = &val
-	}
-
-	userID := ""
-	if uid, ok := actor["id"].(string); ok {
-		userID = uid
-	}
-
-	return &models.EventSummary{
-		SequenceNumber: sequenceNumber,
-		EventType:      eventType,
-		TimestampVEPS:  timestamp.UnixMilli(),
-		NoteID:         noteID,
-		UserID:         userID,
-		Metadata:       metadata,
-	}, nil
+	summary := toSummary(*rec)
+	return &summary, nil
 }
 
-// BatchQuery retrieves events based on filters
+// BatchQuery retrieves sealed events based on filters
 func (c *Client) BatchQuery(ctx context.Context, req models.BatchQueryRequest) ([]models.EventSummary, int, error) {
-	// Build dynamic query
-	query := `
-		SELECT 
-			id,
-			type,
-			timestamp,
-			actor,
-			evidence,
-			metadata
-		FROM events
-		WHERE 1=1
-	`
-
-	args := []interface{}{}
-	argCount := 1
+	// Only sealed events have a sequence number to report
+	filter := eventdb.Filter{
+		SealedOnly: true,
+	}
 
-	// Add filters
 	if req.NoteID != nil {
-		query += fmt.Sprintf(" AND evidence->>'note_id' = $%d", argCount)
-		args = append(args, fmt.Sprintf("%d", *req.NoteID))
-		argCount++
+		filter.Evidence = map[string]string{"note_id": strconv.Itoa(*req.NoteID)}
 	}
-
 	if req.UserID != nil {
-		query += fmt.Sprintf(" AND actor->>'id' = $%d", argCount)
-		args = append(args, *req.UserID)
-		argCount++
+		filter.ActorID = *req.UserID
+	}
+	if req.StartSeq != nil {
+		filter.StartSeq = *req.StartSeq
+	}
+	if req.EndSeq != nil {
+		filter.EndSeq = *req.EndSeq
 	}
-
 	if req.StartTime != nil {
-		query += fmt.Sprintf(" AND timestamp >= $%d", argCount)
-		args = append(args, time.UnixMilli(*req.StartTime))
-		argCount++
+		filter.Start = time.UnixMilli(*req.StartTime)
 	}
-
 	if req.EndTime != nil {
-		query += fmt.Sprintf(" AND timestamp <= $%d", argCount)
-		args = append(args, time.UnixMilli(*req.EndTime))
-		argCount++
+		filter.End = time.UnixMilli(*req.EndTime)
 	}
 
-	// Order by timestamp
-	query += " ORDER BY timestamp DESC"
-
 	// Apply limit
-	limit := req.Limit
-	if limit <= 0 || limit > 1000 {
-		limit = 100 // Default limit
+	filter.Limit = req.Limit
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100 // Default limit
 	}
-	query += fmt.Sprintf(" LIMIT $%d", argCount)
-	args = append(args, limit)
 
-	// Execute query
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	records, err := c.events.Query(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+		return nil, 0, err
 	}
-	defer rows.Close()
-
-	// Parse results
-	events := []models.EventSummary{}
-	for rows.Next() {
-		var (
-			id           string
-			eventType    string
-			timestamp    time.Time
-			actorJSON    []byte
-			evidenceJSON []byte
-			metadataJSON []byte
-		)
-
-		if err := rows.Scan(&id, &eventType, &timestamp, &actorJSON, &evidenceJSON, &metadataJSON); err != nil {
-			log.Printf("[DB] Warning: failed to scan row: %v", err)
-			continue
-		}
 
-		var evidence map[string]interface{}
-		json.Unmarshal(evidenceJSON, &evidence)
-
-		var actor map[string]interface{}
-		json.Unmarshal(actorJSON, &actor)
+	events := make([]models.EventSummary, 0, len(records))
+	for _, rec := range records {
+		events = append(events, toSummary(rec))
+	}
 
-		var metadata map[string]interface{}
-		json.Unmarshal(metadataJSON, &metadata)
+	return events, len(events), nil
+}
 
-		// Extract note_id and user_id
-		var noteID *int
-		if nid, ok := evidence["note_id"].(float64); ok {
-			val := int(nid)
-			noteID = &val
-		}
+// toSummary converts a stored event into its client-facing summary
+func toSummary(rec eventdb.Record) models.EventSummary {
+	summary := models.EventSummary{
+		EventID:       rec.ID,
+		EventType:     rec.Type,
+		TimestampVEPS: rec.Timestamp.UnixMilli(),
+		UserID:        rec.ActorID,
+	}
 
-		userID := ""
-		if uid, ok := actor["id"].(string); ok {
-			userID = uid
-		}
+	if rec.Seal != nil {
+		summary.SequenceNumber = rec.Seal.SequenceNumber
+		summary.ProofHash = rec.Seal.EventHash
+	}
 
-		// Parse sequence number from ID (assuming it's stored as string)
-		var seqNum uint64
-		fmt.Sscanf(id, "%d", &seqNum)
+	// JSON numbers come back from JSONB as float64
+	if nid, ok := rec.Evidence["note_id"].(float64); ok {
+		val := int(nid)
+		summary.NoteID = &val
+	}
 
-		events = append(events, models.EventSummary{
-			SequenceNumber: seqNum,
-			EventType:      eventType,
-			TimestampVEPS:  timestamp.UnixMilli(),
-			NoteID:         noteID,
-			UserID:         userID,
-			Metadata:       metadata,
-		})
+	// Client metadata is carried in the evidence by SubmitEvent
+	if metadata, ok := rec.Evidence["metadata"].(map[string]interface{}); ok {
+		summary.Metadata = metadata
 	}
 
-	return events, len(events), nil
+	return summary
 }
 
 // CompareCausality compares two events by sequence number
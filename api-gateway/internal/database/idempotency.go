@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/veps-service-480701/api-gateway/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+// Outcomes of a claim on an idempotency key
+const (
+	ClaimNew      = eventdb.ClaimNew
+	ClaimReplay   = eventdb.ClaimReplay
+	ClaimInFlight = eventdb.ClaimInFlight
+	ClaimMismatch = eventdb.ClaimMismatch
+)
+
+// ClaimIdempotencyKey claims a client's key for a synchronous submission
+// The stored response is returned for ClaimReplay
+func (c *Client) ClaimIdempotencyKey(ctx context.Context, clientID, key, fingerprint string, ttl, staleAfter time.Duration) (string, *models.ClientEventResponse, error) {
+	outcome, rec, err := c.events.ClaimIdempotencyKey(ctx, clientID, key, fingerprint, ttl, staleAfter)
+	if err != nil || outcome != ClaimReplay {
+		return outcome, nil, err
+	}
+
+	var response models.ClientEventResponse
+	if err := json.Unmarshal(rec.Response, &response); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal stored response: %w", err)
+	}
+	return outcome, &response, nil
+}
+
+// CompleteIdempotencyKey stores the response of a synchronous submission
+func (c *Client) CompleteIdempotencyKey(ctx context.Context, clientID, key string, response models.ClientEventResponse) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return c.events.CompleteIdempotencyKey(ctx, clientID, key, body)
+}
+
+// ReleaseIdempotencyKey forgets a synchronous submission that failed
+func (c *Client) ReleaseIdempotencyKey(ctx context.Context, clientID, key string) error {
+	return c.events.ReleaseIdempotencyKey(ctx, clientID, key)
+}
+
+// CreateKeyedSubmission records a pending asynchronous submission under a
+// client's idempotency key; for ClaimReplay the submission the key already
+// names is returned instead
+func (c *Client) CreateKeyedSubmission(ctx context.Context, id, clientID, callbackURL, key, fingerprint string, ttl time.Duration) (string, *models.Submission, error) {
+	outcome, rec, err := c.events.CreateKeyedSubmission(ctx, eventdb.SubmissionRecord{
+		ID:          id,
+		ClientID:    clientID,
+		CallbackURL: callbackURL,
+	}, key, fingerprint, ttl)
+	if err != nil || rec == nil {
+		return outcome, nil, err
+	}
+
+	submission := toSubmission(*rec)
+	return outcome, &submission, nil
+}
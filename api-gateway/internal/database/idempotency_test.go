@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+func newMockClient(t *testing.T) (*Client, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Client{db: db, events: eventdb.New(db)}, mock
+}
+
+var idempotencyKeyRow = []string{"client_id", "key", "fingerprint", "submission_id", "response", "created_at", "expires_at"}
+
+var submissionRow = []string{
+	"id", "client_id", "state", "event_id",
+	"sequence_number", "event_hash", "previous_hash", "vector_clock",
+	"veto_reasons", "failed_checks", "error",
+	"callback_url", "callback_attempts", "callback_delivered_at", "callback_error",
+	"created_at", "completed_at",
+}
+
+func keyRows(fingerprint string, submissionID, response interface{}) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(idempotencyKeyRow).
+		AddRow("client-1", "key-1", fingerprint, submissionID, response, now, now.Add(time.Hour))
+}
+
+func submissionRows(id, state string) *sqlmock.Rows {
+	return sqlmock.NewRows(submissionRow).
+		AddRow(id, "client-1", state, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil, time.Now(), nil)
+}
+
+func TestClaimIdempotencyKeyOutcomes(t *testing.T) {
+	stored := []byte(`{"sequence_number": 42, "event_id": "event-1", "proof_hash": "abc"}`)
+
+	tests := []struct {
+		name     string
+		setup    func(mock sqlmock.Sqlmock)
+		outcome  string
+		sequence uint64 // of the replayed response, 0 for none
+	}{
+		{
+			name: "unseen or abandoned key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+					WithArgs("client-1", "key-1", "fp-1", float64(3600), float64(60)).
+					WillReturnRows(keyRows("fp-1", nil, nil))
+			},
+			outcome: ClaimNew,
+		},
+		{
+			name: "answered key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WithArgs("client-1", "key-1").
+					WillReturnRows(keyRows("fp-1", nil, stored))
+			},
+			outcome:  ClaimReplay,
+			sequence: 42,
+		},
+		{
+			name: "key in flight",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-1", nil, nil))
+			},
+			outcome: ClaimInFlight,
+		},
+		{
+			name: "key released meanwhile",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnError(sql.ErrNoRows)
+			},
+			outcome: ClaimInFlight,
+		},
+		{
+			name: "key used for other content",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-2", nil, stored))
+			},
+			outcome: ClaimMismatch,
+		},
+		{
+			name: "key of an asynchronous submission",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-1", "submission-1", nil))
+			},
+			outcome: ClaimMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mock := newMockClient(t)
+			tt.setup(mock)
+
+			outcome, response, err := c.ClaimIdempotencyKey(context.Background(), "client-1", "key-1", "fp-1", time.Hour, time.Minute)
+			if err != nil {
+				t.Fatalf("ClaimIdempotencyKey: %v", err)
+			}
+			if outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q", outcome, tt.outcome)
+			}
+			switch {
+			case tt.sequence == 0 && response != nil:
+				t.Errorf("response = %+v, want none", response)
+			case tt.sequence != 0 && (response == nil || response.SequenceNumber != tt.sequence):
+				t.Errorf("response = %+v, want sequence %d", response, tt.sequence)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestClaimIdempotencyKeyRejectsCorruptResponse(t *testing.T) {
+	c, mock := newMockClient(t)
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-1", nil, []byte(`{not json`)))
+
+	if _, _, err := c.ClaimIdempotencyKey(context.Background(), "client-1", "key-1", "fp-1", time.Hour, time.Minute); err == nil {
+		t.Error("ClaimIdempotencyKey replayed a corrupt response")
+	}
+}
+
+func TestCreateKeyedSubmissionOutcomes(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(mock sqlmock.Sqlmock)
+		outcome    string
+		submission string // ID of the returned submission, empty for none
+	}{
+		{
+			name: "unseen key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO submissions`).WithArgs("submission-2", "client-1", nil).
+					WillReturnRows(submissionRows("submission-2", eventdb.SubmissionPending))
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+					WithArgs("client-1", "key-1", "fp-1", "submission-2", float64(3600)).
+					WillReturnRows(sqlmock.NewRows([]string{"submission_id"}).AddRow("submission-2"))
+				mock.ExpectCommit()
+			},
+			outcome:    ClaimNew,
+			submission: "submission-2",
+		},
+		{
+			name: "key naming a submission",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO submissions`).WillReturnRows(submissionRows("submission-2", eventdb.SubmissionPending))
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-1", "submission-1", nil))
+				mock.ExpectQuery(`SELECT .* FROM submissions WHERE id = \$1`).WithArgs("submission-1").
+					WillReturnRows(submissionRows("submission-1", eventdb.SubmissionSealed))
+				mock.ExpectRollback()
+			},
+			outcome:    ClaimReplay,
+			submission: "submission-1",
+		},
+		{
+			name: "key used for other content",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO submissions`).WillReturnRows(submissionRows("submission-2", eventdb.SubmissionPending))
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-2", "submission-1", nil))
+				mock.ExpectRollback()
+			},
+			outcome: ClaimMismatch,
+		},
+		{
+			name: "key of a synchronous submission",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO submissions`).WillReturnRows(submissionRows("submission-2", eventdb.SubmissionPending))
+				mock.ExpectQuery(`INSERT INTO idempotency_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`SELECT .* FROM idempotency_keys`).WillReturnRows(keyRows("fp-1", nil, []byte(`{}`)))
+				mock.ExpectRollback()
+			},
+			outcome: ClaimMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mock := newMockClient(t)
+			mock.ExpectBegin()
+			tt.setup(mock)
+
+			outcome, submission, err := c.CreateKeyedSubmission(context.Background(), "submission-2", "client-1", "", "key-1", "fp-1", time.Hour)
+			if err != nil {
+				t.Fatalf("CreateKeyedSubmission: %v", err)
+			}
+			if outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q", outcome, tt.outcome)
+			}
+			switch {
+			case tt.submission == "" && submission != nil:
+				t.Errorf("submission = %+v, want none", submission)
+			case tt.submission != "" && (submission == nil || submission.SubmissionID != tt.submission):
+				t.Errorf("submission = %+v, want %s", submission, tt.submission)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
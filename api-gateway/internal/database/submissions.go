@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/veps-service-480701/api-gateway/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+// Submission states
+const (
+	SubmissionPending = eventdb.SubmissionPending
+	SubmissionSealed  = eventdb.SubmissionSealed
+	SubmissionVetoed  = eventdb.SubmissionVetoed
+	SubmissionFailed  = eventdb.SubmissionFailed
+)
+
+// ErrSubmissionNotFound is returned when no submission matches the lookup
+var ErrSubmissionNotFound = eventdb.ErrSubmissionNotFound
+
+// CreateSubmission records a pending asynchronous submission
+func (c *Client) CreateSubmission(ctx context.Context, id, clientID, callbackURL string) (*models.Submission, error) {
+	rec, err := c.events.CreateSubmission(ctx, eventdb.SubmissionRecord{
+		ID:          id,
+		ClientID:    clientID,
+		CallbackURL: callbackURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submission := toSubmission(*rec)
+	return &submission, nil
+}
+
+// CompleteSubmission records the outcome of a pending submission
+func (c *Client) CompleteSubmission(ctx context.Context, outcome models.Submission) (*models.Submission, error) {
+	rec := eventdb.SubmissionRecord{
+		ID:           outcome.SubmissionID,
+		State:        outcome.State,
+		EventID:      outcome.EventID,
+		VectorClock:  outcome.VectorClock,
+		VetoReasons:  outcome.VetoReasons,
+		FailedChecks: outcome.FailedChecks,
+		Error:        outcome.Error,
+	}
+	if outcome.State == SubmissionSealed {
+		rec.Seal = &eventdb.Seal{
+			SequenceNumber: outcome.SequenceNumber,
+			EventHash:      outcome.ProofHash,
+			PreviousHash:   outcome.PreviousHash,
+		}
+	}
+
+	stored, err := c.events.CompleteSubmission(ctx, rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete submission: %w", err)
+	}
+
+	submission := toSubmission(*stored)
+	return &submission, nil
+}
+
+// FailStaleSubmissions completes up to limit submissions pending for longer
+// than maxAge as failed, and returns them
+func (c *Client) FailStaleSubmissions(ctx context.Context, maxAge time.Duration, submissionErr string, limit int) ([]models.Submission, error) {
+	recs, err := c.events.FailStaleSubmissions(ctx, time.Now().Add(-maxAge), submissionErr, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := make([]models.Submission, 0, len(recs))
+	for _, rec := range recs {
+		submissions = append(submissions, toSubmission(rec))
+	}
+	return submissions, nil
+}
+
+// RecordCallback records a callback delivery; a zero deliveredAt means it failed
+func (c *Client) RecordCallback(ctx context.Context, id string, attempts int, deliveredAt time.Time, callbackErr string) error {
+	return c.events.RecordCallback(ctx, id, attempts, deliveredAt, callbackErr)
+}
+
+// GetSubmission retrieves a submission by ID
+func (c *Client) GetSubmission(ctx context.Context, id string) (*models.Submission, error) {
+	rec, err := c.events.GetSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := toSubmission(*rec)
+	return &submission, nil
+}
+
+// StartSubmissionPurge deletes submissions older than retention, and expired
+// idempotency keys, every hour
+func (c *Client) StartSubmissionPurge(retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			deleted, err := c.events.DeleteSubmissionsBefore(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("[DB] Warning: failed to purge submissions: %v", err)
+			} else if deleted > 0 {
+				log.Printf("[DB] Purged %d submissions older than %s", deleted, retention)
+			}
+
+			expired, err := c.events.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				log.Printf("[DB] Warning: failed to purge idempotency keys: %v", err)
+			} else if expired > 0 {
+				log.Printf("[DB] Purged %d expired idempotency keys", expired)
+			}
+			cancel()
+		}
+	}()
+}
+
+// toSubmission converts a stored submission into its client-facing form
+func toSubmission(rec eventdb.SubmissionRecord) models.Submission {
+	submission := models.Submission{
+		SubmissionID: rec.ID,
+		ClientID:     rec.ClientID,
+		State:        rec.State,
+		EventID:      rec.EventID,
+		VectorClock:  rec.VectorClock,
+		VetoReasons:  rec.VetoReasons,
+		FailedChecks: rec.FailedChecks,
+		Error:        rec.Error,
+		SubmittedAt:  rec.CreatedAt.UnixMilli(),
+	}
+
+	if rec.Seal != nil {
+		submission.SequenceNumber = rec.Seal.SequenceNumber
+		submission.ProofHash = rec.Seal.EventHash
+		submission.PreviousHash = rec.Seal.PreviousHash
+	}
+	if !rec.CompletedAt.IsZero() {
+		submission.CompletedAt = rec.CompletedAt.UnixMilli()
+	}
+
+	if rec.CallbackURL != "" {
+		submission.Callback = &models.SubmissionCallback{
+			URL:       rec.CallbackURL,
+			Attempts:  rec.CallbackAttempts,
+			Delivered: !rec.CallbackDeliveredAt.IsZero(),
+			Error:     rec.CallbackError,
+		}
+		if submission.Callback.Delivered {
+			submission.Callback.DeliveredAt = rec.CallbackDeliveredAt.UnixMilli()
+		}
+	}
+
+	return submission
+}
@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/api-gateway/internal/database"
+	"github.com/veps-service-480701/api-gateway/internal/idempotency"
+	"github.com/veps-service-480701/api-gateway/pkg/models"
+)
+
+const (
+	// submitTimeout bounds one pass of an event through the VEPS pipeline
+	submitTimeout = 10 * time.Second
+
+	// submissionsPath is where asynchronous submissions are polled
+	submissionsPath = "/api/v1/submissions/"
+
+	// staleSubmissionAge is how long a submission may stay pending before its
+	// processing is assumed lost, e.g. to a restart of the instance running it
+	staleSubmissionAge = 5 * time.Minute
+	// staleSubmissionError is recorded for a submission whose processing was lost
+	staleSubmissionError = "processing was interrupted before its outcome was recorded; resubmit with the same Idempotency-Key to recover the event"
+	// sweepInterval is how often stale submissions are looked for
+	sweepInterval = time.Minute
+	// sweepBatch bounds the stale submissions completed by one sweep
+	sweepBatch = 100
+
+	// maxCallbackURLLength bounds the callback_url parameter
+	maxCallbackURLLength = 2048
+	// callbackAttempts is how many times a callback is sent before giving up
+	callbackAttempts = 3
+	// callbackTimeout bounds a single callback request
+	callbackTimeout = 5 * time.Second
+)
+
+// submitAsync records a pending submission, answers 202 and submits the event
+// in the background
+func (h *Handler) submitAsync(w http.ResponseWriter, r *http.Request, clientReq models.ClientEventRequest, clientID, idempotencyKey string) {
+	callbackURL := r.URL.Query().Get("callback_url")
+	if callbackURL != "" {
+		if err := validateCallbackURL(callbackURL); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	boundaryKey := ""
+	if idempotencyKey != "" {
+		boundaryKey = clientID + ":" + idempotencyKey
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	// A retried request with the same key gets the submission of the first
+	var (
+		outcome    = idempotency.New
+		submission *models.Submission
+		err        error
+	)
+	if idempotencyKey != "" && h.idempotency.Enabled() {
+		outcome, submission, err = h.idempotency.BeginAsync(ctx, uuid.New().String(), clientID, callbackURL,
+			idempotencyKey, idempotency.AsyncFingerprint(clientReq, callbackURL))
+	} else {
+		submission, err = h.dbClient.CreateSubmission(ctx, uuid.New().String(), clientID, callbackURL)
+	}
+	if err != nil {
+		log.Printf("[Gateway] Failed to record submission: %v", err)
+		h.writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("asynchronous submission is unavailable: %v", err))
+		return
+	}
+
+	switch outcome {
+	case idempotency.Mismatch:
+		h.writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different event", idempotencyKeyHeader))
+		return
+	case idempotency.Replay:
+		log.Printf("[Gateway] Replaying submission %s for idempotency key from client %s", submission.SubmissionID, clientID)
+		w.Header().Set(replayedHeader, "true")
+	default:
+		log.Printf("[Gateway] Accepted submission %s: type=%s, user=%s", submission.SubmissionID, clientReq.EventType, clientReq.UserID)
+
+		h.submissions.Add(1)
+		go h.processSubmission(*submission, clientReq, boundaryKey)
+	}
+
+	statusURL := submissionsPath + submission.SubmissionID
+	w.Header().Set("Location", statusURL)
+	h.writeJSON(w, http.StatusAccepted, models.StandardResponse{
+		Success: true,
+		Message: "Event accepted for submission",
+		Data: models.SubmissionAccepted{
+			SubmissionID: submission.SubmissionID,
+			State:        submission.State,
+			StatusURL:    statusURL,
+		},
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// processSubmission submits an accepted event and records the outcome
+func (h *Handler) processSubmission(submission models.Submission, clientReq models.ClientEventRequest, boundaryKey string) {
+	defer h.submissions.Done()
+
+	// The request that accepted the submission is already answered
+	ctx, cancel := context.WithTimeout(context.Background(), submitTimeout)
+	defer cancel()
+
+	outcome := models.Submission{SubmissionID: submission.SubmissionID}
+
+	clientResp, err := h.submit(ctx, clientReq, boundaryKey)
+	if err == nil {
+		outcome.State = database.SubmissionSealed
+		outcome.EventID = clientResp.EventID
+		outcome.SequenceNumber = clientResp.SequenceNumber
+		outcome.VectorClock = clientResp.VectorClock
+		outcome.ProofHash = clientResp.ProofHash
+		outcome.PreviousHash = clientResp.PreviousHash
+	} else if veto, ok := vetoOf(err); ok {
+		outcome.State = database.SubmissionVetoed
+		outcome.EventID = veto.EventID
+		outcome.VetoReasons = veto.Data.Reasons
+		outcome.FailedChecks = veto.Data.FailedChecks
+		outcome.Error = veto.Error
+	} else {
+		outcome.State = database.SubmissionFailed
+		outcome.Error = err.Error()
+	}
+
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer recordCancel()
+
+	completed, err := h.dbClient.CompleteSubmission(recordCtx, outcome)
+	if err != nil {
+		log.Printf("[Gateway] Failed to record outcome of submission %s (%s): %v", outcome.SubmissionID, outcome.State, err)
+		return
+	}
+
+	log.Printf("[Gateway] Submission %s %s (event: %s)", completed.SubmissionID, completed.State, completed.EventID)
+
+	if completed.Callback != nil {
+		h.deliverCallback(*completed)
+	}
+}
+
+// deliverCallback POSTs a completed submission to its callback URL, retrying
+// with backoff, and records the delivery
+func (h *Handler) deliverCallback(submission models.Submission) {
+	callbackURL := submission.Callback.URL
+
+	// The payload is the submission as polled, without the delivery details
+	payload := submission
+	payload.Callback = nil
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[Gateway] Failed to marshal callback for submission %s: %v", submission.SubmissionID, err)
+		return
+	}
+
+	var (
+		attempts    int
+		deliveredAt time.Time
+		lastErr     error
+	)
+	for attempts < callbackAttempts {
+		if attempts > 0 {
+			time.Sleep(time.Duration(1<<(attempts-1)) * time.Second)
+		}
+		attempts++
+
+		if lastErr = h.postCallback(callbackURL, body); lastErr == nil {
+			deliveredAt = time.Now().UTC()
+			break
+		}
+		log.Printf("[Gateway] Callback for submission %s failed (attempt %d/%d): %v",
+			submission.SubmissionID, attempts, callbackAttempts, lastErr)
+	}
+
+	callbackErr := ""
+	if lastErr != nil {
+		callbackErr = lastErr.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.dbClient.RecordCallback(ctx, submission.SubmissionID, attempts, deliveredAt, callbackErr); err != nil {
+		log.Printf("[Gateway] Failed to record callback for submission %s: %v", submission.SubmissionID, err)
+	}
+}
+
+// postCallback sends one callback request; any 2xx answer counts as delivered
+func (h *Handler) postCallback(callbackURL string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.callbackClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call callback URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback URL returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetSubmission handles GET /api/v1/submissions/{id}
+func (h *Handler) GetSubmission(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, submissionsPath)
+	if _, err := uuid.Parse(id); err != nil {
+		h.writeError(w, http.StatusBadRequest, "submission ID must be a UUID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	submission, err := h.dbClient.GetSubmission(ctx, id)
+	if errors.Is(err, database.ErrSubmissionNotFound) {
+		h.writeError(w, http.StatusNotFound, "submission not found")
+		return
+	}
+	if err != nil {
+		log.Printf("[Gateway] Failed to get submission %s: %v", id, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get submission: %v", err))
+		return
+	}
+
+	// Submissions are private to the client that made them
+	clientID, _ := r.Context().Value("client_id").(string)
+	if submission.ClientID != clientID {
+		h.writeError(w, http.StatusNotFound, "submission not found")
+		return
+	}
+
+	if submission.State == database.SubmissionPending &&
+		time.Since(time.UnixMilli(submission.SubmittedAt)) > staleSubmissionAge {
+		// The instance running it went away before recording the outcome
+		submission.State = database.SubmissionFailed
+		// The sweeper has not completed it yet
+		submission.Error = staleSubmissionError
+	}
+
+	h.writeJSON(w, http.StatusOK, models.StandardResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Submission is %s", submission.State),
+		Data:      submission,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// StartSubmissionSweeper completes submissions whose processing was lost as
+// failed, every minute until ctx is done, and delivers their callbacks
+func (h *Handler) StartSubmissionSweeper(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.sweepSubmissions()
+			}
+		}
+	}()
+}
+
+// sweepSubmissions completes the stale submissions found by one sweep
+func (h *Handler) sweepSubmissions() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	failed, err := h.dbClient.FailStaleSubmissions(ctx, staleSubmissionAge, staleSubmissionError, sweepBatch)
+	if err != nil {
+		log.Printf("[Gateway] Warning: failed to sweep stale submissions: %v", err)
+		return
+	}
+
+	for _, submission := range failed {
+		log.Printf("[Gateway] Submission %s failed: pending for over %s", submission.SubmissionID, staleSubmissionAge)
+		if submission.Callback == nil {
+			continue
+		}
+
+		h.submissions.Add(1)
+		go func() {
+			defer h.submissions.Done()
+			h.deliverCallback(submission)
+		}()
+	}
+}
+
+// WaitForSubmissions blocks until running asynchronous submissions finish or
+// ctx is done
+func (h *Handler) WaitForSubmissions(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.submissions.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// vetoOf returns the Boundary Adapter's veto details if err is a veto
+func vetoOf(err error) (*models.BoundaryVeto, bool) {
+	var boundaryErr *boundaryError
+	if !errors.As(err, &boundaryErr) || boundaryErr.statusCode != http.StatusPreconditionFailed {
+		return nil, false
+	}
+
+	var veto models.BoundaryVeto
+	if err := json.Unmarshal([]byte(boundaryErr.body), &veto); err != nil {
+		return nil, false
+	}
+	return &veto, true
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// blockedCallbackPrefixes are ranges a callback may not reach besides the
+// private, loopback, link-local, multicast and unspecified ones
+var blockedCallbackPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which maps to IPv4 addresses
+}
+
+// errBlockedAddress is returned for callbacks to internal addresses
+var errBlockedAddress = errors.New("callback address is not publicly routable")
+
+// newCallbackClient returns the client that delivers callbacks
+// Callback URLs come from clients, so the client only connects to public
+// addresses, checked at dial time after DNS resolution, goes through no
+// proxy, and does not follow redirects
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: callbackTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("invalid callback address %q: %w", address, err)
+			}
+			if !publicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: callbackTimeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: callbackTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// A redirect could lead anywhere; the 3xx counts as a failed delivery
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicAddress reports whether a callback may connect to an address
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedCallbackPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCallbackURL checks that a callback URL is an absolute HTTPS URL
+// A host given as an IP address must be public; hostnames are checked when
+// the callback is sent
+func validateCallbackURL(callbackURL string) error {
+	if len(callbackURL) > maxCallbackURLLength {
+		return fmt.Errorf("callback_url must be at most %d characters", maxCallbackURLLength)
+	}
+
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return errors.New("callback_url must be an absolute https URL")
+	}
+	if parsed.User != nil {
+		return errors.New("callback_url must not contain credentials")
+	}
+	if addr, err := netip.ParseAddr(parsed.Hostname()); err == nil && !publicAddress(addr) {
+		return errors.New("callback_url must not point to a private, loopback or link-local address")
+	}
+	return nil
+}
@@ -4,31 +4,66 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/veps-service-480701/api-gateway/internal/database"
+	"github.com/veps-service-480701/api-gateway/internal/idempotency"
+	"github.com/veps-service-480701/api-gateway/internal/veto"
 	"github.com/veps-service-480701/api-gateway/pkg/models"
+	"github.com/veps-service-480701/veps-common/model"
+)
+
+// clientSource is the source recorded for events submitted through the gateway
+const clientSource = "second-brain"
+
+const (
+	// idempotencyKeyHeader lets a client retry a submission without sealing
+	// the event twice
+	idempotencyKeyHeader = "Idempotency-Key"
+	// replayedHeader marks a response replayed from an earlier submission
+	replayedHeader = "Idempotent-Replayed"
 )
 
 // Handler manages API Gateway HTTP requests
 type Handler struct {
 	boundaryURL string
 	dbClient    *database.Client
+	vetoClient  *veto.Client // nil when VETO_SERVICE_URL is not set
+	idempotency *idempotency.Store
+
+	callbackClient *http.Client
+	submissions    sync.WaitGroup // asynchronous submissions still running
 }
 
 // New creates a new API Gateway handler
-func New(boundaryURL string, dbClient *database.Client) *Handler {
+func New(boundaryURL string, dbClient *database.Client, vetoClient *veto.Client, idempotencyStore *idempotency.Store) *Handler {
 	return &Handler{
 		boundaryURL: boundaryURL,
 		dbClient:    dbClient,
+		vetoClient:  vetoClient,
+		idempotency: idempotencyStore,
+
+		callbackClient: newCallbackClient(),
 	}
 }
 
+// boundaryError is a non-200 answer from the Boundary Adapter
+type boundaryError struct {
+	statusCode int
+	body       string
+}
+
+func (e *boundaryError) Error() string {
+	return fmt.Sprintf("boundary adapter returned error: %s", e.body)
+}
+
 // SubmitEvent handles POST /api/v1/events
 func (h *Handler) SubmitEvent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -54,12 +89,112 @@ func (h *Handler) SubmitEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("[Gateway] Submitting event: type=%s, user=%s, note=%d", 
+	// Idempotency keys are scoped to the authenticated client
+	clientID, _ := r.Context().Value("client_id").(string)
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > idempotency.MaxKeyLength {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, idempotency.MaxKeyLength))
+		return
+	}
+
+	// ?mode=async answers 202 at once; the outcome is polled or called back
+	switch r.URL.Query().Get("mode") {
+	case "", "sync":
+	case "async":
+		h.submitAsync(w, r, clientReq, clientID, idempotencyKey)
+		return
+	default:
+		h.writeError(w, http.StatusBadRequest, "mode must be sync or async")
+		return
+	}
+
+	if idempotencyKey != "" {
+		outcome, stored, err := h.idempotency.Begin(r.Context(), clientID, idempotencyKey, idempotency.Fingerprint(clientReq))
+		if err != nil {
+			log.Printf("[Gateway] Failed to claim idempotency key for client %s: %v", clientID, err)
+			h.writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("idempotency keys are unavailable: %v", err))
+			return
+		}
+		switch outcome {
+		case idempotency.Replay:
+			log.Printf("[Gateway] Replaying event %s for idempotency key from client %s", stored.EventID, clientID)
+			w.Header().Set(replayedHeader, "true")
+			h.writeJSON(w, http.StatusOK, models.StandardResponse{
+				Success:   true,
+				Message:   "Event submitted successfully",
+				Data:      *stored,
+				Timestamp: time.Now().UTC(),
+			})
+			return
+		case idempotency.InFlight:
+			h.writeError(w, http.StatusConflict, "a request with the same idempotency key is still being processed")
+			return
+		case idempotency.Mismatch:
+			h.writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different event", idempotencyKeyHeader))
+			return
+		}
+
+		// A failed submission forgets the key so the client can retry it
+		defer h.releaseKey(r.Context(), clientID, idempotencyKey)
+	}
+
+	// The Boundary Adapter deduplicates the key too, so a retry that reaches
+	// another gateway instance still gets the original event
+	boundaryKey := ""
+	if idempotencyKey != "" {
+		boundaryKey = clientID + ":" + idempotencyKey
+	}
+
+	// Call Boundary Adapter
+	ctx, cancel := context.WithTimeout(r.Context(), submitTimeout)
+	defer cancel()
+
+	clientResp, err := h.submit(ctx, clientReq, boundaryKey)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		var boundaryErr *boundaryError
+		if errors.As(err, &boundaryErr) &&
+			(boundaryErr.statusCode == http.StatusConflict || boundaryErr.statusCode == http.StatusUnprocessableEntity) {
+			statusCode = boundaryErr.statusCode
+		}
+		h.writeError(w, statusCode, fmt.Sprintf("failed to process event: %v", err))
+		return
+	}
+
+	if idempotencyKey != "" {
+		// Stored even if the client has gone away, for it to retry
+		if err := h.idempotency.Complete(context.WithoutCancel(r.Context()), clientID, idempotencyKey, *clientResp); err != nil {
+			log.Printf("[Gateway] Warning: failed to store response for idempotency key from client %s: %v", clientID, err)
+		}
+	}
+
+	log.Printf("[Gateway] Event submitted successfully: id=%s, seq=%d", 
+		clientResp.EventID, clientResp.SequenceNumber)
+
+	h.writeJSON(w, http.StatusOK, models.StandardResponse{
+		Success:   true,
+		Message:   "Event submitted successfully",
+		Data:      *clientResp,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// releaseKey forgets the key of a synchronous submission unless it completed
+func (h *Handler) releaseKey(ctx context.Context, clientID, key string) {
+	if err := h.idempotency.Release(context.WithoutCancel(ctx), clientID, key); err != nil {
+		log.Printf("[Gateway] Warning: failed to release idempotency key from client %s: %v", clientID, err)
+	}
+}
+
+// submit runs an event through the VEPS pipeline via the Boundary Adapter and
+// returns the ledger receipt
+func (h *Handler) submit(ctx context.Context, clientReq models.ClientEventRequest, boundaryKey string) (*models.ClientEventResponse, error) {
+	log.Printf("[Gateway] Submitting event: type=%s, user=%s, note=%d",
 		clientReq.EventType, clientReq.UserID, clientReq.NoteID)
 
 	// Transform to VEPS format (Boundary Adapter format)
 	boundaryEvent := models.BoundaryEvent{
-		Source: "second-brain",
+		Source: clientSource,
 		Data: map[string]interface{}{
 			"type": clientReq.EventType,
 			"actor": map[string]interface{}{
@@ -67,61 +202,122 @@ func (h *Handler) SubmitEvent(w http.ResponseWriter, r *http.Request) {
 				"name": clientReq.UserID,
 				"type": "user",
 			},
-			"evidence": map[string]interface{}{
-				"note_id":          clientReq.NoteID,
-				"bpm":              clientReq.BPM,
-				"duration_ms":      clientReq.DurationMS,
-				"timestamp_client": clientReq.TimestampClient,
-				"metadata":         clientReq.Metadata,
-			},
+			"evidence": clientEvidence(clientReq),
 		},
 	}
 
-	// Call Boundary Adapter
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-
-	boundaryResp, err := h.callBoundaryAdapter(ctx, boundaryEvent)
+	boundaryResp, err := h.callBoundaryAdapter(ctx, boundaryEvent, boundaryKey)
 	if err != nil {
 		log.Printf("[Gateway] Failed to call Boundary Adapter: %v", err)
-		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to process event: %v", err))
-		return
+		return nil, err
 	}
 
 	if !boundaryResp.Success {
-		h.writeError(w, http.StatusInternalServerError, boundaryResp.Message)
-		return
+		return nil, errors.New(boundaryResp.Message)
 	}
 
-	// Build client response
-	// Note: sequence_number will be 0 until we integrate with Monolith Submitter
-	// For now, using vector clock counter as sequence
-	sequenceNumber := uint64(0)
-	for _, counter := range boundaryResp.VectorClock {
-		if uint64(counter) > sequenceNumber {
-			sequenceNumber = uint64(counter)
-		}
+	// The Boundary Adapter only answers 200 once the ledger has sealed the event
+	seal := boundaryResp.Data.Seal
+	if seal == nil {
+		log.Printf("[Gateway] Boundary Adapter returned no seal for event %s", boundaryResp.EventID)
+		return nil, errors.New("event was not sealed by the ledger")
 	}
 
-	clientResp := models.ClientEventResponse{
-		SequenceNumber: sequenceNumber,
-		VectorClock:    boundaryResp.VectorClock,
-		ProofHash:      "", // TODO: Add when Monolith Submitter integration complete
+	vectorClock := boundaryResp.VectorClock
+	if vectorClock == nil {
+		vectorClock = boundaryResp.Data.Event.VectorClock
+	}
+
+	// Build client response from the ledger receipt
+	return &models.ClientEventResponse{
+		SequenceNumber: seal.SequenceNumber,
+		VectorClock:    vectorClock,
+		ProofHash:      seal.EventHash,
+		PreviousHash:   seal.PreviousHash,
 		TimestampVEPS:  boundaryResp.Timestamp.UnixMilli(),
 		EventID:        boundaryResp.EventID,
+	}, nil
+}
+
+// ExplainEvent handles POST /api/v1/events/explain
+// It shows how the Veto Service would judge an event without submitting it
+func (h *Handler) ExplainEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
 	}
 
-	log.Printf("[Gateway] Event submitted successfully: id=%s, seq=%d", 
-		clientResp.EventID, clientResp.SequenceNumber)
+	if h.vetoClient == nil {
+		h.writeError(w, http.StatusServiceUnavailable, "event explain is not configured (VETO_SERVICE_URL is not set)")
+		return
+	}
+
+	var clientReq models.ClientEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&clientReq); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	if clientReq.EventType == "" {
+		h.writeError(w, http.StatusBadRequest, "event_type is required")
+		return
+	}
+	if clientReq.UserID == "" {
+		h.writeError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	// Build the event the Boundary Adapter would produce from this request
+	event := model.Event{
+		Type:   clientReq.EventType,
+		Source: clientSource,
+		Actor: model.Actor{
+			ID:   clientReq.UserID,
+			Name: clientReq.UserID,
+			Type: "user",
+		},
+		Evidence: clientEvidence(clientReq),
+	}
+
+	log.Printf("[Gateway] Explaining event: type=%s, user=%s", clientReq.EventType, clientReq.UserID)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	explanation, err := h.vetoClient.Explain(ctx, event)
+	if err != nil {
+		log.Printf("[Gateway] Failed to explain event: %v", err)
+		h.writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to explain event: %v", err))
+		return
+	}
+
+	// Clients can name any user, so what the veto read about the actor stays here
+	if err := explanation.Redact(); err != nil {
+		log.Printf("[Gateway] Failed to redact explanation: %v", err)
+		h.writeError(w, http.StatusBadGateway, "failed to explain event")
+		return
+	}
 
 	h.writeJSON(w, http.StatusOK, models.StandardResponse{
 		Success:   true,
-		Message:   "Event submitted successfully",
-		Data:      clientResp,
+		Message:   explanation.Message,
+		Data:      explanation.Data,
 		Timestamp: time.Now().UTC(),
 	})
 }
 
+// clientEvidence maps a client request to the event's evidence
+func clientEvidence(clientReq models.ClientEventRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"note_id":          clientReq.NoteID,
+		"bpm":              clientReq.BPM,
+		"duration_ms":      clientReq.DurationMS,
+		"timestamp_client": clientReq.TimestampClient,
+		"metadata":         clientReq.Metadata,
+	}
+}
+
 // CheckCausality handles GET /api/v1/causality
 func (h *Handler) CheckCausality(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -302,12 +498,14 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 			"gateway_ready":  true,
 			"database_healthy": dbHealthy,
 			"boundary_url":   h.boundaryURL,
+			"idempotency":    h.idempotency.Stats(),
 		},
 	})
 }
 
 // callBoundaryAdapter calls the Boundary Adapter to ingest an event
-func (h *Handler) callBoundaryAdapter(ctx context.Context, event models.BoundaryEvent) (*models.BoundaryResponse, error) {
+// A non-empty idempotency key is forwarded so the adapter deduplicates retries
+func (h *Handler) callBoundaryAdapter(ctx context.Context, event models.BoundaryEvent, idempotencyKey string) (*models.BoundaryResponse, error) {
 	// Serialize event
 	body, err := json.Marshal(event)
 	if err != nil {
@@ -320,6 +518,9 @@ func (h *Handler) callBoundaryAdapter(ctx context.Context, event models.Boundary
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
 
 	// Execute request
 	client := &http.Client{Timeout: 10 * time.Second}
@@ -336,7 +537,7 @@ func (h *Handler) callBoundaryAdapter(ctx context.Context, event models.Boundary
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("boundary adapter returned error: %s", string(respBody))
+		return nil, &boundaryError{statusCode: resp.StatusCode, body: string(respBody)}
 	}
 
 	// Parse response
@@ -379,5 +580,7 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 			h.writeError(w, http.StatusMethodNotAllowed, "only GET and POST methods are allowed")
 		}
 	})
+	mux.HandleFunc("/api/v1/events/explain", h.ExplainEvent)
+	mux.HandleFunc("/api/v1/submissions/", h.GetSubmission)
 	mux.HandleFunc("/api/v1/causality", h.CheckCausality)
 }
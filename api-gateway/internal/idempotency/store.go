@@ -0,0 +1,170 @@
+// Package idempotency remembers submitted events by client-chosen key, so a
+// client that lost a response can retry safely and get the original receipt
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/veps-service-480701/api-gateway/pkg/models"
+)
+
+// MaxKeyLength bounds the Idempotency-Key header
+const MaxKeyLength = 255
+
+// staleAfter is how long a synchronous submission may hold its key without a
+// response before it is taken to be abandoned, e.g. by an instance that
+// crashed; submissions are answered well within it
+const staleAfter = time.Minute
+
+// Outcome tells the caller of Begin what to do with a request
+type Outcome int
+
+const (
+	// New means the key is unseen; the caller submits the event and must call
+	// Complete or Release
+	New Outcome = iota
+	// Replay means the event was already submitted; Begin returns its response
+	Replay
+	// InFlight means a request with the same key is still being submitted
+	InFlight
+	// Mismatch means the key was used for a different request body
+	Mismatch
+)
+
+// outcomes maps the stored claim outcomes to Outcome
+var outcomes = map[string]Outcome{
+	"new":       New,
+	"replay":    Replay,
+	"in_flight": InFlight,
+	"mismatch":  Mismatch,
+}
+
+// Keys is the table of idempotency keys, next to the submissions table
+type Keys interface {
+	ClaimIdempotencyKey(ctx context.Context, clientID, key, fingerprint string, ttl, staleAfter time.Duration) (string, *models.ClientEventResponse, error)
+	CompleteIdempotencyKey(ctx context.Context, clientID, key string, response models.ClientEventResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, clientID, key string) error
+	CreateKeyedSubmission(ctx context.Context, id, clientID, callbackURL, key, fingerprint string, ttl time.Duration) (string, *models.Submission, error)
+}
+
+// Stats describes the store for health reporting
+type Stats struct {
+	Enabled bool   `json:"enabled"`
+	TTL     string `json:"ttl,omitempty"`
+	Replays uint64 `json:"replays"`
+}
+
+// Store holds idempotency keys per client until they expire
+// Keys are kept in Postgres, so a retry is recognized whichever gateway
+// instance it reaches. A zero TTL disables the store: Begin always returns
+// New
+type Store struct {
+	keys Keys
+	ttl  time.Duration
+
+	replays atomic.Uint64
+}
+
+// NewStore creates a store whose keys expire after ttl
+func NewStore(keys Keys, ttl time.Duration) *Store {
+	return &Store{keys: keys, ttl: ttl}
+}
+
+// Enabled reports whether Idempotency-Key headers are honored
+func (s *Store) Enabled() bool {
+	return s.ttl > 0
+}
+
+// Fingerprint identifies a request body independently of its formatting
+func Fingerprint(req models.ClientEventRequest) string {
+	// Marshalling sorts map keys, so equal requests hash equally
+	body, _ := json.Marshal(req)
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+// AsyncFingerprint identifies an asynchronous request, which differs from the
+// same body submitted synchronously, or with another callback URL
+func AsyncFingerprint(req models.ClientEventRequest, callbackURL string) string {
+	body, _ := json.Marshal(map[string]interface{}{
+		"mode":         "async",
+		"request":      req,
+		"callback_url": callbackURL,
+	})
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+// Begin claims a key for a synchronous request with the given fingerprint
+// The response is set only for Replay
+func (s *Store) Begin(ctx context.Context, clientID, key, fingerprint string) (Outcome, *models.ClientEventResponse, error) {
+	if !s.Enabled() {
+		return New, nil, nil
+	}
+
+	claim, response, err := s.keys.ClaimIdempotencyKey(ctx, clientID, key, fingerprint, s.ttl, staleAfter)
+	if err != nil {
+		return New, nil, err
+	}
+	outcome, ok := outcomes[claim]
+	if !ok {
+		return New, nil, fmt.Errorf("unknown claim outcome %q", claim)
+	}
+	if outcome == Replay {
+		s.replays.Add(1)
+	}
+	return outcome, response, nil
+}
+
+// BeginAsync records a pending asynchronous submission under a key
+// For New the submission is created with submissionID; for Replay it is the
+// submission of an earlier request with the key. A failed submission's key
+// may be reused, and creates a new submission
+func (s *Store) BeginAsync(ctx context.Context, submissionID, clientID, callbackURL, key, fingerprint string) (Outcome, *models.Submission, error) {
+	claim, submission, err := s.keys.CreateKeyedSubmission(ctx, submissionID, clientID, callbackURL, key, fingerprint, s.ttl)
+	if err != nil {
+		return New, nil, err
+	}
+	outcome, ok := outcomes[claim]
+	if !ok || outcome == InFlight {
+		return New, nil, fmt.Errorf("unexpected claim outcome %q", claim)
+	}
+	if outcome == Replay {
+		s.replays.Add(1)
+	}
+	return outcome, submission, nil
+}
+
+// Complete stores the response for a key; retries get it until the key expires
+func (s *Store) Complete(ctx context.Context, clientID, key string, response models.ClientEventResponse) error {
+	if !s.Enabled() {
+		return nil
+	}
+	return s.keys.CompleteIdempotencyKey(ctx, clientID, key, response)
+}
+
+// Release forgets a key whose request failed, so a retry is submitted again
+func (s *Store) Release(ctx context.Context, clientID, key string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	return s.keys.ReleaseIdempotencyKey(ctx, clientID, key)
+}
+
+// Stats returns a snapshot of the store for health reporting
+func (s *Store) Stats() Stats {
+	stats := Stats{
+		Enabled: s.Enabled(),
+		Replays: s.replays.Load(),
+	}
+	if stats.Enabled {
+		stats.TTL = s.ttl.String()
+	}
+	return stats
+}
@@ -0,0 +1,108 @@
+package veto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/veps-common/model"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/idtoken"
+)
+
+// Client asks the Veto Service to explain how it would judge an event
+// It never submits anything; explained events are not vetoed or recorded
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	tokenSource oauth2.TokenSource
+}
+
+// NewClient creates a Veto Service client
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Explanation is the Veto Service's answer to an explain request
+type Explanation struct {
+	Success  bool            `json:"success"`
+	Message  string          `json:"message,omitempty"`
+	EventID  string          `json:"event_id,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Duration string          `json:"duration,omitempty"`
+}
+
+// Explain runs the veto checks for an event without side effects
+func (c *Client) Explain(ctx context.Context, event model.Event) (*Explanation, error) {
+	body, err := json.Marshal(map[string]interface{}{"event": event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/validate/explain", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// The Veto Service only accepts service-to-service calls on Cloud Run
+	if token, err := c.idToken(); err != nil {
+		// Log but don't fail - might be running locally without auth
+		log.Printf("[Veto] Warning: failed to get ID token: %v", err)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call veto service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var explanation Explanation
+	if err := json.Unmarshal(respBody, &explanation); err != nil {
+		return nil, fmt.Errorf("veto service returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &explanation, fmt.Errorf("veto service returned status %d: %s", resp.StatusCode, explanation.Error)
+	}
+	return &explanation, nil
+}
+
+// idToken returns an ID token for the Veto Service, cached until it expires
+func (c *Client) idToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokenSource == nil {
+		// The source refreshes in the background, so it must not be tied to a request
+		ts, err := idtoken.NewTokenSource(context.Background(), c.baseURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to create token source: %w", err)
+		}
+		c.tokenSource = ts
+	}
+
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,107 @@
+package veto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// privateChecks are the checks whose traces read server-side state about the
+// actor: its registry row, its recent activity and its funds. A client may
+// name any user_id, so only their verdicts are passed on, with these reasons
+var privateChecks = map[string]string{
+	"actor_existence": "the actor cannot submit this event",
+	"velocity":        "the actor's activity limit would be exceeded",
+	"balance":         "the actor's balance does not allow this amount",
+}
+
+// publicInputs are the inputs of a private check that come from the request
+var publicInputs = map[string]bool{
+	"actor_id":     true,
+	"actor_type":   true,
+	"registration": true,
+	"amount":       true,
+}
+
+// Redact strips what the private checks read about the actor from the
+// explanation's trace: their inputs beyond the request, the values they
+// compared, their notes and the details in their reasons and warnings
+func (e *Explanation) Redact() error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return fmt.Errorf("failed to decode explanation: %w", err)
+	}
+
+	// Velocity warnings carry the limit's rendered reason, with the actor's totals
+	limitIDs := make(map[string]bool)
+
+	checks, _ := data["checks"].([]any)
+	for _, c := range checks {
+		check, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := check["check"].(string)
+		generic, private := privateChecks[name]
+		if !private {
+			continue
+		}
+
+		inputs, _ := check["inputs"].(map[string]any)
+		if limits, ok := inputs["limits"].([]any); ok {
+			for _, l := range limits {
+				if limit, ok := l.(map[string]any); ok {
+					if id, ok := limit["limit_id"].(string); ok {
+						limitIDs[id] = true
+					}
+				}
+			}
+		}
+
+		kept := make(map[string]any)
+		for key, value := range inputs {
+			if publicInputs[key] {
+				kept[key] = value
+			}
+		}
+		check["inputs"] = kept
+		delete(check, "comparisons")
+		delete(check, "rules")
+		delete(check, "note")
+		if reason, _ := check["reason"].(string); reason != "" {
+			check["reason"] = generic
+		}
+	}
+
+	// Reasons are "<check>: <reason>"
+	reasons, _ := data["reasons"].([]any)
+	for i, r := range reasons {
+		reason, _ := r.(string)
+		name, _, _ := strings.Cut(reason, ": ")
+		if generic, private := privateChecks[name]; private {
+			reasons[i] = name + ": " + generic
+		}
+	}
+
+	warnings, _ := data["warnings"].([]any)
+	for _, w := range warnings {
+		warning, ok := w.(map[string]any)
+		if !ok {
+			continue
+		}
+		if id, _ := warning["rule_id"].(string); limitIDs[id] {
+			warning["reason"] = privateChecks["velocity"]
+		}
+	}
+
+	redacted, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode explanation: %w", err)
+	}
+	e.Data = redacted
+	return nil
+}
@@ -0,0 +1,150 @@
+package veto
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// vetoedExplanation is what the Veto Service explains for a withdrawal by a
+// suspended actor over its velocity limit and its funds
+const vetoedExplanation = `{
+	"passed": false,
+	"verdict": "vetoed",
+	"failed_checks": ["actor_existence", "velocity", "balance"],
+	"reasons": [
+		"actor_existence: actor is suspended: chargeback fraud",
+		"velocity: 12 withdrawals in 1h exceeds 10",
+		"balance: insufficient funds: available 40 (balance 100, held 60), withdrawal 75",
+		"business_rules: withdrawal amount is missing or invalid"
+	],
+	"warnings": [
+		{"rule_id": "withdrawals-per-hour", "severity": "warn", "reason": "12 withdrawals in 1h exceeds 10"},
+		{"rule_id": "large-withdrawal", "severity": "warn", "reason": "withdrawal over 50"}
+	],
+	"checks": [
+		{
+			"check": "actor_existence",
+			"passed": false,
+			"reason": "actor is suspended: chargeback fraud",
+			"note": "actor registered 2025-01-01",
+			"inputs": {"actor_id": "alice", "actor_type": "user", "registered": {"status": "suspended", "suspended_reason": "chargeback fraud"}},
+			"comparisons": [{"expr": "status", "op": "==", "left": "suspended", "right": "active", "result": false}],
+			"duration": "5µs"
+		},
+		{
+			"check": "velocity",
+			"passed": false,
+			"reason": "12 withdrawals in 1h exceeds 10",
+			"inputs": {"actor_id": "alice", "limits": [{"limit_id": "withdrawals-per-hour", "prior": 11, "amount": 1, "total": 12, "max": 10, "fired": true}], "windows": {"1h": 11}},
+			"comparisons": [{"expr": "total", "op": "<=", "left": 12, "right": 10, "result": false}],
+			"duration": "7µs"
+		},
+		{
+			"check": "balance",
+			"passed": false,
+			"reason": "insufficient funds: available 40 (balance 100, held 60), withdrawal 75",
+			"inputs": {"actor_id": "alice", "amount": 75, "balance": 100, "held": 60, "available": 40},
+			"comparisons": [{"expr": "amount", "op": "<=", "left": 75, "right": 40, "result": false}],
+			"duration": "9µs"
+		},
+		{
+			"check": "business_rules",
+			"passed": false,
+			"reason": "withdrawal amount is missing or invalid",
+			"inputs": {"type": "withdrawal"},
+			"rules": [{"rule_id": "withdrawal-amount-invalid", "fired": true}],
+			"duration": "3µs"
+		}
+	]
+}`
+
+func TestRedactStripsActorState(t *testing.T) {
+	e := &Explanation{Data: json.RawMessage(vetoedExplanation)}
+	if err := e.Redact(); err != nil {
+		t.Fatalf("Redact: %v", err)
+	}
+
+	for _, leaked := range []string{"chargeback", `"suspended"`, `"registered"`, `"held"`, `"available"`, `"windows"`, `"prior"`, "12 withdrawals", "100"} {
+		if strings.Contains(string(e.Data), leaked) {
+			t.Errorf("redacted explanation still contains %q: %s", leaked, e.Data)
+		}
+	}
+
+	var data struct {
+		Reasons  []string `json:"reasons"`
+		Warnings []struct {
+			RuleID string `json:"rule_id"`
+			Reason string `json:"reason"`
+		} `json:"warnings"`
+		Checks []map[string]any `json:"checks"`
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		t.Fatalf("redacted explanation is not JSON: %v", err)
+	}
+
+	wantReasons := []string{
+		"actor_existence: " + privateChecks["actor_existence"],
+		"velocity: " + privateChecks["velocity"],
+		"balance: " + privateChecks["balance"],
+		"business_rules: withdrawal amount is missing or invalid",
+	}
+	if !reflect.DeepEqual(data.Reasons, wantReasons) {
+		t.Errorf("reasons = %q, want %q", data.Reasons, wantReasons)
+	}
+
+	if len(data.Warnings) != 2 || data.Warnings[0].Reason != privateChecks["velocity"] || data.Warnings[1].Reason != "withdrawal over 50" {
+		t.Errorf("warnings = %+v, want only the velocity limit's reason replaced", data.Warnings)
+	}
+
+	tests := []struct {
+		check  string
+		inputs map[string]any
+	}{
+		{check: "actor_existence", inputs: map[string]any{"actor_id": "alice", "actor_type": "user"}},
+		{check: "velocity", inputs: map[string]any{"actor_id": "alice"}},
+		{check: "balance", inputs: map[string]any{"actor_id": "alice", "amount": float64(75)}},
+	}
+	for i, tt := range tests {
+		check := data.Checks[i]
+		if check["check"] != tt.check {
+			t.Fatalf("check %d = %v, want %s", i, check["check"], tt.check)
+		}
+		if !reflect.DeepEqual(check["inputs"], tt.inputs) {
+			t.Errorf("%s inputs = %v, want %v", tt.check, check["inputs"], tt.inputs)
+		}
+		for _, field := range []string{"comparisons", "rules", "note"} {
+			if _, ok := check[field]; ok {
+				t.Errorf("%s still has %s", tt.check, field)
+			}
+		}
+		if check["passed"] != false || check["duration"] == nil {
+			t.Errorf("%s lost its verdict or duration: %v", tt.check, check)
+		}
+	}
+
+	rules := data.Checks[3]
+	if rules["reason"] != "withdrawal amount is missing or invalid" || rules["rules"] == nil {
+		t.Errorf("business_rules trace was redacted: %v", rules)
+	}
+}
+
+func TestRedactLeavesPassedChecksReasonless(t *testing.T) {
+	e := &Explanation{Data: json.RawMessage(`{"passed": true, "checks": [{"check": "balance", "passed": true, "inputs": {"actor_id": "bob", "available": 10}}]}`)}
+	if err := e.Redact(); err != nil {
+		t.Fatalf("Redact: %v", err)
+	}
+	if want := `{"checks":[{"check":"balance","inputs":{"actor_id":"bob"},"passed":true}],"passed":true}`; string(e.Data) != want {
+		t.Errorf("Data = %s, want %s", e.Data, want)
+	}
+}
+
+func TestRedactRejectsMalformedData(t *testing.T) {
+	if err := (&Explanation{}).Redact(); err != nil {
+		t.Errorf("Redact without data = %v, want nil", err)
+	}
+	if err := (&Explanation{Data: json.RawMessage(`[1, 2]`)}).Redact(); err == nil {
+		t.Error("Redact accepted data that is not an object")
+	}
+}
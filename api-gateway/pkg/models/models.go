@@ -2,26 +2,29 @@ package models
 
 import (
 	"time"
+
+	"github.com/veps-service-480701/veps-common/model"
 )
 
 // ClientEventRequest represents the client's event submission format
 type ClientEventRequest struct {
-	EventType        string                 `json:"event_type"`
-	NoteID           int                    `json:"note_id,omitempty"`
-	UserID           string                 `json:"user_id"`
-	BPM              int                    `json:"bpm,omitempty"`
-	DurationMS       int                    `json:"duration_ms,omitempty"`
-	TimestampClient  int64                  `json:"timestamp_client"` // ms since epoch
-	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	EventType       string                 `json:"event_type"`
+	NoteID          int                    `json:"note_id,omitempty"`
+	UserID          string                 `json:"user_id"`
+	BPM             int                    `json:"bpm,omitempty"`
+	DurationMS      int                    `json:"duration_ms,omitempty"`
+	TimestampClient int64                  `json:"timestamp_client"` // ms since epoch
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // ClientEventResponse represents the response after event submission
 type ClientEventResponse struct {
-	SequenceNumber uint64                 `json:"sequence_number"`
-	VectorClock    map[string]int64       `json:"vector_clock"`
-	ProofHash      string                 `json:"proof_hash"`
-	TimestampVEPS  int64                  `json:"timestamp_veps"` // ms since epoch
-	EventID        string                 `json:"event_id"`
+	SequenceNumber uint64            `json:"sequence_number"`
+	VectorClock    model.VectorClock `json:"vector_clock"`
+	ProofHash      string            `json:"proof_hash"`
+	PreviousHash   string            `json:"previous_hash,omitempty"`
+	TimestampVEPS  int64             `json:"timestamp_veps"` // ms since epoch
+	EventID        string            `json:"event_id"`
 }
 
 // CausalityRequest represents a causality check request
@@ -39,13 +42,13 @@ type CausalityResponse struct {
 
 // BatchQueryRequest represents a batch event retrieval request
 type BatchQueryRequest struct {
-	NoteID       *int   `json:"note_id,omitempty"`
-	UserID       *string `json:"user_id,omitempty"`
-	StartSeq     *uint64 `json:"start_seq,omitempty"`
-	EndSeq       *uint64 `json:"end_seq,omitempty"`
-	StartTime    *int64  `json:"start_time,omitempty"` // ms since epoch
-	EndTime      *int64  `json:"end_time,omitempty"`   // ms since epoch
-	Limit        int     `json:"limit,omitempty"`
+	NoteID    *int    `json:"note_id,omitempty"`
+	UserID    *string `json:"user_id,omitempty"`
+	StartSeq  *uint64 `json:"start_seq,omitempty"`
+	EndSeq    *uint64 `json:"end_seq,omitempty"`
+	StartTime *int64  `json:"start_time,omitempty"` // ms since epoch
+	EndTime   *int64  `json:"end_time,omitempty"`   // ms since epoch
+	Limit     int     `json:"limit,omitempty"`
 }
 
 // BatchQueryResponse represents the batch retrieval response
@@ -57,6 +60,8 @@ type BatchQueryResponse struct {
 // EventSummary represents a summary of a single event
 type EventSummary struct {
 	SequenceNumber uint64                 `json:"sequence_number"`
+	EventID        string                 `json:"event_id"`
+	ProofHash      string                 `json:"proof_hash,omitempty"`
 	EventType      string                 `json:"event_type"`
 	TimestampVEPS  int64                  `json:"timestamp_veps"` // ms since epoch
 	NoteID         *int                   `json:"note_id,omitempty"`
@@ -76,8 +81,30 @@ type BoundaryResponse struct {
 	Message     string            `json:"message,omitempty"`
 	EventID     string            `json:"event_id,omitempty"`
 	Timestamp   time.Time         `json:"timestamp,omitempty"`
-	VectorClock map[string]int64  `json:"vector_clock,omitempty"`
+	VectorClock model.VectorClock `json:"vector_clock,omitempty"`
 	Duration    string            `json:"duration,omitempty"`
+	Data        BoundaryData      `json:"data"`
+}
+
+// BoundaryData represents the routing outcome returned by Boundary Adapter
+type BoundaryData struct {
+	Event BoundaryEventData `json:"event"`
+	Seal  *SealReceipt      `json:"seal,omitempty"`
+}
+
+// BoundaryEventData represents the normalized event echoed by Boundary Adapter
+type BoundaryEventData struct {
+	ID          string            `json:"id"`
+	VectorClock model.VectorClock `json:"vector_clock"`
+}
+
+// SealReceipt represents the ImmutableLedger proof for a sealed event
+type SealReceipt struct {
+	SequenceNumber  uint64    `json:"sequence_number"`
+	EventID         string    `json:"event_id"`
+	EventHash       string    `json:"event_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	SealedTimestamp time.Time `json:"sealed_timestamp"`
 }
 
 // StandardResponse represents the standard API response format
@@ -88,3 +115,47 @@ type StandardResponse struct {
 	Error     string      `json:"error,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
 }
+
+// SubmissionAccepted represents the response to an asynchronous submission
+type SubmissionAccepted struct {
+	SubmissionID string `json:"submission_id"`
+	State        string `json:"state"`
+	StatusURL    string `json:"status_url"`
+}
+
+// Submission represents the state of an asynchronous submission
+type Submission struct {
+	SubmissionID   string              `json:"submission_id"`
+	ClientID       string              `json:"-"`     // only the submitting client may read it
+	State          string              `json:"state"` // "pending", "sealed", "vetoed", "failed"
+	EventID        string              `json:"event_id,omitempty"`
+	SequenceNumber uint64              `json:"sequence_number,omitempty"`
+	VectorClock    model.VectorClock   `json:"vector_clock,omitempty"`
+	ProofHash      string              `json:"proof_hash,omitempty"`
+	PreviousHash   string              `json:"previous_hash,omitempty"`
+	VetoReasons    []string            `json:"veto_reasons,omitempty"`
+	FailedChecks   []string            `json:"failed_checks,omitempty"`
+	Error          string              `json:"error,omitempty"`
+	Callback       *SubmissionCallback `json:"callback,omitempty"`
+	SubmittedAt    int64               `json:"submitted_at"`           // ms since epoch
+	CompletedAt    int64               `json:"completed_at,omitempty"` // ms since epoch
+}
+
+// SubmissionCallback represents the delivery of a submission's callback
+type SubmissionCallback struct {
+	URL         string `json:"url"`
+	Attempts    int    `json:"attempts"`
+	Delivered   bool   `json:"delivered"`
+	DeliveredAt int64  `json:"delivered_at,omitempty"` // ms since epoch
+	Error       string `json:"error,omitempty"`
+}
+
+// BoundaryVeto represents the Boundary Adapter's response to a vetoed event
+type BoundaryVeto struct {
+	Error   string `json:"error"`
+	EventID string `json:"event_id"`
+	Data    struct {
+		FailedChecks []string `json:"failed_checks"`
+		Reasons      []string `json:"reasons"`
+	} `json:"data"`
+}
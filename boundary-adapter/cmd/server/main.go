@@ -7,12 +7,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/veps-service-480701/boundary-adapter/internal/client"
+	"github.com/veps-service-480701/boundary-adapter/internal/clock"
+	"github.com/veps-service-480701/boundary-adapter/internal/dedupe"
 	"github.com/veps-service-480701/boundary-adapter/internal/handler"
 	"github.com/veps-service-480701/boundary-adapter/internal/normalizer"
+	"github.com/veps-service-480701/boundary-adapter/internal/outbox"
 	"github.com/veps-service-480701/boundary-adapter/internal/router"
 )
 
@@ -22,23 +27,55 @@ func main() {
 	// Load configuration from environment
 	config := loadConfig()
 
-	// Initialize components
-	norm := normalizer.New()
-	log.Printf("[Main] Normalizer initialized with node ID: %s", os.Getenv("VEPS_NODE_ID"))
-
 	// Initialize real service clients
 	rdbClient := client.NewRDBClient(config.RDBUpdaterURL, 5*time.Second)
 	vetoClient := client.NewVetoClient(config.VetoServiceURL, 5*time.Second)
+	ledgerClient := client.NewLedgerClient(config.MonolithSubmitterURL, 5*time.Second)
+	fractureClient := client.NewFractureClient(config.FractureHandlerURL, 5*time.Second)
+
+	log.Printf("[Main] Service clients initialized (RDB: %s, Veto: %s, Submitter: %s, Fracture: %s)", 
+		config.RDBUpdaterURL, config.VetoServiceURL, config.MonolithSubmitterURL, config.FractureHandlerURL)
+
+	// Initialize the node's logical clock (persisted, then caught up with the store)
+	clk, err := clock.Open(filepath.Join(config.DataDir, "clock.json"), config.NodeID)
+	if err != nil {
+		log.Fatalf("[Main] Failed to open vector clock state: %v", err)
+	}
+	os.Setenv("VEPS_NODE_ID", clk.NodeID())
+
+	recoverCtx, recoverCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	storedCounter, err := rdbClient.GetNodeCounter(recoverCtx, clk.NodeID())
+	recoverCancel()
+	if err != nil {
+		// Without the stored counter a lost clock file would restart the node
+		// at 0 and reuse counters its earlier events already carry
+		log.Fatalf("[Main] Failed to recover clock from RDB Updater: %v", err)
+	}
+	if err := clk.Observe(storedCounter); err != nil {
+		log.Fatalf("[Main] Failed to persist recovered clock: %v", err)
+	}
 
-	log.Printf("[Main] Service clients initialized (RDB: %s, Veto: %s)", 
-		config.RDBUpdaterURL, config.VetoServiceURL)
+	// Initialize components
+	norm := normalizer.New(clk)
+	log.Printf("[Main] Normalizer initialized with node ID: %s (counter: %d)", clk.NodeID(), clk.Counter())
+
+	// Initialize durable outbox for the context and fracture paths
+	contextOutbox, err := outbox.Open(filepath.Join(config.DataDir, "outbox"), rdbClient, fractureClient)
+	if err != nil {
+		log.Fatalf("[Main] Failed to open context outbox: %v", err)
+	}
+	contextOutbox.Start()
 
 	// Initialize router with timeout for sub-50ms requirement
-	rtr := router.New(vetoClient, rdbClient, config.RouterTimeout)
-	log.Printf("[Main] Router initialized with %s timeout", config.RouterTimeout)
+	rtr := router.New(vetoClient, contextOutbox, ledgerClient, contextOutbox, config.RouterTimeout, config.SealTimeout)
+	log.Printf("[Main] Router initialized with %s veto timeout, %s seal timeout", config.RouterTimeout, config.SealTimeout)
+
+	// Remember ingested requests, in the RDB Updater's table shared by every
+	// instance, so client retries are not ingested twice
+	dedupeStore := dedupe.NewStore(rdbClient, config.DedupeWindow, config.DedupeFingerprints)
 
 	// Initialize HTTP handler
-	h := handler.New(norm, rtr)
+	h := handler.New(norm, rtr, contextOutbox, dedupeStore)
 
 	// Set up HTTP server
 	mux := http.NewServeMux()
@@ -78,16 +115,31 @@ func main() {
 		log.Fatalf("[Main] Server forced to shutdown: %v", err)
 	}
 
+	// Deliver what is still queued while the deadline allows; the rest stays
+	// on disk for the next start
+	if err := contextOutbox.Drain(ctx); err != nil {
+		log.Printf("[Main] Warning: outbox not drained before shutdown: %v", err)
+	}
+	if err := contextOutbox.Close(); err != nil {
+		log.Printf("[Main] Warning: failed to close outbox: %v", err)
+	}
+
 	log.Println("[Main] Server exited successfully")
 }
 
 // Config holds application configuration
 type Config struct {
-	Port           string
-	RouterTimeout  time.Duration
-	NodeID         string
-	RDBUpdaterURL  string
-	VetoServiceURL string
+	Port                 string
+	RouterTimeout        time.Duration // ROUTER_TIMEOUT_MS: budget for the veto decision
+	SealTimeout          time.Duration
+	NodeID               string
+	RDBUpdaterURL        string
+	VetoServiceURL       string
+	MonolithSubmitterURL string
+	FractureHandlerURL   string
+	DataDir              string        // VEPS_DATA_DIR: durable directory for the outbox and clock state
+	DedupeWindow         time.Duration // how long retries are recognized; zero disables
+	DedupeFingerprints   bool          // VEPS_DEDUPE_FINGERPRINT: also dedupe requests without an Idempotency-Key
 }
 
 // loadConfig loads configuration from environment variables
@@ -97,21 +149,32 @@ func loadConfig() Config {
 		port = "8080" // Default port for Cloud Run
 	}
 
-	timeoutStr := os.Getenv("ROUTER_TIMEOUT_MS")
-	timeout := 50 * time.Millisecond // Default to 50ms for sub-50ms requirement
-	if timeoutStr != "" {
-		if ms, err := time.ParseDuration(timeoutStr + "ms"); err == nil {
+	// The veto looks up the actor, its recent activity and its balance in the
+	// RDB before deciding, which a 50ms budget did not leave room for: cold
+	// instances and busy actors timed out and their events were rejected. The
+	// budget is a ceiling, so fast decisions are not slowed down by it
+	timeout := 2 * time.Second
+	if raw := os.Getenv("ROUTER_TIMEOUT_MS"); raw != "" {
+		if ms, err := time.ParseDuration(raw + "ms"); err == nil && ms > 0 {
 			timeout = ms
+		} else {
+			log.Printf("[Main] Warning: invalid ROUTER_TIMEOUT_MS %q, using %s", raw, timeout)
 		}
 	}
 
-	nodeID := os.Getenv("VEPS_NODE_ID")
-	if nodeID == "" {
-		// Generate a default node ID if not set
-		nodeID = fmt.Sprintf("boundary-adapter-%d", time.Now().Unix())
-		os.Setenv("VEPS_NODE_ID", nodeID)
+	// The seal starts once the veto has passed and gets its own budget
+	sealTimeout := 5 * time.Second
+	if raw := os.Getenv("SEAL_TIMEOUT_MS"); raw != "" {
+		if ms, err := time.ParseDuration(raw + "ms"); err == nil && ms > 0 {
+			sealTimeout = ms
+		} else {
+			log.Printf("[Main] Warning: invalid SEAL_TIMEOUT_MS %q, using %s", raw, sealTimeout)
+		}
 	}
 
+	// An empty node ID reuses the one persisted with the clock state
+	nodeID := os.Getenv("VEPS_NODE_ID")
+
 	rdbUpdaterURL := os.Getenv("RDB_UPDATER_URL")
 	if rdbUpdaterURL == "" {
 		rdbUpdaterURL = "http://localhost:8081" // Default for local dev
@@ -122,12 +185,54 @@ func loadConfig() Config {
 		vetoServiceURL = "http://localhost:8082" // Default for local dev
 	}
 
+	monolithSubmitterURL := os.Getenv("MONOLITH_SUBMITTER_URL")
+	if monolithSubmitterURL == "" {
+		monolithSubmitterURL = "http://localhost:8083" // Default for local dev
+	}
+
+	fractureHandlerURL := os.Getenv("FRACTURE_HANDLER_URL")
+	if fractureHandlerURL == "" {
+		fractureHandlerURL = "http://localhost:8084" // Default for local dev
+	}
+
+	// The outbox and the clock must outlive the instance, so there is no
+	// default: on Cloud Run /tmp is in memory and lost on every restart
+	dataDir := os.Getenv("VEPS_DATA_DIR")
+	if dataDir == "" {
+		log.Fatal("[Main] VEPS_DATA_DIR must be set to a durable directory (e.g. a mounted volume) for the outbox and clock state")
+	}
+
+	dedupeWindow := 10 * time.Minute
+	if raw := os.Getenv("VEPS_DEDUPE_WINDOW"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+			dedupeWindow = d
+		} else {
+			log.Printf("[Main] Warning: invalid VEPS_DEDUPE_WINDOW %q, using %s", raw, dedupeWindow)
+		}
+	}
+
+	// Off by default: equal events without a key may well be meant twice
+	dedupeFingerprints := false
+	if raw := os.Getenv("VEPS_DEDUPE_FINGERPRINT"); raw != "" {
+		if enabled, err := strconv.ParseBool(raw); err == nil {
+			dedupeFingerprints = enabled
+		} else {
+			log.Printf("[Main] Warning: invalid VEPS_DEDUPE_FINGERPRINT %q, fingerprint dedupe disabled", raw)
+		}
+	}
+
 	return Config{
-		Port:           port,
-		RouterTimeout:  timeout,
-		NodeID:         nodeID,
-		RDBUpdaterURL:  rdbUpdaterURL,
-		VetoServiceURL: vetoServiceURL,
+		Port:                 port,
+		RouterTimeout:        timeout,
+		SealTimeout:          sealTimeout,
+		NodeID:               nodeID,
+		RDBUpdaterURL:        rdbUpdaterURL,
+		VetoServiceURL:       vetoServiceURL,
+		MonolithSubmitterURL: monolithSubmitterURL,
+		FractureHandlerURL:   fractureHandlerURL,
+		DataDir:              dataDir,
+		DedupeWindow:         dedupeWindow,
+		DedupeFingerprints:   dedupeFingerprints,
 	}
 }
 
@@ -169,7 +274,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+)
+
+// FractureClient handles communication with the Data Fracture Handler
+type FractureClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewFractureClient creates a new Data Fracture Handler client
+func NewFractureClient(baseURL string, timeout time.Duration) *FractureClient {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	// Configure HTTP client for high performance
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DisableKeepAlives:   false,
+		ForceAttemptHTTP2:   true,
+	}
+
+	return &FractureClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// SendToFracture logs a vetoed event with the Data Fracture Handler (Fracture Path)
+func (c *FractureClient) SendToFracture(ctx context.Context, fracture models.FractureRequest) error {
+	// Marshal to JSON
+	jsonData, err := json.Marshal(fracture)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fracture request: %w", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/fracture", c.baseURL),
+		bytes.NewBuffer(jsonData),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication token for Cloud Run service-to-service calls
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		// Log but don't fail - might be running locally without auth
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	// Send request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		var errMsg string
+		var errorResp map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
+			errMsg, _ = errorResp["error"].(string)
+		}
+		// Client errors are permanent; the outbox dead-letters them after a few attempts
+		if rejected := models.NewRejectedError("Data Fracture Handler", resp.StatusCode, errMsg); rejected != nil {
+			return rejected
+		}
+		if errMsg != "" {
+			return fmt.Errorf("Data Fracture Handler error: %s", errMsg)
+		}
+		return fmt.Errorf("Data Fracture Handler returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
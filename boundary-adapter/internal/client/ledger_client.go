@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+)
+
+// LedgerClient handles communication with the Monolith Submitter, which seals
+// certified events into the ImmutableLedger
+type LedgerClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewLedgerClient creates a new Monolith Submitter client
+func NewLedgerClient(baseURL string, timeout time.Duration) *LedgerClient {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	// Configure HTTP client for high performance
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DisableKeepAlives:   false,
+		ForceAttemptHTTP2:   true,
+	}
+
+	return &LedgerClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// SubmitEvent sends an event that passed the veto to the Monolith Submitter
+// and returns the ledger's sealing receipt
+func (c *LedgerClient) SubmitEvent(ctx context.Context, event models.Event) (*models.SubmitResponse, error) {
+	// Prepare the submit request
+	submitRequest := struct {
+		Event models.Event `json:"event"`
+	}{
+		Event: event,
+	}
+
+	// Marshal to JSON
+	jsonData, err := json.Marshal(submitRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal submit request: %w", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/submit", c.baseURL),
+		bytes.NewBuffer(jsonData),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication token for Cloud Run service-to-service calls
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		// Log but don't fail - might be running locally without auth
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	// Send request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Parse response
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    models.SubmitResponse `json:"data"`
+		Error   string                `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !response.Success {
+		if response.Error != "" {
+			return nil, fmt.Errorf("Monolith Submitter error: %s", response.Error)
+		}
+		return nil, fmt.Errorf("Monolith Submitter returned status %d", resp.StatusCode)
+	}
+
+	return &response.Data, nil
+}
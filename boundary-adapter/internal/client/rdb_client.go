@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/veps-service-480701/boundary-adapter/pkg/models"
@@ -41,13 +43,6 @@ func NewRDBClient(baseURL string, timeout time.Duration) *RDBClient {
 	}
 }
 
-// ContextUpdate represents the request to RDB Updater
-type ContextUpdate struct {
-	Event     models.Event `json:"event"`
-	Operation string       `json:"operation"`
-	Route     string       `json:"route"`
-}
-
 // getIDToken gets an ID token for authenticating to another Cloud Run service
 // DEPRECATED: Use GetIDToken from token_cache.go instead
 func getIDToken(ctx context.Context, audience string) (string, error) {
@@ -56,13 +51,15 @@ func getIDToken(ctx context.Context, audience string) (string, error) {
 
 // SendToRDB sends an event to the RDB Updater (Context Path)
 func (c *RDBClient) SendToRDB(ctx context.Context, event models.Event) error {
-	// Prepare the context update request
-	update := ContextUpdate{
+	return c.SendUpdate(ctx, models.ContextUpdate{
 		Event:     event,
 		Operation: "upsert",
 		Route:     "rdb_updater",
-	}
+	})
+}
 
+// SendUpdate sends a context update to the RDB Updater
+func (c *RDBClient) SendUpdate(ctx context.Context, update models.ContextUpdate) error {
 	// Marshal to JSON
 	jsonData, err := json.Marshal(update)
 	if err != nil {
@@ -99,13 +96,26 @@ func (c *RDBClient) SendToRDB(ctx context.Context, event models.Event) error {
 	}
 	defer resp.Body.Close()
 
+	// A conflicting seal cannot be fixed by retrying; report it and let the outbox move on
+	if resp.StatusCode == http.StatusConflict && update.Operation == "seal" {
+		log.Printf("[RDBClient] ERROR: event %s already sealed with a different sequence number (got %d)",
+			update.Event.ID, update.Seal.SequenceNumber)
+		return nil
+	}
+
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
+		var errMsg string
 		var errorResp map[string]interface{}
 		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
-			if errMsg, ok := errorResp["error"].(string); ok {
-				return fmt.Errorf("RDB Updater error: %s", errMsg)
-			}
+			errMsg, _ = errorResp["error"].(string)
+		}
+		// Client errors are permanent; the outbox dead-letters them after a few attempts
+		if rejected := models.NewRejectedError("RDB Updater", resp.StatusCode, errMsg); rejected != nil {
+			return rejected
+		}
+		if errMsg != "" {
+			return fmt.Errorf("RDB Updater error: %s", errMsg)
 		}
 		return fmt.Errorf("RDB Updater returned status %d", resp.StatusCode)
 	}
@@ -113,6 +123,118 @@ func (c *RDBClient) SendToRDB(ctx context.Context, event models.Event) error {
 	return nil
 }
 
+// GetNodeCounter returns the highest vector clock counter stored for a boundary node
+func (c *RDBClient) GetNodeCounter(ctx context.Context, nodeID string) (int64, error) {
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/clock?node=%s", c.baseURL, url.QueryEscape(nodeID)),
+		nil,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication token for Cloud Run service-to-service calls
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	// Send request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("RDB Updater returned status %d", resp.StatusCode)
+	}
+
+	// Parse response
+	var response struct {
+		Data struct {
+			Counter int64 `json:"counter"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data.Counter, nil
+}
+
+// ClaimIngestKey claims an idempotency key in the RDB Updater's shared table
+func (c *RDBClient) ClaimIngestKey(ctx context.Context, claim models.IngestKeyClaim) (*models.IngestKeyClaimResult, error) {
+	var response struct {
+		Data models.IngestKeyClaimResult `json:"data"`
+	}
+	if err := c.postJSON(ctx, "/ingest-keys/claim", claim, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.Key == nil {
+		return nil, fmt.Errorf("RDB Updater returned no ingest key")
+	}
+	return &response.Data, nil
+}
+
+// UpdateIngestKey records the progress of a claimed idempotency key
+func (c *RDBClient) UpdateIngestKey(ctx context.Context, update models.IngestKeyUpdate) error {
+	return c.postJSON(ctx, "/ingest-keys/update", update, nil)
+}
+
+// postJSON posts a request to the RDB Updater and decodes its response into
+// out, if given
+func (c *RDBClient) postJSON(ctx context.Context, path string, body, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication token for Cloud Run service-to-service calls
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil && errorResp.Error != "" {
+			return fmt.Errorf("RDB Updater error (status %d): %s", resp.StatusCode, errorResp.Error)
+		}
+		return fmt.Errorf("RDB Updater returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
 // VetoClient handles communication with the Veto Service (placeholder for now)
 type VetoClient struct {
 	baseURL    string
@@ -196,16 +318,24 @@ func (c *VetoClient) SendToVeto(ctx context.Context, event models.Event) error {
 	}
 
 	if resp.StatusCode == http.StatusPreconditionFailed {
-		// Parse the veto response to get details
-		var errorResp map[string]interface{}
+		// Parse the veto response to get details for the fracture path
+		vetoErr := &models.VetoError{VetoNode: c.baseURL}
+
+		var errorResp struct {
+			Duration string `json:"duration"`
+			Data     struct {
+				FailedChecks   []string `json:"failed_checks"`
+				Reasons        []string `json:"reasons"`
+				RulesetVersion string   `json:"ruleset_version"`
+			} `json:"data"`
+		}
 		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
-			if data, ok := errorResp["data"].(map[string]interface{}); ok {
-				if reasons, ok := data["reasons"].([]interface{}); ok {
-					return fmt.Errorf("event vetoed: %v", reasons)
-				}
-			}
+			vetoErr.FailedChecks = errorResp.Data.FailedChecks
+			vetoErr.Reasons = errorResp.Data.Reasons
+			vetoErr.Duration = errorResp.Duration
+			vetoErr.RulesetVersion = errorResp.Data.RulesetVersion
 		}
-		return fmt.Errorf("event vetoed by Veto Service")
+		return vetoErr
 	}
 
 	return fmt.Errorf("Veto Service returned unexpected status %d", resp.StatusCode)
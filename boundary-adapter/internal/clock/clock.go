@@ -0,0 +1,148 @@
+package clock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Clock is this node's logical counter for vector clocks.
+// The counter is persisted on every tick so it stays monotonic across restarts,
+// and the node ID is persisted so a restarted node keeps its identity.
+type Clock struct {
+	mu      sync.Mutex
+	path    string
+	nodeID  string
+	counter int64
+}
+
+// ErrExhausted is returned once the counter cannot be advanced any further
+var ErrExhausted = errors.New("vector clock counter exhausted")
+
+// state is the on-disk representation of the clock
+type state struct {
+	NodeID  string `json:"node_id"`
+	Counter int64  `json:"counter"`
+}
+
+// Open loads the clock state from path. If nodeID is empty, the persisted node
+// ID is reused (or a new one generated); if it differs from the persisted one,
+// the counter starts over for the new node.
+func Open(path string, nodeID string) (*Clock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create clock directory: %w", err)
+	}
+
+	var persisted state
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read clock state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &persisted); err != nil {
+			return nil, fmt.Errorf("failed to parse clock state: %w", err)
+		}
+	}
+
+	c := &Clock{path: path, nodeID: nodeID}
+
+	switch {
+	case nodeID == "" && persisted.NodeID != "":
+		c.nodeID = persisted.NodeID
+		c.counter = persisted.Counter
+	case nodeID == "":
+		c.nodeID = fmt.Sprintf("boundary-adapter-%s", uuid.New().String())
+	case nodeID == persisted.NodeID:
+		c.counter = persisted.Counter
+	}
+
+	if err := c.persist(c.counter); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NodeID returns the node ID this clock counts for
+func (c *Clock) NodeID() string {
+	return c.nodeID
+}
+
+// Counter returns the last counter value handed out
+func (c *Clock) Counter() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counter
+}
+
+// Observe raises the counter to at least value, e.g. when recovering the
+// highest counter already stored for this node
+func (c *Clock) Observe(value int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value <= c.counter {
+		return nil
+	}
+	if err := c.persist(value); err != nil {
+		return err
+	}
+	c.counter = value
+	return nil
+}
+
+// Tick advances the counter by one and returns the new value once it is
+// persisted. Only this node hands out its counters, so a value claimed for it
+// by a client never moves the counter: a jump would leave counters that no
+// event carries, which later events could not cite as their predecessor
+func (c *Clock) Tick() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counter == math.MaxInt64 {
+		return 0, ErrExhausted
+	}
+	next := c.counter + 1
+
+	if err := c.persist(next); err != nil {
+		return 0, err
+	}
+	c.counter = next
+	return next, nil
+}
+
+// persist atomically replaces the state file (caller holds mu)
+func (c *Clock) persist(counter int64) error {
+	data, err := json.Marshal(state{NodeID: c.nodeID, Counter: counter})
+	if err != nil {
+		return fmt.Errorf("failed to marshal clock state: %w", err)
+	}
+
+	tmpPath := c.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write clock state: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write clock state: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync clock state: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close clock state: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("failed to replace clock state: %w", err)
+	}
+	return nil
+}
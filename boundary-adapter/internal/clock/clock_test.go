@@ -0,0 +1,137 @@
+package clock
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTickPersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clock.json")
+
+	clk, err := Open(path, "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for want := int64(1); want <= 3; want++ {
+		got, err := clk.Tick()
+		if err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+		if got != want {
+			t.Errorf("Tick = %d, want %d", got, want)
+		}
+	}
+
+	// Reopening with the same node ID, or with none, continues the counter
+	for _, nodeID := range []string{"node-a", ""} {
+		reopened, err := Open(path, nodeID)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", nodeID, err)
+		}
+		if reopened.NodeID() != "node-a" {
+			t.Errorf("Open(%q).NodeID() = %q, want node-a", nodeID, reopened.NodeID())
+		}
+		if got := reopened.Counter(); got != 3 {
+			t.Errorf("Open(%q).Counter() = %d, want 3", nodeID, got)
+		}
+	}
+
+	next, err := clk.Tick()
+	if err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	reopened, err := Open(path, "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got := reopened.Counter(); got != next {
+		t.Errorf("Counter after reopen = %d, want %d", got, next)
+	}
+}
+
+func TestOpenWithNewNodeIDStartsOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clock.json")
+
+	clk, err := Open(path, "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := clk.Tick(); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+
+	renamed, err := Open(path, "node-b")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if renamed.NodeID() != "node-b" || renamed.Counter() != 0 {
+		t.Errorf("Open(node-b) = %s at %d, want node-b at 0", renamed.NodeID(), renamed.Counter())
+	}
+}
+
+func TestOpenGeneratesNodeID(t *testing.T) {
+	clk, err := Open(filepath.Join(t.TempDir(), "clock.json"), "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !strings.HasPrefix(clk.NodeID(), "boundary-adapter-") {
+		t.Errorf("NodeID = %q, want a generated boundary-adapter ID", clk.NodeID())
+	}
+}
+
+func TestObserveOnlyRaisesCounter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clock.json")
+	clk, err := Open(path, "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if err := clk.Observe(41); err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+	if err := clk.Observe(7); err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+	if got, err := clk.Tick(); err != nil || got != 42 {
+		t.Errorf("Tick after Observe(41) = %d, %v, want 42", got, err)
+	}
+
+	reopened, err := Open(path, "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got := reopened.Counter(); got != 42 {
+		t.Errorf("Counter after reopen = %d, want 42", got)
+	}
+}
+
+func TestTickRefusesToOverflow(t *testing.T) {
+	clk, err := Open(filepath.Join(t.TempDir(), "clock.json"), "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := clk.Observe(math.MaxInt64); err != nil {
+		t.Fatalf("Observe: %v", err)
+	}
+
+	if got, err := clk.Tick(); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Tick at MaxInt64 = %d, %v, want %v", got, err, ErrExhausted)
+	}
+	if got := clk.Counter(); got != math.MaxInt64 {
+		t.Errorf("Counter = %d, want it left at MaxInt64", got)
+	}
+}
+
+func TestOpenRejectsCorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clock.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, "node-a"); err == nil {
+		t.Error("Open with a corrupt state file succeeded, want an error")
+	}
+}
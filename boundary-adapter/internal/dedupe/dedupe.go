@@ -0,0 +1,188 @@
+package dedupe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+)
+
+// staleAfter is how long a claim may stay in flight before it is taken to be
+// abandoned, e.g. by an instance that crashed; requests are answered well
+// within it, as the server's write timeout is 10s
+const staleAfter = time.Minute
+
+// Outcome tells the caller of Begin what to do with a request
+type Outcome int
+
+const (
+	// New means the key is unseen; the caller processes the request and must
+	// call Complete, Fail or Release
+	New Outcome = iota
+	// Replay means the request was already answered; the entry holds the result
+	Replay
+	// Retry means an earlier attempt failed after normalizing; the caller
+	// routes the entry's event again, with the same ID, and must call
+	// Complete or Fail
+	Retry
+	// InFlight means a request with the same key is still being processed
+	InFlight
+	// Mismatch means the idempotency key was used for different content
+	Mismatch
+)
+
+// outcomes maps the RDB Updater's claim outcomes to Outcome
+var outcomes = map[string]Outcome{
+	"new":       New,
+	"replay":    Replay,
+	"retry":     Retry,
+	"in_flight": InFlight,
+	"mismatch":  Mismatch,
+}
+
+// Keys is the table of idempotency keys shared by every instance, kept by
+// the RDB Updater
+type Keys interface {
+	ClaimIngestKey(ctx context.Context, claim models.IngestKeyClaim) (*models.IngestKeyClaimResult, error)
+	UpdateIngestKey(ctx context.Context, update models.IngestKeyUpdate) error
+}
+
+// Stats describes the dedupe store for health reporting
+type Stats struct {
+	Enabled      bool   `json:"enabled"`
+	Window       string `json:"window,omitempty"`
+	Fingerprints bool   `json:"fingerprints"`
+	Replays      uint64 `json:"replays"`
+	Retries      uint64 `json:"retries"`
+}
+
+// Store remembers ingested requests for the dedupe window
+// Keys are kept in a table shared by every instance, so a retry is recognized
+// whichever instance it reaches. A zero window disables deduplication, and
+// an empty key is never deduplicated: Begin returns New
+type Store struct {
+	keys         Keys
+	window       time.Duration
+	fingerprints bool
+
+	replays atomic.Uint64
+	retries atomic.Uint64
+}
+
+// NewStore creates a store keeping keys for window
+// With fingerprints set, requests without an Idempotency-Key are deduplicated
+// by their content and timestamp
+func NewStore(keys Keys, window time.Duration, fingerprints bool) *Store {
+	if window <= 0 {
+		log.Println("[Dedupe] Deduplication disabled")
+	} else {
+		log.Printf("[Dedupe] Deduplicating for %s (fingerprints: %t)", window, fingerprints)
+	}
+	return &Store{
+		keys:         keys,
+		window:       window,
+		fingerprints: fingerprints,
+	}
+}
+
+// Fingerprints reports whether requests without an Idempotency-Key are
+// deduplicated by their content
+func (s *Store) Fingerprints() bool {
+	return s.window > 0 && s.fingerprints
+}
+
+// Begin claims a key for a request with the given content fingerprint
+// The returned entry is set for every outcome but New
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (Outcome, *models.IngestKey, error) {
+	if s.disabled(key) {
+		return New, nil, nil
+	}
+
+	result, err := s.keys.ClaimIngestKey(ctx, models.IngestKeyClaim{
+		Key:               key,
+		Fingerprint:       fingerprint,
+		WindowSeconds:     int(s.window.Seconds()),
+		StaleAfterSeconds: int(staleAfter.Seconds()),
+	})
+	if err != nil {
+		return New, nil, err
+	}
+	outcome, ok := outcomes[result.Outcome]
+	if !ok {
+		return New, nil, fmt.Errorf("unknown claim outcome %q", result.Outcome)
+	}
+
+	switch outcome {
+	case New:
+		return New, nil, nil
+	case Replay:
+		s.replays.Add(1)
+	case Retry:
+		if result.Key.Event == nil {
+			// The failed attempt recorded no event, so it routed none; the
+			// claim is ours, to process as new
+			return New, nil, nil
+		}
+		s.retries.Add(1)
+	}
+	return outcome, result.Key, nil
+}
+
+// SetEvent records the normalized event of an in-flight request, so a retry
+// after a failure routes the same event
+func (s *Store) SetEvent(ctx context.Context, key string, event models.Event) error {
+	return s.update(ctx, models.IngestKeyUpdate{Key: key, Operation: "event", Event: &event})
+}
+
+// Complete stores the result of a request; duplicates get it replayed until
+// the window ends
+func (s *Store) Complete(ctx context.Context, key string, status int, response interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return s.update(ctx, models.IngestKeyUpdate{Key: key, Operation: "complete", Status: status, Response: body})
+}
+
+// Fail marks a request whose routing failed without a result; a retry routes
+// the recorded event again
+func (s *Store) Fail(ctx context.Context, key string) error {
+	return s.update(ctx, models.IngestKeyUpdate{Key: key, Operation: "fail"})
+}
+
+// Release forgets a request that never got as far as routing, so a retry is
+// processed as new
+func (s *Store) Release(ctx context.Context, key string) error {
+	return s.update(ctx, models.IngestKeyUpdate{Key: key, Operation: "release"})
+}
+
+// update records the progress of a claimed key
+func (s *Store) update(ctx context.Context, update models.IngestKeyUpdate) error {
+	if s.disabled(update.Key) {
+		return nil
+	}
+	return s.keys.UpdateIngestKey(ctx, update)
+}
+
+// disabled reports whether a key is exempt from deduplication
+func (s *Store) disabled(key string) bool {
+	return s.window <= 0 || key == ""
+}
+
+// Stats returns a snapshot of the store for health reporting
+func (s *Store) Stats() Stats {
+	stats := Stats{
+		Enabled:      s.window > 0,
+		Fingerprints: s.Fingerprints(),
+		Replays:      s.replays.Load(),
+		Retries:      s.retries.Load(),
+	}
+	if stats.Enabled {
+		stats.Window = s.window.String()
+	}
+	return stats
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,25 +11,44 @@ import (
 	"time"
 
 	"github.com/veps-service-480701/boundary-adapter/internal/client"
+	"github.com/veps-service-480701/boundary-adapter/internal/dedupe"
 	"github.com/veps-service-480701/boundary-adapter/internal/normalizer"
+	"github.com/veps-service-480701/boundary-adapter/internal/outbox"
 	"github.com/veps-service-480701/boundary-adapter/internal/router"
 	"github.com/veps-service-480701/boundary-adapter/pkg/models"
 )
 
 // Handler manages HTTP requests for the Boundary Adapter
 type Handler struct {
-	normalizer *normalizer.Normalizer
-	router     *router.Router
+	normalizer    *normalizer.Normalizer
+	router        *router.Router
+	contextOutbox *outbox.Outbox
+	dedupe        *dedupe.Store
 }
 
 // New creates a new HTTP handler
-func New(norm *normalizer.Normalizer, rtr *router.Router) *Handler {
+func New(norm *normalizer.Normalizer, rtr *router.Router, contextOutbox *outbox.Outbox, dedupeStore *dedupe.Store) *Handler {
 	return &Handler{
-		normalizer: norm,
-		router:     rtr,
+		normalizer:    norm,
+		router:        rtr,
+		contextOutbox: contextOutbox,
+		dedupe:        dedupeStore,
 	}
 }
 
+const (
+	// idempotencyKeyHeader lets a client name a request so retries of it are
+	// recognized even if their content differs in irrelevant ways
+	idempotencyKeyHeader = "Idempotency-Key"
+	// replayedHeader marks a response replayed from an earlier request
+	replayedHeader = "Idempotent-Replayed"
+
+	// Request body limits; every record the outbox persists must stay well
+	// under durablelog.MaxRecordSize
+	maxEventBody = 1 << 20  // one event
+	maxBatchBody = 10 << 20 // up to 100 events
+)
+
 // Response represents the standard API response format
 type Response struct {
 	Success   bool        `json:"success"`
@@ -55,6 +75,10 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Success:   true,
 		Message:   "Boundary Adapter is healthy",
 		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"context_outbox": h.contextOutbox.Stats(),
+			"dedupe":         h.dedupe.Stats(),
+		},
 	}
 	h.writeJSON(w, http.StatusOK, response)
 }
@@ -66,6 +90,8 @@ func (h *Handler) Warmup(w http.ResponseWriter, r *http.Request) {
 	// Pre-cache authentication tokens
 	vetoURL := os.Getenv("VETO_SERVICE_URL")
 	rdbURL := os.Getenv("RDB_UPDATER_URL")
+	submitterURL := os.Getenv("MONOLITH_SUBMITTER_URL")
+	fractureURL := os.Getenv("FRACTURE_HANDLER_URL")
 	
 	if vetoURL != "" {
 		client.GetIDToken(ctx, vetoURL)
@@ -74,6 +100,14 @@ func (h *Handler) Warmup(w http.ResponseWriter, r *http.Request) {
 	if rdbURL != "" {
 		client.GetIDToken(ctx, rdbURL)
 	}
+
+	if submitterURL != "" {
+		client.GetIDToken(ctx, submitterURL)
+	}
+
+	if fractureURL != "" {
+		client.GetIDToken(ctx, fractureURL)
+	}
 	
 	response := Response{
 		Success:   true,
@@ -101,8 +135,7 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 
 	// Parse raw event from request body
 	var rawEvent models.RawEvent
-	if err := json.NewDecoder(r.Body).Decode(&rawEvent); err != nil {
-		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	if !h.decodeJSON(w, r, maxEventBody, &rawEvent) {
 		return
 	}
 	defer r.Body.Close()
@@ -115,12 +148,66 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Normalize the event
-	event, err := h.normalizer.Normalize(rawEvent)
+	// Recognize retries before normalizing, which mints a new ID and ticks
+	// the vector clock
+	fingerprint, err := h.normalizer.Fingerprint(rawEvent)
 	if err != nil {
 		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("normalization failed: %v", err))
 		return
 	}
+	var key string
+	switch clientKey := r.Header.Get(idempotencyKeyHeader); {
+	case clientKey != "":
+		key = "key:" + rawEvent.Source + ":" + clientKey
+	case h.dedupe.Fingerprints() && !rawEvent.Timestamp.IsZero():
+		// Without a key, only the same content stamped with the same time is
+		// a retry; two equal payments a minute apart are two payments
+		key = "fingerprint:" + fingerprint + ":" + rawEvent.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+
+	outcome, entry, err := h.dedupe.Begin(r.Context(), key, fingerprint)
+	if err != nil {
+		log.Printf("[Handler] Failed to record idempotency key: %v", err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to record idempotency key: %v", err))
+		return
+	}
+
+	var event *models.Event
+	switch outcome {
+	case dedupe.Replay:
+		log.Printf("[Handler] Duplicate request (%s), replaying its result", key)
+		w.Header().Set(replayedHeader, "true")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(entry.Status)
+		w.Write(entry.Response)
+		return
+	case dedupe.InFlight:
+		h.writeError(w, http.StatusConflict, "a request with the same idempotency key is still being processed")
+		return
+	case dedupe.Mismatch:
+		h.writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different event", idempotencyKeyHeader))
+		return
+	case dedupe.Retry:
+		// Route the event of the failed attempt again; its ID makes the
+		// downstream services treat it as the same event
+		event = entry.Event
+		log.Printf("[Handler] Retrying event %s after an earlier failure", event.ID)
+	default:
+		// Normalize the event
+		event, err = h.normalizer.Normalize(rawEvent)
+		if err != nil {
+			h.releaseKey(r.Context(), key)
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("normalization failed: %v", err))
+			return
+		}
+		// A retry must route the same event, so it is recorded before routing
+		if err := h.dedupe.SetEvent(r.Context(), key, *event); err != nil {
+			log.Printf("[Handler] Failed to record event %s for idempotency key: %v", event.ID, err)
+			h.releaseKey(r.Context(), key)
+			h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to record idempotency key: %v", err))
+			return
+		}
+	}
 	
 	metrics.Timestamps["event_normalized"] = time.Now()
 
@@ -133,6 +220,30 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// Routing failed - likely veto service rejected or timeout
 		log.Printf("[Handler] Routing failed for event %s: %v", event.ID, err)
+
+		var vetoErr *models.VetoError
+		if errors.As(err, &vetoErr) {
+			// Surface the veto as the Veto Service does, with its details
+			response := Response{
+				Success:   false,
+				Error:     fmt.Sprintf("event processing failed: %v", err),
+				EventID:   event.ID.String(),
+				Timestamp: time.Now().UTC(),
+				Data: map[string]interface{}{
+					"failed_checks":   vetoErr.FailedChecks,
+					"reasons":         vetoErr.Reasons,
+					"ruleset_version": vetoErr.RulesetVersion,
+				},
+			}
+			// A veto is a final answer, so duplicates get it too
+			h.completeKey(r.Context(), key, http.StatusPreconditionFailed, response)
+			h.writeJSON(w, http.StatusPreconditionFailed, response)
+			return
+		}
+
+		if err := h.dedupe.Fail(context.WithoutCancel(r.Context()), key); err != nil {
+			log.Printf("[Handler] Warning: failed to record failure of event %s: %v", event.ID, err)
+		}
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("event processing failed: %v", err))
 		return
 	}
@@ -168,7 +279,7 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 	// Success response with detailed timing
 	response := Response{
 		Success:   true,
-		Message:   "Event ingested and routed successfully",
+		Message:   "Event ingested, routed and sealed successfully",
 		EventID:   event.ID.String(),
 		Timestamp: time.Now().UTC(),
 		Duration:  metrics.TotalDuration.String(),
@@ -176,6 +287,8 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 			"event":             event,
 			"integrity_success": routeResult.IntegritySuccess,
 			"context_success":   routeResult.ContextSuccess,
+			"seal_success":      routeResult.SealSuccess,
+			"seal":              routeResult.Seal,
 			"routing_duration":  routeResult.Duration.String(),
 			"performance_breakdown": map[string]float64{
 				"total_ms":         float64(metrics.TotalDuration.Microseconds()) / 1000.0,
@@ -192,9 +305,25 @@ func (h *Handler) IngestEvent(w http.ResponseWriter, r *http.Request) {
 		float64(metrics.TotalDuration.Microseconds())/1000.0,
 		float64(vepsInternal.Microseconds())/1000.0)
 	
+	h.completeKey(r.Context(), key, http.StatusOK, response)
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// completeKey stores the result of a request for its duplicates, even if
+// the client has gone away
+func (h *Handler) completeKey(ctx context.Context, key string, status int, response Response) {
+	if err := h.dedupe.Complete(context.WithoutCancel(ctx), key, status, response); err != nil {
+		log.Printf("[Handler] Warning: failed to store result for idempotency key: %v", err)
+	}
+}
+
+// releaseKey forgets a request that was rejected before routing
+func (h *Handler) releaseKey(ctx context.Context, key string) {
+	if err := h.dedupe.Release(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("[Handler] Warning: failed to release idempotency key: %v", err)
+	}
+}
+
 // IngestBatch handles batch event ingestion
 func (h *Handler) IngestBatch(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -206,8 +335,7 @@ func (h *Handler) IngestBatch(w http.ResponseWriter, r *http.Request) {
 
 	// Parse batch of raw events
 	var rawEvents []models.RawEvent
-	if err := json.NewDecoder(r.Body).Decode(&rawEvents); err != nil {
-		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	if !h.decodeJSON(w, r, maxBatchBody, &rawEvents) {
 		return
 	}
 	defer r.Body.Close()
@@ -245,7 +373,7 @@ func (h *Handler) IngestBatch(w http.ResponseWriter, r *http.Request) {
 	successCount := 0
 	failCount := 0
 	for _, result := range results {
-		if result != nil && result.IntegritySuccess {
+		if result != nil && result.IntegritySuccess && result.SealSuccess {
 			successCount++
 		} else {
 			failCount++
@@ -285,6 +413,24 @@ func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{})
 	}
 }
 
+// decodeJSON decodes a request body of at most limit bytes into v, answering
+// 413 or 400 itself when it cannot
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, limit int64, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", limit))
+		return false
+	}
+	h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	return false
+}
+
 // writeError writes an error response
 func (h *Handler) writeError(w http.ResponseWriter, status int, message string) {
 	response := Response{
@@ -1,29 +1,29 @@
 package normalizer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/veps-service-480701/boundary-adapter/internal/clock"
 	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
 // Normalizer handles the transformation of raw events into canonical Event format
 type Normalizer struct {
-	nodeID string // This VEPS instance's ID for vector clock
+	nodeID string       // This VEPS instance's ID for vector clock
+	clock  *clock.Clock // This VEPS instance's logical counter
 }
 
-// New creates a new Normalizer instance
-func New() *Normalizer {
-	// Get node ID from environment or generate one
-	nodeID := os.Getenv("VEPS_NODE_ID")
-	if nodeID == "" {
-		nodeID = uuid.New().String()
-	}
-
+// New creates a new Normalizer instance backed by the node's persistent clock
+func New(clk *clock.Clock) *Normalizer {
 	return &Normalizer{
-		nodeID: nodeID,
+		nodeID: clk.NodeID(),
+		clock:  clk,
 	}
 }
 
@@ -57,16 +57,21 @@ func (n *Normalizer) Normalize(raw models.RawEvent) (*models.Event, error) {
 	}
 
 	// Initialize vector clock for this event
-	vectorClock := models.VectorClock{
-		n.nodeID: time.Now().UnixNano(), // Use nanosecond timestamp as logical clock
-	}
+	vectorClock := models.VectorClock{}
 
 	// If incoming event has a vector clock, merge it
 	if incomingVC, ok := raw.Data["vector_clock"].(map[string]interface{}); ok {
 		existingVC := n.parseVectorClock(incomingVC)
 		vectorClock.Merge(existingVC)
 	}
-	vectorClock.Increment(n.nodeID)
+
+	// Advance this node's logical counter; the client's entry for this node
+	// is replaced, since only this node hands out its counters
+	counter, err := n.clock.Tick()
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance vector clock: %w", err)
+	}
+	vectorClock[n.nodeID] = counter
 
 	// Create the normalized event
 	event := &models.Event{
@@ -81,13 +86,37 @@ func (n *Normalizer) Normalize(raw models.RawEvent) (*models.Event, error) {
 			ReceivedAt:    time.Now().UTC(),
 			BoundaryNode:  n.nodeID,
 			CorrelationID: n.extractCorrelationID(raw.Data),
-			SchemaVersion: "1.0",
+			SchemaVersion: model.SchemaVersion,
 		},
 	}
 
 	return event, nil
 }
 
+// Fingerprint identifies a raw event by its content: source, type, actor
+// and evidence. Unlike Normalize it has no side effects, so retries can be
+// recognized before the vector clock ticks
+func (n *Normalizer) Fingerprint(raw models.RawEvent) (string, error) {
+	actor, err := n.extractActor(raw.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract actor: %w", err)
+	}
+
+	// Maps are marshalled with sorted keys, so equal content hashes equally
+	content, err := json.Marshal(map[string]any{
+		"source":   raw.Source,
+		"type":     raw.Data["type"],
+		"actor":    map[string]string{"id": actor.ID, "name": actor.Name, "type": actor.Type},
+		"evidence": n.extractEvidence(raw.Data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event content: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // extractActor pulls actor information from raw data
 func (n *Normalizer) extractActor(data map[string]any) (models.Actor, error) {
 	actor := models.Actor{
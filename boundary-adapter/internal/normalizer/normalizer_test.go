@@ -0,0 +1,48 @@
+package normalizer
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/veps-service-480701/boundary-adapter/internal/clock"
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+)
+
+func TestNormalizeIgnoresClientCounterForThisNode(t *testing.T) {
+	clk, err := clock.Open(filepath.Join(t.TempDir(), "clock.json"), "node-a")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	norm := New(clk)
+
+	raw := models.RawEvent{
+		Source: "test",
+		Data: map[string]any{
+			"type":    "payment",
+			"user_id": "user-1",
+			// As decoded from JSON
+			"vector_clock": map[string]interface{}{
+				"node-a": float64(math.MaxInt64),
+				"node-b": float64(5),
+			},
+		},
+	}
+
+	for want := int64(1); want <= 2; want++ {
+		event, err := norm.Normalize(raw)
+		if err != nil {
+			t.Fatalf("Normalize: %v", err)
+		}
+		if got := event.VectorClock["node-a"]; got != want {
+			t.Errorf("VectorClock[node-a] = %d, want %d", got, want)
+		}
+		if got := event.VectorClock["node-b"]; got != 5 {
+			t.Errorf("VectorClock[node-b] = %d, want 5", got)
+		}
+	}
+
+	if got := clk.Counter(); got != 2 {
+		t.Errorf("Counter = %d, want 2", got)
+	}
+}
@@ -0,0 +1,504 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+	"github.com/veps-service-480701/veps-common/durablelog"
+)
+
+const (
+	logName      = "outbox" // outbox.log and outbox.ack
+	deadFileName = "outbox.dead"
+
+	// compactAfterAcks rewrites the log once this many records were acknowledged
+	compactAfterAcks = 10000
+	// maxConcurrentActors bounds how many actors are delivered to in parallel
+	maxConcurrentActors = 8
+	// deliveryTimeout bounds a single delivery attempt
+	deliveryTimeout = 5 * time.Second
+	// initialBackoff is the retry delay after the first failure, doubled per attempt
+	initialBackoff = 500 * time.Millisecond
+	// maxBackoff caps the retry delay for a failing actor
+	maxBackoff = 1 * time.Minute
+	// idleWait is how long the drainer sleeps when nothing is ready
+	idleWait = 1 * time.Second
+	// drainPoll is how often Drain checks whether the outbox is empty
+	drainPoll = 50 * time.Millisecond
+	// maxRejectedAttempts is how many times a record the receiver rejects as
+	// invalid (4xx) is sent before it is dead-lettered; outages retry forever
+	maxRejectedAttempts = 5
+)
+
+// Sink delivers context updates to the RDB Updater
+type Sink interface {
+	SendUpdate(ctx context.Context, update models.ContextUpdate) error
+}
+
+// FractureSink delivers vetoed events to the Data Fracture Handler
+type FractureSink interface {
+	SendToFracture(ctx context.Context, fracture models.FractureRequest) error
+}
+
+// Record is a single context update or fracture persisted in the outbox log
+// Exactly one of Update and Fracture is set
+type Record struct {
+	Seq        uint64                  `json:"seq"`
+	ActorID    string                  `json:"actor_id"`
+	EnqueuedAt time.Time               `json:"enqueued_at"`
+	Update     *models.ContextUpdate   `json:"update,omitempty"`
+	Fracture   *models.FractureRequest `json:"fracture,omitempty"`
+}
+
+// queueKey orders records per actor; fractures get their own queue so a
+// Data Fracture Handler outage does not hold back the actor's context updates
+func (r *Record) queueKey() string {
+	if r.Fracture != nil {
+		return "fracture:" + r.ActorID
+	}
+	return r.ActorID
+}
+
+// eventID returns the ID of the event the record is about
+func (r *Record) eventID() string {
+	if r.Fracture != nil {
+		return r.Fracture.Event.ID.String()
+	}
+	return r.Update.Event.ID.String()
+}
+
+// deadRecord is a record the receiver kept rejecting
+type deadRecord struct {
+	Record
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// actorQueue holds the pending records of one actor in enqueue order
+type actorQueue struct {
+	records     []*Record
+	attempts    int
+	rejections  int // consecutive attempts the receiver rejected
+	nextAttempt time.Time
+	inFlight    bool
+}
+
+// Stats describes the current state of the outbox
+type Stats struct {
+	Depth        int    `json:"depth"`
+	Actors       int    `json:"actors"`
+	Fractures    int    `json:"fractures"`
+	OldestAge    string `json:"oldest_age,omitempty"`
+	Delivered    uint64 `json:"delivered"`
+	FailedTries  uint64 `json:"failed_attempts"`
+	DeadLettered uint64 `json:"dead_lettered"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+// Outbox is a durable, append-only queue for the context and fracture paths.
+// Records are appended to a durablelog and fsynced before Enqueue returns; a
+// background drainer delivers them with exponential backoff, keeping updates
+// for the same actor in order. Records the receiver keeps rejecting are moved
+// to a dead-letter log so they do not block their actor.
+type Outbox struct {
+	sink      Sink
+	fractures FractureSink
+
+	mu           sync.Mutex
+	records      *durablelog.Log[Record]
+	dead         *durablelog.DeadLetters
+	queues       map[string]*actorQueue
+	depth        int
+	delivered    uint64
+	failedTries  uint64
+	deadLettered uint64
+	lastError    string
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Open opens (or creates) the outbox in dir and recovers any pending records
+func Open(dir string, sink Sink, fractures FractureSink) (*Outbox, error) {
+	recordLog, pending, err := durablelog.Open(dir, logName, func(r *Record) *uint64 { return &r.Seq })
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+
+	dead, err := durablelog.OpenDeadLetters(filepath.Join(dir, deadFileName))
+	if err != nil {
+		recordLog.Close()
+		return nil, err
+	}
+
+	o := &Outbox{
+		sink:      sink,
+		fractures: fractures,
+		records:   recordLog,
+		dead:      dead,
+		queues:    make(map[string]*actorQueue),
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	for _, record := range pending {
+		if record.Update == nil && record.Fracture == nil {
+			continue
+		}
+		o.push(record)
+	}
+
+	log.Printf("[Outbox] Opened at %s with %d pending records", dir, o.depth)
+	return o, nil
+}
+
+// push adds a record to its queue (caller holds mu or owns o)
+func (o *Outbox) push(record *Record) {
+	key := record.queueKey()
+	q, ok := o.queues[key]
+	if !ok {
+		q = &actorQueue{}
+		o.queues[key] = q
+	}
+	q.records = append(q.records, record)
+	o.depth++
+}
+
+// compact rewrites the log with only pending records (caller holds mu)
+func (o *Outbox) compact() error {
+	pending := make([]*Record, 0, o.depth)
+	for _, q := range o.queues {
+		pending = append(pending, q.records...)
+	}
+	return o.records.Compact(pending)
+}
+
+// Enqueue durably appends a context update; it returns once the update is on disk
+func (o *Outbox) Enqueue(update models.ContextUpdate) error {
+	return o.append(&Record{
+		ActorID: update.Event.Actor.ID,
+		Update:  &update,
+	})
+}
+
+// SendToFracture satisfies router.FractureHandler by writing the fracture to
+// the outbox; delivery to the Data Fracture Handler happens in the background
+func (o *Outbox) SendToFracture(ctx context.Context, fracture models.FractureRequest) error {
+	return o.append(&Record{
+		ActorID:  fracture.Event.Actor.ID,
+		Fracture: &fracture,
+	})
+}
+
+// append assigns the record a sequence number and writes it to the log
+func (o *Outbox) append(record *Record) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	record.EnqueuedAt = time.Now().UTC()
+	if err := o.records.Append(record); err != nil {
+		return fmt.Errorf("failed to append to outbox: %w", err)
+	}
+
+	o.push(record)
+
+	// Wake the drainer without blocking
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// SendToRDB satisfies router.ContextHandler by writing the event to the outbox;
+// delivery to the RDB Updater happens in the background
+func (o *Outbox) SendToRDB(ctx context.Context, event models.Event) error {
+	return o.Enqueue(models.ContextUpdate{
+		Event:     event,
+		Operation: "upsert",
+		Route:     "rdb_updater",
+	})
+}
+
+// SendSeal satisfies router.ContextHandler by writing the ledger receipt to the
+// outbox; it is delivered after the event's upsert since both share the actor queue
+func (o *Outbox) SendSeal(ctx context.Context, event models.Event, seal *models.SubmitResponse) error {
+	return o.Enqueue(models.ContextUpdate{
+		Event:     event,
+		Operation: "seal",
+		Route:     "rdb_updater",
+		Seal: &models.SealRecord{
+			SequenceNumber:  seal.SequenceNumber,
+			EventHash:       seal.EventHash,
+			PreviousHash:    seal.PreviousHash,
+			SealedTimestamp: seal.SealedTimestamp,
+		},
+	})
+}
+
+// ReleaseHold satisfies router.ContextHandler by writing a hold release to the
+// outbox, for an event that passed or skipped the veto but will not be sealed
+func (o *Outbox) ReleaseHold(ctx context.Context, event models.Event) error {
+	return o.Enqueue(models.ContextUpdate{
+		Event:     event,
+		Operation: "release",
+		Route:     "rdb_updater",
+	})
+}
+
+// Start launches the background drainer
+func (o *Outbox) Start() {
+	go o.drain()
+}
+
+// drain delivers ready records until Close is called
+func (o *Outbox) drain() {
+	defer close(o.done)
+
+	for {
+		batch, wait := o.ready()
+
+		if len(batch) == 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-o.stop:
+				timer.Stop()
+				return
+			case <-o.wake:
+				timer.Stop()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for key, record := range batch {
+			wg.Add(1)
+			go func(key string, record *Record) {
+				defer wg.Done()
+				o.deliver(key, record)
+			}(key, record)
+		}
+		wg.Wait()
+
+		select {
+		case <-o.stop:
+			return
+		default:
+		}
+	}
+}
+
+// ready returns the head record of each queue that is due for delivery, and
+// how long to wait when none are
+func (o *Outbox) ready() (map[string]*Record, time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	wait := idleWait
+	batch := make(map[string]*Record)
+
+	for key, q := range o.queues {
+		if q.inFlight || len(q.records) == 0 {
+			continue
+		}
+		if q.nextAttempt.After(now) {
+			if d := q.nextAttempt.Sub(now); d < wait {
+				wait = d
+			}
+			continue
+		}
+		q.inFlight = true
+		batch[key] = q.records[0]
+		if len(batch) >= maxConcurrentActors {
+			break
+		}
+	}
+
+	return batch, wait
+}
+
+// deliver sends one record and acknowledges or schedules a retry
+func (o *Outbox) deliver(key string, record *Record) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	var err error
+	if record.Fracture != nil {
+		err = o.fractures.SendToFracture(ctx, *record.Fracture)
+	} else {
+		err = o.sink.SendUpdate(ctx, *record.Update)
+	}
+	cancel()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	q := o.queues[key]
+	q.inFlight = false
+
+	if err != nil {
+		q.attempts++
+		o.failedTries++
+		o.lastError = err.Error()
+
+		var rejected *models.RejectedError
+		if errors.As(err, &rejected) {
+			q.rejections++
+		} else {
+			q.rejections = 0
+		}
+
+		if q.rejections >= maxRejectedAttempts {
+			dlErr := o.deadLetter(record, q.attempts, err)
+			if dlErr == nil {
+				log.Printf("[Outbox] ERROR: Record %d for event %s rejected %d times, moved to dead letter: %v",
+					record.Seq, record.eventID(), q.rejections, err)
+				o.deadLettered++
+				o.ack(key, q, record)
+				return
+			}
+			// Keep the record pending rather than lose it
+			log.Printf("[Outbox] ERROR: failed to dead-letter record %d: %v", record.Seq, dlErr)
+		}
+
+		backoff := initialBackoff << (q.attempts - 1)
+		if backoff > maxBackoff || backoff <= 0 {
+			backoff = maxBackoff
+		}
+		q.nextAttempt = time.Now().Add(backoff)
+
+		log.Printf("[Outbox] Delivery of record %d for event %s failed (attempt %d, retry in %s): %v",
+			record.Seq, record.eventID(), q.attempts, backoff, err)
+		return
+	}
+
+	o.delivered++
+	o.ack(key, q, record)
+}
+
+// deadLetter appends a rejected record to the dead-letter log (caller holds mu)
+func (o *Outbox) deadLetter(record *Record, attempts int, cause error) error {
+	return o.dead.Write(deadRecord{
+		Record:    *record,
+		Attempts:  attempts,
+		LastError: cause.Error(),
+		FailedAt:  time.Now().UTC(),
+	})
+}
+
+// ack removes a queue's head record and compacts the log when due (caller holds mu)
+func (o *Outbox) ack(key string, q *actorQueue, record *Record) {
+	// Losing an ack only causes a redelivery: context updates are idempotent,
+	// and the Data Fracture Handler derives a fracture's ID from the event and
+	// veto node, so a redelivered fracture maps onto the one already stored
+	if err := o.records.Ack(record.Seq); err != nil {
+		log.Printf("[Outbox] Warning: %v", err)
+	}
+
+	q.records = q.records[1:]
+	q.attempts = 0
+	q.rejections = 0
+	q.nextAttempt = time.Time{}
+	if len(q.records) == 0 {
+		delete(o.queues, key)
+	}
+
+	o.depth--
+
+	// In-flight records of other actors are still queued, so they survive compaction
+	if o.depth == 0 || o.records.Acked() >= compactAfterAcks {
+		if err := o.compact(); err != nil {
+			log.Printf("[Outbox] Warning: compaction failed: %v", err)
+		}
+	}
+}
+
+// Stats returns a snapshot of the outbox state for health reporting
+func (o *Outbox) Stats() Stats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stats := Stats{
+		Depth:        o.depth,
+		Delivered:    o.delivered,
+		FailedTries:  o.failedTries,
+		DeadLettered: o.deadLettered,
+		LastError:    o.lastError,
+	}
+
+	var oldest time.Time
+	for _, q := range o.queues {
+		if len(q.records) == 0 {
+			continue
+		}
+		if q.records[0].Fracture != nil {
+			stats.Fractures += len(q.records)
+		} else {
+			stats.Actors++
+		}
+		head := q.records[0].EnqueuedAt
+		if oldest.IsZero() || head.Before(oldest) {
+			oldest = head
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestAge = time.Since(oldest).Round(time.Millisecond).String()
+	}
+
+	return stats
+}
+
+// Drain retries every pending record now and waits until the outbox is empty
+// or ctx is done, for a graceful shutdown; failed records keep backing off
+func (o *Outbox) Drain(ctx context.Context) error {
+	o.mu.Lock()
+	for _, q := range o.queues {
+		q.nextAttempt = time.Time{}
+	}
+	o.mu.Unlock()
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	ticker := time.NewTicker(drainPoll)
+	defer ticker.Stop()
+
+	for {
+		o.mu.Lock()
+		depth := o.depth
+		o.mu.Unlock()
+		if depth == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d records still pending: %w", depth, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the drainer and closes the outbox files; pending records stay on disk
+func (o *Outbox) Close() error {
+	close(o.stop)
+	<-o.done
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.dead.Close()
+	return o.records.Close()
+}
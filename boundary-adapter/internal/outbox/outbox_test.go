@@ -0,0 +1,263 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/boundary-adapter/pkg/models"
+)
+
+// fakeSink records deliveries as "<actor>/<operation>" and fails the ones
+// fail returns an error for
+type fakeSink struct {
+	mu        sync.Mutex
+	fail      func(label string) error
+	delivered []string
+	attempts  map[string]int
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{attempts: make(map[string]int)}
+}
+
+func (s *fakeSink) record(label string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts[label]++
+	if s.fail != nil {
+		if err := s.fail(label); err != nil {
+			return err
+		}
+	}
+	s.delivered = append(s.delivered, label)
+	return nil
+}
+
+func (s *fakeSink) SendUpdate(ctx context.Context, update models.ContextUpdate) error {
+	return s.record(update.Event.Actor.ID + "/" + update.Operation)
+}
+
+func (s *fakeSink) SendToFracture(ctx context.Context, fracture models.FractureRequest) error {
+	return s.record(fracture.Event.Actor.ID + "/fracture")
+}
+
+func (s *fakeSink) setFail(fail func(label string) error) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+func (s *fakeSink) snapshot() ([]string, map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attempts := make(map[string]int, len(s.attempts))
+	for k, v := range s.attempts {
+		attempts[k] = v
+	}
+	return append([]string(nil), s.delivered...), attempts
+}
+
+func openOutbox(t *testing.T, dir string, sink *fakeSink) *Outbox {
+	t.Helper()
+	o, err := Open(dir, sink, sink)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	o.Start()
+	return o
+}
+
+func testEvent(actorID string) models.Event {
+	return models.Event{ID: uuid.New(), Type: "payment", Actor: models.Actor{ID: actorID}}
+}
+
+func drain(t *testing.T, o *Outbox) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := o.Drain(ctx); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+}
+
+var errUnavailable = errors.New("connection refused")
+
+func TestOutboxRedeliversAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	down := newFakeSink()
+	down.fail = func(string) error { return errUnavailable }
+	o := openOutbox(t, dir, down)
+
+	event := testEvent("actor-1")
+	o.SendToRDB(context.Background(), event)
+	o.SendSeal(context.Background(), event, &models.SubmitResponse{SequenceNumber: 1})
+	o.SendToFracture(context.Background(), models.FractureRequest{Event: testEvent("actor-2"), VetoNode: "veto-1"})
+	if err := o.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	up := newFakeSink()
+	reopened := openOutbox(t, dir, up)
+	defer reopened.Close()
+	if depth := reopened.Stats().Depth; depth != 3 {
+		t.Fatalf("depth after reopen = %d, want 3", depth)
+	}
+	drain(t, reopened)
+
+	delivered, _ := up.snapshot()
+	var updates []string
+	fractures := 0
+	for _, label := range delivered {
+		if label == "actor-2/fracture" {
+			fractures++
+			continue
+		}
+		updates = append(updates, label)
+	}
+	if want := []string{"actor-1/upsert", "actor-1/seal"}; !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %v, want %v in order", updates, want)
+	}
+	if fractures != 1 {
+		t.Errorf("fracture delivered %d times, want 1", fractures)
+	}
+}
+
+func TestOutboxRetriesInActorOrder(t *testing.T) {
+	sink := newFakeSink()
+	failed := false
+	sink.fail = func(label string) error {
+		if label == "actor-1/upsert" && !failed {
+			failed = true
+			return errUnavailable
+		}
+		return nil
+	}
+	o := openOutbox(t, t.TempDir(), sink)
+	defer o.Close()
+
+	event := testEvent("actor-1")
+	o.SendToRDB(context.Background(), event)
+	o.ReleaseHold(context.Background(), event)
+	drain(t, o)
+
+	delivered, attempts := sink.snapshot()
+	if want := []string{"actor-1/upsert", "actor-1/release"}; !reflect.DeepEqual(delivered, want) {
+		t.Errorf("delivered = %v, want %v", delivered, want)
+	}
+	if attempts["actor-1/upsert"] != 2 || attempts["actor-1/release"] != 1 {
+		t.Errorf("attempts = %v, want the upsert retried once and the release sent once", attempts)
+	}
+}
+
+func TestOutboxRedeliversUnacknowledgedRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	// actor-2 stays undeliverable, so the log is not compacted away
+	sink := newFakeSink()
+	sink.fail = func(label string) error {
+		if label == "actor-2/upsert" {
+			return errUnavailable
+		}
+		return nil
+	}
+	o := openOutbox(t, dir, sink)
+	o.SendToRDB(context.Background(), testEvent("actor-1"))
+	o.SendToRDB(context.Background(), testEvent("actor-2"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	o.Drain(ctx)
+	cancel()
+	o.Close()
+
+	if delivered, _ := sink.snapshot(); !reflect.DeepEqual(delivered, []string{"actor-1/upsert"}) {
+		t.Fatalf("delivered = %v, want [actor-1/upsert]", delivered)
+	}
+
+	// Acks are not synced; a crash that loses them redelivers the record
+	if err := os.Truncate(filepath.Join(dir, logName+".ack"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	again := newFakeSink()
+	reopened := openOutbox(t, dir, again)
+	defer reopened.Close()
+	drain(t, reopened)
+
+	_, attempts := again.snapshot()
+	if attempts["actor-1/upsert"] != 1 || attempts["actor-2/upsert"] != 1 {
+		t.Errorf("attempts after losing the acks = %v, want both records delivered again", attempts)
+	}
+}
+
+func TestOutboxDeadLettersRejectedRecords(t *testing.T) {
+	dir := t.TempDir()
+	sink := newFakeSink()
+	sink.fail = func(label string) error {
+		if label == "actor-1/upsert" {
+			return models.NewRejectedError("RDB Updater", 400, "invalid event")
+		}
+		return nil
+	}
+	o := openOutbox(t, dir, sink)
+	defer o.Close()
+
+	event := testEvent("actor-1")
+	o.SendToRDB(context.Background(), event)
+	o.ReleaseHold(context.Background(), event)
+
+	// Each Drain retries the record at once instead of waiting out its backoff
+	deadline := time.Now().Add(10 * time.Second)
+	for o.Stats().DeadLettered == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the record to be dead-lettered")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		o.Drain(ctx)
+		cancel()
+	}
+	drain(t, o)
+
+	delivered, attempts := sink.snapshot()
+	if attempts["actor-1/upsert"] != maxRejectedAttempts {
+		t.Errorf("rejected record sent %d times, want %d", attempts["actor-1/upsert"], maxRejectedAttempts)
+	}
+	if !reflect.DeepEqual(delivered, []string{"actor-1/release"}) {
+		t.Errorf("delivered = %v, want the release once the upsert was dead-lettered", delivered)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, deadFileName)); err != nil || len(data) == 0 {
+		t.Errorf("dead-letter log is empty (%v)", err)
+	}
+}
+
+func TestOutboxOutagesNeverDeadLetter(t *testing.T) {
+	sink := newFakeSink()
+	sink.fail = func(string) error { return errUnavailable }
+	o := openOutbox(t, t.TempDir(), sink)
+	defer o.Close()
+
+	o.SendToRDB(context.Background(), testEvent("actor-1"))
+	for i := 0; i < maxRejectedAttempts+2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		o.Drain(ctx)
+		cancel()
+	}
+
+	stats := o.Stats()
+	if stats.Depth != 1 || stats.DeadLettered != 0 {
+		t.Fatalf("stats = %+v, want the record still pending", stats)
+	}
+
+	sink.setFail(nil)
+	drain(t, o)
+	if stats := o.Stats(); stats.Delivered != 1 {
+		t.Errorf("delivered = %d, want 1", stats.Delivered)
+	}
+}
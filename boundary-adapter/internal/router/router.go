@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -11,11 +12,16 @@ import (
 )
 
 // Router handles the concurrent split of normalized events
-// Sends to both Integrity Path (Veto Service) and Context Path (RDB Updater)
+// Sends to both Integrity Path (Veto Service) and Context Path (RDB Updater),
+// then seals events that pass the veto via the Monolith Submitter and reports
+// vetoed events to the Data Fracture Handler
 type Router struct {
 	integrityHandler IntegrityHandler
 	contextHandler   ContextHandler
-	timeout          time.Duration
+	sealHandler      SealHandler
+	fractureHandler  FractureHandler
+	timeout          time.Duration // bounds the veto decision
+	sealTimeout      time.Duration // bounds the ledger seal, which starts after the veto
 }
 
 // IntegrityHandler defines the interface for sending to Veto Service
@@ -24,20 +30,40 @@ type IntegrityHandler interface {
 }
 
 // ContextHandler defines the interface for sending to RDB Updater
+// In production this is the durable outbox, which delivers to the RDB Updater in the background
 type ContextHandler interface {
 	SendToRDB(ctx context.Context, event models.Event) error
+	SendSeal(ctx context.Context, event models.Event, seal *models.SubmitResponse) error
+	ReleaseHold(ctx context.Context, event models.Event) error
+}
+
+// SealHandler defines the interface for sending certified events to the Monolith Submitter
+type SealHandler interface {
+	SubmitEvent(ctx context.Context, event models.Event) (*models.SubmitResponse, error)
+}
+
+// FractureHandler defines the interface for sending vetoed events to the Data Fracture Handler
+// In production this is the durable outbox, which retries delivery until it succeeds
+type FractureHandler interface {
+	SendToFracture(ctx context.Context, fracture models.FractureRequest) error
 }
 
 // New creates a new Router with the specified handlers
-func New(integrity IntegrityHandler, context ContextHandler, timeout time.Duration) *Router {
+func New(integrity IntegrityHandler, context ContextHandler, seal SealHandler, fracture FractureHandler, timeout, sealTimeout time.Duration) *Router {
 	if timeout == 0 {
 		timeout = 10 * time.Second // Default timeout
 	}
+	if sealTimeout == 0 {
+		sealTimeout = 5 * time.Second
+	}
 
 	return &Router{
 		integrityHandler: integrity,
 		contextHandler:   context,
+		sealHandler:      seal,
+		fractureHandler:  fracture,
 		timeout:          timeout,
+		sealTimeout:      sealTimeout,
 	}
 }
 
@@ -48,12 +74,18 @@ type RouteResult struct {
 	IntegrityError   error
 	ContextSuccess   bool
 	ContextError     error
+	Vetoed           bool
+	SealSuccess      bool
+	SealError        error
+	Seal             *models.SubmitResponse
 	Duration         time.Duration
 }
 
 // Route performs the concurrent split - sends event to both paths simultaneously
 // The Integrity Path is BLOCKING (we wait for veto decision)
 // The Context Path is NON-BLOCKING (fire and forget)
+// The Seal Path runs only after the veto passes and is BLOCKING (we return the ledger receipt)
+// The Fracture Path runs only after a veto and is NON-BLOCKING (delivered with retries)
 func (r *Router) Route(ctx context.Context, event models.Event) (*RouteResult, error) {
 	startTime := time.Now()
 
@@ -85,7 +117,7 @@ func (r *Router) Route(ctx context.Context, event models.Event) (*RouteResult, e
 		integrityDone <- err
 	}()
 
-	// CONTEXT PATH - Non-blocking, fire and forget
+	// CONTEXT PATH - Non-blocking, written to the durable outbox and retried from there
 	go func() {
 		defer wg.Done()
 		// Use a separate context that won't be cancelled if integrity fails
@@ -106,22 +138,53 @@ func (r *Router) Route(ctx context.Context, event models.Event) (*RouteResult, e
 	case err := <-integrityDone:
 		if err != nil {
 			// Integrity path failed - this is a critical failure
+			var vetoErr *models.VetoError
+			if errors.As(err, &vetoErr) {
+				// FRACTURE PATH - Every veto is recorded in the audit store
+				result.Vetoed = true
+				r.reportFracture(event, vetoErr)
+			}
+			r.releaseHold(event)
 			result.Duration = time.Since(startTime)
 			return result, fmt.Errorf("integrity path failed: %w", err)
 		}
 	case <-routeCtx.Done():
 		// Timeout exceeded
+		r.releaseHold(event)
 		result.Duration = time.Since(startTime)
 		return result, fmt.Errorf("integrity path timeout exceeded: %w", routeCtx.Err())
 	}
 
+	// SEAL PATH - Only events that passed the veto are submitted to the ledger
+	// The veto has used up most of routeCtx, so the seal gets its own budget
+	sealCtx, sealCancel := context.WithTimeout(ctx, r.sealTimeout)
+	defer sealCancel()
+
+	seal, err := r.sealHandler.SubmitEvent(sealCtx, event)
+	if err != nil {
+		log.Printf("[Router] Seal path failed for event %s: %v", event.ID, err)
+		result.SealError = err
+		r.releaseHold(event)
+		result.Duration = time.Since(startTime)
+		return result, fmt.Errorf("seal path failed: %w", err)
+	}
+	result.SealSuccess = true
+	result.Seal = seal
+
 	// Wait for context path to complete (for logging purposes only)
 	// We don't fail if context path has issues
 	wg.Wait()
 
+	// Record the sequence number in the read model; queued after the upsert above
+	if result.ContextSuccess {
+		if err := r.contextHandler.SendSeal(context.Background(), event, seal); err != nil {
+			log.Printf("[Router] Failed to queue seal for event %s (non-blocking): %v", event.ID, err)
+		}
+	}
+
 	result.Duration = time.Since(startTime)
 
-	// Success if integrity path succeeded (context path failure is tolerated)
+	// Success if integrity and seal paths succeeded (context path failure is tolerated)
 	if !result.IntegritySuccess {
 		return result, fmt.Errorf("integrity validation failed")
 	}
@@ -129,6 +192,48 @@ func (r *Router) Route(ctx context.Context, event models.Event) (*RouteResult, e
 	return result, nil
 }
 
+// releaseHold frees any funds the veto held for an event that will not be
+// sealed. A hold placed after the release, by a veto still running when the
+// decision timed out, expires on its own
+func (r *Router) releaseHold(event models.Event) {
+	if err := r.contextHandler.ReleaseHold(context.Background(), event); err != nil {
+		log.Printf("[Router] Failed to queue hold release for event %s (non-blocking): %v", event.ID, err)
+	}
+}
+
+// reportFracture hands a vetoed event to the fracture handler, which persists
+// it for background delivery to the Data Fracture Handler
+func (r *Router) reportFracture(event models.Event, vetoErr *models.VetoError) {
+	fracture := models.FractureRequest{
+		Event:         event,
+		FailedChecks:  vetoErr.FailedChecks,
+		Reasons:       vetoErr.Reasons,
+		VetoNode:      vetoErr.VetoNode,
+		Duration:      vetoErr.Duration,
+		CorrelationID: event.Metadata.CorrelationID,
+		Metadata: map[string]interface{}{
+			"boundary_node": event.Metadata.BoundaryNode,
+			"reported_by":   "boundary-adapter",
+		},
+	}
+
+	if vetoErr.RulesetVersion != "" {
+		fracture.Metadata["ruleset_version"] = vetoErr.RulesetVersion
+	}
+
+	// The fracture handler rejects requests without failed checks
+	if len(fracture.FailedChecks) == 0 {
+		fracture.FailedChecks = []string{"unknown"}
+	}
+
+	// Use a separate context so the write outlives the client request
+	if err := r.fractureHandler.SendToFracture(context.Background(), fracture); err != nil {
+		log.Printf("[Router] ERROR: Failed to queue fracture for vetoed event %s: %v", event.ID, err)
+		return
+	}
+	log.Printf("[Router] Fracture queued for vetoed event %s", event.ID)
+}
+
 // RouteBatch routes multiple events concurrently with rate limiting
 func (r *Router) RouteBatch(ctx context.Context, events []models.Event, maxConcurrent int) []*RouteResult {
 	if maxConcurrent <= 0 {
@@ -204,4 +309,81 @@ func (m *MockContextHandler) SendToRDB(ctx context.Context, event models.Event)
 
 	log.Printf("[MockContext] Event %s sent to RDB updater", event.ID)
 	return nil
+}
+
+func (m *MockContextHandler) SendSeal(ctx context.Context, event models.Event, seal *models.SubmitResponse) error {
+	if m.Fail {
+		return fmt.Errorf("mock context handler failure")
+	}
+
+	log.Printf("[MockContext] Seal %d for event %s sent to RDB updater", seal.SequenceNumber, event.ID)
+	return nil
+}
+
+func (m *MockContextHandler) ReleaseHold(ctx context.Context, event models.Event) error {
+	if m.Fail {
+		return fmt.Errorf("mock context handler failure")
+	}
+
+	log.Printf("[MockContext] Hold release for event %s sent to RDB updater", event.ID)
+	return nil
+}
+
+// MockFractureHandler is a mock implementation for testing
+type MockFractureHandler struct {
+	Delay time.Duration
+	Fail  bool
+}
+
+func (m *MockFractureHandler) SendToFracture(ctx context.Context, fracture models.FractureRequest) error {
+	if m.Delay > 0 {
+		select {
+		case <-time.After(m.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if m.Fail {
+		return fmt.Errorf("mock fracture handler failure")
+	}
+
+	log.Printf("[MockFracture] Event %s sent to fracture handler (checks: %v)", fracture.Event.ID, fracture.FailedChecks)
+	return nil
+}
+
+// MockSealHandler is a mock implementation for testing
+type MockSealHandler struct {
+	Delay time.Duration
+	Fail  bool
+
+	mu       sync.Mutex
+	sequence uint64
+}
+
+func (m *MockSealHandler) SubmitEvent(ctx context.Context, event models.Event) (*models.SubmitResponse, error) {
+	if m.Delay > 0 {
+		select {
+		case <-time.After(m.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if m.Fail {
+		return nil, fmt.Errorf("mock seal handler failure")
+	}
+
+	m.mu.Lock()
+	m.sequence++
+	sequence := m.sequence
+	m.mu.Unlock()
+
+	log.Printf("[MockSeal] Event %s sealed with sequence %d", event.ID, sequence)
+	return &models.SubmitResponse{
+		Success:         true,
+		SequenceNumber:  sequence,
+		EventID:         event.ID.String(),
+		SealedTimestamp: time.Now().UTC(),
+	}, nil
 }
\ No newline at end of file
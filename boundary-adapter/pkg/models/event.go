@@ -1,45 +1,21 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
-// Event represents a normalized event in the VEPS system
-// This is the canonical format after boundary adapter normalization
-type Event struct {
-	ID           uuid.UUID         `json:"id"`
-	Type         string            `json:"type"`
-	Source       string            `json:"source"`
-	Timestamp    time.Time         `json:"timestamp"`
-	Actor        Actor             `json:"actor"`
-	Evidence     map[string]any    `json:"evidence"`
-	VectorClock  VectorClock       `json:"vector_clock"`
-	Metadata     EventMetadata     `json:"metadata,omitempty"`
-}
-
-// Actor represents the entity that triggered the event
-type Actor struct {
-	ID       string            `json:"id"`
-	Name     string            `json:"name"`
-	Type     string            `json:"type,omitempty"` // e.g., "user", "service", "system"
-	Metadata map[string]string `json:"metadata,omitempty"`
-}
-
-// VectorClock tracks causality for distributed event ordering
-// Maps node/service ID to logical clock value
-type VectorClock map[string]int64
-
-// EventMetadata contains additional context for tracking and debugging
-type EventMetadata struct {
-	ReceivedAt      time.Time `json:"received_at"`
-	ProcessedAt     time.Time `json:"processed_at,omitempty"`
-	BoundaryNode    string    `json:"boundary_node"`     // Which VEPS instance processed this
-	CorrelationID   string    `json:"correlation_id"`    // For distributed tracing
-	RetryCount      int       `json:"retry_count"`
-	SchemaVersion   string    `json:"schema_version"`
-}
+// Event, Actor, VectorClock and EventMetadata are the canonical VEPS types,
+// shared with the other services through veps-common
+type (
+	Event         = model.Event
+	Actor         = model.Actor
+	VectorClock   = model.VectorClock
+	EventMetadata = model.EventMetadata
+)
 
 // RawEvent represents the incoming event before normalization
 type RawEvent struct {
@@ -57,45 +33,117 @@ type IntegrityCheckResult struct {
 
 // ContextUpdate is sent down the context path to RDB Updater
 type ContextUpdate struct {
-	Event     Event  `json:"event"`
-	Operation string `json:"operation"` // "upsert", "append", etc.
-	Route     string `json:"route"`     // "rdb_updater"
+	Event     Event       `json:"event"`
+	Operation string      `json:"operation"`      // "upsert", "seal" or "release"
+	Route     string      `json:"route"`          // "rdb_updater"
+	Seal      *SealRecord `json:"seal,omitempty"` // set for "seal" updates
 }
 
-// Increment increments the vector clock for the given node
-func (vc VectorClock) Increment(nodeID string) {
-	vc[nodeID]++
+// IngestKeyClaim claims an idempotency key in the RDB Updater's shared table
+type IngestKeyClaim struct {
+	Key               string `json:"key"`
+	Fingerprint       string `json:"fingerprint"`
+	WindowSeconds     int    `json:"window_seconds"`      // how long the key is kept
+	StaleAfterSeconds int    `json:"stale_after_seconds"` // when an in-flight claim counts as abandoned
 }
 
-// Merge merges another vector clock into this one (takes max of each entry)
-func (vc VectorClock) Merge(other VectorClock) {
-	for nodeID, timestamp := range other {
-		if vc[nodeID] < timestamp {
-			vc[nodeID] = timestamp
-		}
-	}
+// IngestKey is the record kept for one idempotency key
+type IngestKey struct {
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	State       string          `json:"state"`              // "in_flight", "done" or "failed"
+	Event       *Event          `json:"event,omitempty"`    // set once the request is normalized
+	Status      int             `json:"status,omitempty"`   // HTTP status of the stored result
+	Response    json.RawMessage `json:"response,omitempty"` // body of the stored result
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// IngestKeyClaimResult is the RDB Updater's answer to an IngestKeyClaim
+type IngestKeyClaimResult struct {
+	Outcome string     `json:"outcome"` // "new", "retry", "replay", "in_flight" or "mismatch"
+	Key     *IngestKey `json:"key"`
+}
+
+// IngestKeyUpdate records the progress of a claimed idempotency key
+type IngestKeyUpdate struct {
+	Key       string          `json:"key"`
+	Operation string          `json:"operation"`          // "event", "complete", "fail" or "release"
+	Event     *Event          `json:"event,omitempty"`    // set for "event"
+	Status    int             `json:"status,omitempty"`   // set for "complete"
+	Response  json.RawMessage `json:"response,omitempty"` // set for "complete"
+}
+
+// SealRecord is the ledger receipt the RDB Updater stores against a sealed event
+type SealRecord struct {
+	SequenceNumber  uint64    `json:"sequence_number"`
+	EventHash       string    `json:"event_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	SealedTimestamp time.Time `json:"sealed_timestamp"`
 }
 
-// HappensBefore checks if this vector clock happens before another
-func (vc VectorClock) HappensBefore(other VectorClock) bool {
-	lessThanOrEqual := false
-	strictlyLess := false
-
-	for nodeID, timestamp := range vc {
-		otherTimestamp, exists := other[nodeID]
-		if !exists || timestamp > otherTimestamp {
-			return false
-		}
-		if timestamp < otherTimestamp {
-			strictlyLess = true
-		}
-		lessThanOrEqual = true
+// SubmitResponse is returned by the Monolith Submitter once an event is sealed
+// by the ImmutableLedger
+type SubmitResponse struct {
+	Success         bool      `json:"success"`
+	SequenceNumber  uint64    `json:"sequence_number"`
+	EventID         string    `json:"event_id"`
+	EventHash       string    `json:"event_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	SealedTimestamp time.Time `json:"sealed_timestamp"`
+	CommitLatencyMS int64     `json:"commit_latency_ms"`
+	Message         string    `json:"message,omitempty"`
+}
+
+// FractureRequest is sent to the Data Fracture Handler when the Veto Service
+// rejects an event
+type FractureRequest struct {
+	Event         Event                  `json:"event"`
+	FailedChecks  []string               `json:"failed_checks"`
+	Reasons       []string               `json:"reasons"`
+	VetoNode      string                 `json:"veto_node"`
+	Duration      string                 `json:"duration,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// VetoError is returned down the integrity path when the Veto Service
+// rejects an event (HTTP 412)
+type VetoError struct {
+	FailedChecks   []string
+	Reasons        []string
+	VetoNode       string
+	Duration       string
+	RulesetVersion string // veto rules the event was checked against
+}
+
+func (e *VetoError) Error() string {
+	if len(e.Reasons) == 0 {
+		return "event vetoed by Veto Service"
 	}
+	return fmt.Sprintf("event vetoed: %v", e.Reasons)
+}
+
+// RejectedError is returned when a downstream service rejects a request with
+// a client error (4xx), so resending the same request will not succeed
+type RejectedError struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
 
-	return lessThanOrEqual && strictlyLess
+func (e *RejectedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s rejected the request with status %d", e.Service, e.StatusCode)
+	}
+	return fmt.Sprintf("%s error: %s", e.Service, e.Message)
 }
 
-// IsConcurrent checks if two vector clocks are concurrent (neither happens before the other)
-func (vc VectorClock) IsConcurrent(other VectorClock) bool {
-	return !vc.HappensBefore(other) && !other.HappensBefore(vc)
-}
\ No newline at end of file
+// NewRejectedError returns a RejectedError for permanent client errors and nil
+// for statuses worth retrying (408 Request Timeout, 429 Too Many Requests, 5xx)
+func NewRejectedError(service string, statusCode int, message string) error {
+	if statusCode < 400 || statusCode > 499 || statusCode == 408 || statusCode == 429 {
+		return nil
+	}
+	return &RejectedError{Service: service, StatusCode: statusCode, Message: message}
+}
@@ -7,11 +7,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/veps-service-480701/data-fracture-handler/internal/client"
 	"github.com/veps-service-480701/data-fracture-handler/internal/handler"
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+	"github.com/veps-service-480701/data-fracture-handler/internal/queue"
+	"github.com/veps-service-480701/data-fracture-handler/internal/replay"
+	"github.com/veps-service-480701/data-fracture-handler/internal/stats"
 	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/internal/triage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
 )
 
 func main() {
@@ -20,18 +28,54 @@ func main() {
 	// Load configuration from environment
 	config := loadConfig()
 
-	// Initialize Cloud Storage writer
+	// Initialize fracture storage
 	ctx := context.Background()
-	storageWriter, err := storage.NewCloudStorageWriter(ctx, config.BucketName)
+	fractureStore, err := storage.New(ctx, config.Storage)
 	if err != nil {
-		log.Fatalf("[Main] Failed to initialize Cloud Storage: %v", err)
+		log.Fatalf("[Main] Failed to initialize fracture storage: %v", err)
 	}
-	defer storageWriter.Close()
+	defer fractureStore.Close()
 
-	log.Printf("[Main] Cloud Storage initialized (bucket: %s)", config.BucketName)
+	log.Printf("[Main] Fracture storage initialized (backend: %s)", config.Storage.Backend)
+
+	// The query index follows storage; stats rollups count what it indexes
+	indexWindow := time.Duration(config.IndexDays) * 24 * time.Hour
+	rollups := stats.New(indexWindow)
+	fractureIndex := index.New(fractureStore, indexWindow, rollups.Add)
+
+	go warmIndex(ctx, fractureIndex, config.IndexDays)
+
+	// Initialize the write queue in front of storage
+	// Fractures are indexed once they are durable in storage; redelivered
+	// fractures are already stored and indexed, so they are skipped
+	sink := func(ctx context.Context, fractures []*models.FracturedEvent) error {
+		written, err := storage.WriteNew(ctx, fractureStore, fractures)
+		if err != nil {
+			return err
+		}
+		fractureIndex.Add(written)
+		return nil
+	}
+
+	fractureQueue, err := queue.Open(config.Queue, sink)
+	if err != nil {
+		log.Fatalf("[Main] Failed to open fracture queue: %v", err)
+	}
+	fractureQueue.Start()
+
+	log.Printf("[Main] Fracture queue started (dir: %s, capacity: %d, workers: %d)",
+		config.Queue.Dir, config.Queue.Capacity, config.Queue.Workers)
+
+	// Replay claims and outcomes are kept beside the fractures
+	replayLog := replay.Open(fractureStore)
+
+	replayClient := client.NewReplayClient(config.BoundaryAdapterURL, config.VetoServiceURL, 15*time.Second)
+
+	// Triage history is kept beside the fractures
+	triageLog := triage.Open(fractureStore)
 
 	// Initialize HTTP handler
-	h := handler.New(storageWriter)
+	h := handler.New(fractureStore, fractureQueue, fractureIndex, replayLog, replayClient, triageLog, rollups)
 
 	// Set up HTTP server
 	mux := http.NewServeMux()
@@ -71,14 +115,23 @@ func main() {
 		log.Fatalf("[Main] Server forced to shutdown: %v", err)
 	}
 
+	// Flush queued fractures to storage before the store is closed
+	if err := fractureQueue.Close(shutdownCtx); err != nil {
+		log.Printf("[Main] Warning: %v", err)
+	}
+
 	log.Println("[Main] Server exited successfully")
 }
 
 // Config holds application configuration
 type Config struct {
-	Port       string
-	BucketName string
-	NodeID     string
+	Port               string
+	Storage            storage.Config
+	Queue              queue.Config
+	IndexDays          int
+	BoundaryAdapterURL string
+	VetoServiceURL     string
+	NodeID             string
 }
 
 // loadConfig loads configuration from environment variables
@@ -88,10 +141,23 @@ func loadConfig() Config {
 		port = "8080" // Default port for Cloud Run
 	}
 
-	bucketName := os.Getenv("GCS_BUCKET_NAME")
-	if bucketName == "" {
-		bucketName = "veps-fractures" // Default bucket name
-		log.Printf("[Main] Warning: GCS_BUCKET_NAME not set, using default: %s", bucketName)
+	storageConfig := loadStorageConfig()
+
+	queueDir := os.Getenv("FRACTURE_QUEUE_DIR")
+	if queueDir == "" {
+		queueDir = "/tmp/veps-fracture-queue" // Cloud Run only allows writes under /tmp
+	}
+	queueConfig := queue.Config{
+		Dir:         queueDir,
+		Capacity:    envInt("FRACTURE_QUEUE_CAPACITY", 10000),
+		Workers:     envInt("FRACTURE_QUEUE_WORKERS", 4),
+		MaxAttempts: envInt("FRACTURE_QUEUE_MAX_ATTEMPTS", 8),
+	}
+
+	boundaryAdapterURL := os.Getenv("BOUNDARY_ADAPTER_URL")
+	vetoServiceURL := os.Getenv("VETO_SERVICE_URL")
+	if boundaryAdapterURL == "" && vetoServiceURL == "" {
+		log.Printf("[Main] Warning: BOUNDARY_ADAPTER_URL and VETO_SERVICE_URL not set, replays are dry-run only")
 	}
 
 	nodeID := os.Getenv("FRACTURE_NODE_ID")
@@ -100,11 +166,95 @@ func loadConfig() Config {
 		os.Setenv("FRACTURE_NODE_ID", nodeID)
 	}
 
+	indexDays := envInt("FRACTURE_INDEX_DAYS", 7)
+	if indexDays > index.MaxDays {
+		log.Printf("[Main] Warning: FRACTURE_INDEX_DAYS %d exceeds %d, using %d", indexDays, index.MaxDays, index.MaxDays)
+		indexDays = index.MaxDays
+	}
+
 	return Config{
-		Port:       port,
-		BucketName: bucketName,
-		NodeID:     nodeID,
+		Port:               port,
+		Storage:            storageConfig,
+		Queue:              queueConfig,
+		IndexDays:          indexDays,
+		BoundaryAdapterURL: boundaryAdapterURL,
+		VetoServiceURL:     vetoServiceURL,
+		NodeID:             nodeID,
+	}
+}
+
+// warmIndex indexes the fractures of the last days from storage, so the
+// first queries on a fresh Cloud Run instance do not pay for it
+func warmIndex(ctx context.Context, idx *index.Index, days int) {
+	start := time.Now()
+	if err := idx.Refresh(ctx, time.Time{}, time.Time{}); err != nil {
+		log.Printf("[Main] Warning: index warm-up failed: %v", err)
+		return
 	}
+	log.Printf("[Main] Index warm-up complete: %d fractures from the last %d days in %s", idx.Len(), days, time.Since(start))
+}
+
+// envInt reads a positive integer setting, falling back to def
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("[Main] Warning: invalid %s %q, using default: %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+// loadStorageConfig loads the fracture storage backend selected by FRACTURE_STORAGE
+func loadStorageConfig() storage.Config {
+	cfg := storage.Config{
+		Backend: os.Getenv("FRACTURE_STORAGE"),
+	}
+
+	switch cfg.Backend {
+	case "":
+		cfg.Backend = storage.BackendGCS
+		fallthrough
+	case storage.BackendGCS:
+		cfg.BucketName = os.Getenv("GCS_BUCKET_NAME")
+		if cfg.BucketName == "" {
+			cfg.BucketName = "veps-fractures" // Default bucket name
+			log.Printf("[Main] Warning: GCS_BUCKET_NAME not set, using default: %s", cfg.BucketName)
+		}
+
+	case storage.BackendLocal:
+		cfg.LocalDir = os.Getenv("FRACTURE_LOCAL_DIR")
+		if cfg.LocalDir == "" {
+			cfg.LocalDir = "fractures"
+			log.Printf("[Main] Using default FRACTURE_LOCAL_DIR: %s", cfg.LocalDir)
+		}
+
+	case storage.BackendS3:
+		cfg.S3 = storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		}
+		if cfg.S3.Endpoint == "" {
+			cfg.S3.Endpoint = "http://localhost:9000" // MinIO
+			log.Printf("[Main] Using default S3_ENDPOINT: %s", cfg.S3.Endpoint)
+		}
+		if cfg.S3.Region == "" {
+			cfg.S3.Region = "us-east-1"
+		}
+		if cfg.S3.Bucket == "" {
+			cfg.S3.Bucket = "veps-fractures"
+			log.Printf("[Main] Warning: S3_BUCKET not set, using default: %s", cfg.S3.Bucket)
+		}
+	}
+
+	return cfg
 }
 
 // loggingMiddleware logs HTTP requests
@@ -140,12 +290,20 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush lets streamed responses through the wrapper
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // corsMiddleware adds CORS headers for development
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Next-Cursor")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
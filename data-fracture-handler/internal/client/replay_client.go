@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// ReplayClient re-sends vetoed events to the Boundary Adapter or Veto Service
+type ReplayClient struct {
+	boundaryURL string
+	vetoURL     string
+	httpClient  *http.Client
+}
+
+// Outcome is a target's decision on a replayed event
+type Outcome struct {
+	Accepted     bool
+	HTTPStatus   int
+	EventID      string
+	FailedChecks []string
+	Reasons      []string
+}
+
+// replayResponse is the subset of the Boundary Adapter and Veto Service responses we read
+type replayResponse struct {
+	EventID string `json:"event_id"`
+	Error   string `json:"error"`
+	Data    struct {
+		Passed       *bool    `json:"passed"` // set by /validate/explain, which always answers 200
+		FailedChecks []string `json:"failed_checks"`
+		Reasons      []string `json:"reasons"`
+	} `json:"data"`
+}
+
+// idempotencyKeyHeader names a request so the Boundary Adapter ingests it once
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// NewReplayClient creates a replay client; either URL may be empty if that
+// target is not used
+func NewReplayClient(boundaryURL, vetoURL string, timeout time.Duration) *ReplayClient {
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DisableKeepAlives:   false,
+		ForceAttemptHTTP2:   true,
+	}
+
+	return &ReplayClient{
+		boundaryURL: boundaryURL,
+		vetoURL:     vetoURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// Supports reports whether a target's URL is configured
+func (c *ReplayClient) Supports(target string) bool {
+	switch target {
+	case models.ReplayTargetBoundary:
+		return c.boundaryURL != ""
+	case models.ReplayTargetVeto:
+		return c.vetoURL != ""
+	}
+	return false
+}
+
+// Replay sends a fractured event to a target
+// Boundary replays carry an idempotency key derived from the fracture, so a
+// retried replay is ingested once. Veto replays go to /validate/explain,
+// which places no holds and records nothing, since the event is not ingested
+func (c *ReplayClient) Replay(ctx context.Context, target string, fracture *models.FracturedEvent) (*Outcome, error) {
+	switch target {
+	case models.ReplayTargetBoundary:
+		return c.post(ctx, c.boundaryURL, "/ingest", "replay:"+fracture.FractureID.String(), ingestRequest(fracture))
+	case models.ReplayTargetVeto:
+		return c.post(ctx, c.vetoURL, "/validate/explain", "", validateRequest(fracture))
+	}
+	return nil, fmt.Errorf("unknown replay target %q", target)
+}
+
+// ingestRequest rebuilds the raw event the Boundary Adapter normalized
+// The replay gets a new event ID, timestamp and vector clock, so it is not
+// vetoed as stale or causally out of order; the originals are kept in the
+// evidence
+func ingestRequest(fracture *models.FracturedEvent) models.RawEvent {
+	event := fracture.Event
+
+	data := make(map[string]any, len(event.Evidence)+8)
+	for key, value := range event.Evidence {
+		data[key] = value
+	}
+	data["type"] = event.Type
+	data["actor"] = map[string]any{
+		"id":   event.Actor.ID,
+		"name": event.Actor.Name,
+		"type": event.Actor.Type,
+	}
+	if event.Metadata.CorrelationID != "" {
+		data["correlation_id"] = event.Metadata.CorrelationID
+	}
+	addReplayEvidence(data, fracture)
+
+	return models.RawEvent{
+		Data:      data,
+		Source:    event.Source,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// validateRequest explains the stored event as of now
+func validateRequest(fracture *models.FracturedEvent) models.VetoRequest {
+	event := fracture.Event
+
+	evidence := make(map[string]any, len(event.Evidence)+4)
+	for key, value := range event.Evidence {
+		evidence[key] = value
+	}
+	addReplayEvidence(evidence, fracture)
+
+	event.Evidence = evidence
+	event.Timestamp = time.Now().UTC()
+
+	return models.VetoRequest{
+		Event: event,
+		Route: "veto_service",
+	}
+}
+
+// addReplayEvidence links a replayed event to its fracture
+func addReplayEvidence(evidence map[string]any, fracture *models.FracturedEvent) {
+	evidence["replay_of_fracture"] = fracture.FractureID.String()
+	evidence["replay_of_event"] = fracture.Event.ID.String()
+	evidence["original_timestamp"] = fracture.Event.Timestamp
+	if len(fracture.Event.VectorClock) > 0 {
+		evidence["original_vector_clock"] = fracture.Event.VectorClock
+	}
+}
+
+// post sends a replay request; 200 is accepted and 412 is vetoed, as is a
+// 200 whose data says the event did not pass
+func (c *ReplayClient) post(ctx context.Context, baseURL, path, idempotencyKey string, body any) (*Outcome, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal replay request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+
+	// Add authentication token for Cloud Run service-to-service calls
+	token, err := GetIDToken(ctx, baseURL)
+	if err != nil {
+		// Log but don't fail - might be running locally without auth
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded replayResponse
+	json.NewDecoder(resp.Body).Decode(&decoded)
+
+	outcome := &Outcome{
+		HTTPStatus:   resp.StatusCode,
+		EventID:      decoded.EventID,
+		FailedChecks: decoded.Data.FailedChecks,
+		Reasons:      decoded.Data.Reasons,
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		outcome.Accepted = decoded.Data.Passed == nil || *decoded.Data.Passed
+		return outcome, nil
+	case http.StatusPreconditionFailed:
+		return outcome, nil
+	}
+
+	if decoded.Error != "" {
+		return outcome, fmt.Errorf("%s returned status %d: %s", path, resp.StatusCode, decoded.Error)
+	}
+	return outcome, fmt.Errorf("%s returned status %d", path, resp.StatusCode)
+}
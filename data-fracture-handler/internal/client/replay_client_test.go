@@ -0,0 +1,126 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// replayTarget serves one canned response and records the request it got
+type replayTarget struct {
+	status int
+	body   string
+
+	path           string
+	idempotencyKey string
+}
+
+func (rt *replayTarget) start(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rt.path = r.URL.Path
+		rt.idempotencyKey = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(rt.status)
+		w.Write([]byte(rt.body))
+	}))
+	t.Cleanup(srv.Close)
+
+	// Skip the metadata server: the test targets need no ID token
+	globalTokenCache.mu.Lock()
+	globalTokenCache.tokens[srv.URL] = &cachedToken{token: "test", expiresAt: time.Now().Add(time.Hour)}
+	globalTokenCache.mu.Unlock()
+	return srv.URL
+}
+
+func testFracture() *models.FracturedEvent {
+	return &models.FracturedEvent{
+		FractureID: uuid.New(),
+		Event: models.Event{
+			ID:       uuid.New(),
+			Type:     "payment",
+			Actor:    models.Actor{ID: "user-1"},
+			Evidence: map[string]any{"amount": 10.0},
+		},
+	}
+}
+
+func TestReplayToBoundarySendsIdempotencyKey(t *testing.T) {
+	target := &replayTarget{status: http.StatusOK, body: `{"success":true,"event_id":"new-event"}`}
+	c := NewReplayClient(target.start(t), "", time.Second)
+
+	fracture := testFracture()
+	outcome, err := c.Replay(t.Context(), models.ReplayTargetBoundary, fracture)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !outcome.Accepted || outcome.EventID != "new-event" {
+		t.Errorf("outcome = %+v, want accepted as new-event", outcome)
+	}
+	if target.path != "/ingest" {
+		t.Errorf("sent to %s, want /ingest", target.path)
+	}
+	if want := "replay:" + fracture.FractureID.String(); target.idempotencyKey != want {
+		t.Errorf("Idempotency-Key = %q, want %q", target.idempotencyKey, want)
+	}
+}
+
+func TestReplayToVetoOnlyExplains(t *testing.T) {
+	tests := []struct {
+		name     string
+		passed   bool
+		accepted bool
+	}{
+		{name: "passes now", passed: true, accepted: true},
+		{name: "still vetoed", passed: false, accepted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]any{"passed": tt.passed, "failed_checks": []string{}, "reasons": []string{}}
+			if !tt.passed {
+				data["failed_checks"] = []string{"balance"}
+				data["reasons"] = []string{"balance: insufficient funds"}
+			}
+			body, _ := json.Marshal(map[string]any{"success": true, "data": data})
+
+			// /validate/explain answers 200 either way; the verdict is in the data
+			target := &replayTarget{status: http.StatusOK, body: string(body)}
+			c := NewReplayClient("", target.start(t), time.Second)
+
+			outcome, err := c.Replay(t.Context(), models.ReplayTargetVeto, testFracture())
+			if err != nil {
+				t.Fatalf("Replay: %v", err)
+			}
+			if target.path != "/validate/explain" {
+				t.Errorf("sent to %s, want /validate/explain", target.path)
+			}
+			if target.idempotencyKey != "" {
+				t.Errorf("Idempotency-Key = %q on an explain request", target.idempotencyKey)
+			}
+			if outcome.Accepted != tt.accepted {
+				t.Errorf("accepted = %v, want %v", outcome.Accepted, tt.accepted)
+			}
+			if !tt.passed && (len(outcome.FailedChecks) != 1 || outcome.FailedChecks[0] != "balance") {
+				t.Errorf("failed checks = %v, want [balance]", outcome.FailedChecks)
+			}
+		})
+	}
+}
+
+func TestReplayReportsTargetErrors(t *testing.T) {
+	target := &replayTarget{status: http.StatusServiceUnavailable, body: `{"success":false,"error":"overloaded"}`}
+	c := NewReplayClient(target.start(t), "", time.Second)
+
+	outcome, err := c.Replay(t.Context(), models.ReplayTargetBoundary, testFracture())
+	if err == nil {
+		t.Fatal("Replay succeeded against a failing target")
+	}
+	if outcome == nil || outcome.Accepted || outcome.HTTPStatus != http.StatusServiceUnavailable {
+		t.Errorf("outcome = %+v, want a rejected 503", outcome)
+	}
+}
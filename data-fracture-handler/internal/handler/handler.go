@@ -2,24 +2,54 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/client"
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+	"github.com/veps-service-480701/data-fracture-handler/internal/queue"
+	"github.com/veps-service-480701/data-fracture-handler/internal/replay"
+	"github.com/veps-service-480701/data-fracture-handler/internal/stats"
 	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/internal/triage"
 	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
 )
 
+// Request body limits; a fracture carries the event as the Boundary Adapter
+// accepted it, so these sit above the adapter's own ingest limits
+const (
+	maxFractureBody = 2 << 20  // one fracture
+	maxBatchBody    = 16 << 20 // up to 100 fractures
+	maxControlBody  = 1 << 20  // replay and triage requests
+)
+
 // Handler manages HTTP requests for the Data Fracture Handler
 type Handler struct {
-	storage *storage.CloudStorageWriter
+	storage  storage.FractureStore
+	queue    *queue.Queue
+	index    *index.Index
+	replays  *replay.Log
+	replayer *client.ReplayClient
+	triage   *triage.Log
+	stats    *stats.Rollups
 }
 
 // New creates a new HTTP handler
-func New(storage *storage.CloudStorageWriter) *Handler {
+// Fractures are written through the queue; queries look them up in the index,
+// which follows storage, and read only the matching hours
+func New(storage storage.FractureStore, queue *queue.Queue, index *index.Index, replays *replay.Log, replayer *client.ReplayClient, triage *triage.Log, stats *stats.Rollups) *Handler {
 	return &Handler{
-		storage: storage,
+		storage:  storage,
+		queue:    queue,
+		index:    index,
+		replays:  replays,
+		replayer: replayer,
+		triage:   triage,
+		stats:    stats,
 	}
 }
 
@@ -39,6 +69,10 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Success:   true,
 		Message:   "Data Fracture Handler is healthy",
 		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"queue":           h.queue.Stats(),
+			"indexed_entries": h.index.Len(),
+		},
 	}
 	h.writeJSON(w, http.StatusOK, response)
 }
@@ -55,14 +89,13 @@ func (h *Handler) LogFracture(w http.ResponseWriter, r *http.Request) {
 
 	// Parse fracture request
 	var fractureReq models.FractureRequest
-	if err := json.NewDecoder(r.Body).Decode(&fractureReq); err != nil {
-		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	if !h.decodeJSON(w, r, maxFractureBody, &fractureReq) {
 		return
 	}
 	defer r.Body.Close()
 
-	// Validate request
-	if fractureReq.Event.ID.String() == "" {
+	// Validate request; the fracture ID is derived from the event ID
+	if fractureReq.Event.ID == uuid.Nil {
 		h.writeError(w, http.StatusBadRequest, "event ID is required")
 		return
 	}
@@ -72,24 +105,26 @@ func (h *Handler) LogFracture(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Fractures are an audit trail, so events with an unknown schema are still recorded
+	if err := fractureReq.Event.UpgradeSchema(); err != nil {
+		log.Printf("[Handler] Warning: fracture for event %s: %v", fractureReq.Event.ID, err)
+	}
+
 	// Convert to fractured event
 	fracturedEvent := fractureReq.ToFracturedEvent()
 
 	log.Printf("[Handler] Logging fracture for event %s (checks failed: %v)", 
 		fracturedEvent.Event.ID, fracturedEvent.Rejection.FailedChecks)
 
-	// Write to Cloud Storage (async to not block response)
-	go func() {
-		ctx := r.Context()
-		if err := h.storage.WriteFracture(ctx, fracturedEvent); err != nil {
-			log.Printf("[Handler] ERROR: Failed to write fracture %s: %v", 
-				fracturedEvent.FractureID, err)
-		}
-	}()
+	// Queue for the storage workers; the fracture is on disk once this returns
+	if err := h.queue.Enqueue([]*models.FracturedEvent{fracturedEvent}); err != nil {
+		h.writeEnqueueError(w, err)
+		return
+	}
 
 	duration := time.Since(startTime)
 
-	// Return success immediately (fire-and-forget pattern)
+	// Return once queued; the storage write happens in the background
 	response := Response{
 		Success:    true,
 		Message:    "Fracture logged successfully",
@@ -118,8 +153,7 @@ func (h *Handler) LogFractureBatch(w http.ResponseWriter, r *http.Request) {
 
 	// Parse batch request
 	var batchReq []models.FractureRequest
-	if err := json.NewDecoder(r.Body).Decode(&batchReq); err != nil {
-		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	if !h.decodeJSON(w, r, maxBatchBody, &batchReq) {
 		return
 	}
 	defer r.Body.Close()
@@ -136,19 +170,21 @@ func (h *Handler) LogFractureBatch(w http.ResponseWriter, r *http.Request) {
 
 	// Convert all to fractured events
 	fracturedEvents := make([]*models.FracturedEvent, 0, len(batchReq))
-	for _, req := range batchReq {
+	for i, req := range batchReq {
+		if req.Event.ID == uuid.Nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("event ID is required (batch item %d)", i))
+			return
+		}
 		fracturedEvents = append(fracturedEvents, req.ToFracturedEvent())
 	}
 
 	log.Printf("[Handler] Logging batch of %d fractures", len(fracturedEvents))
 
-	// Write batch asynchronously
-	go func() {
-		ctx := r.Context()
-		if err := h.storage.WriteFractureBatch(ctx, fracturedEvents); err != nil {
-			log.Printf("[Handler] ERROR: Failed to write fracture batch: %v", err)
-		}
-	}()
+	// Queue the whole batch as one request
+	if err := h.queue.Enqueue(fracturedEvents); err != nil {
+		h.writeEnqueueError(w, err)
+		return
+	}
 
 	duration := time.Since(startTime)
 
@@ -165,50 +201,6 @@ func (h *Handler) LogFractureBatch(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// QueryFractures handles queries for fractured events by date
-func (h *Handler) QueryFractures(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
-		return
-	}
-
-	// Parse date from query parameter
-	dateStr := r.URL.Query().Get("date")
-	if dateStr == "" {
-		h.writeError(w, http.StatusBadRequest, "date parameter is required (format: YYYY-MM-DD)")
-		return
-	}
-
-	date, err := time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid date format (expected: YYYY-MM-DD)")
-		return
-	}
-
-	log.Printf("[Handler] Querying fractures for date: %s", date.Format("2006-01-02"))
-
-	// Read fractures from Cloud Storage
-	fractures, err := h.storage.ReadFractures(r.Context(), date)
-	if err != nil {
-		log.Printf("[Handler] ERROR: Failed to read fractures: %v", err)
-		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read fractures: %v", err))
-		return
-	}
-
-	response := Response{
-		Success:   true,
-		Message:   fmt.Sprintf("Found %d fractures for %s", len(fractures), date.Format("2006-01-02")),
-		Timestamp: time.Now().UTC(),
-		Data: map[string]interface{}{
-			"date":      date.Format("2006-01-02"),
-			"count":     len(fractures),
-			"fractures": fractures,
-		},
-	}
-
-	h.writeJSON(w, http.StatusOK, response)
-}
-
 // writeJSON writes a JSON response
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -218,6 +210,24 @@ func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{})
 	}
 }
 
+// decodeJSON decodes a request body of at most limit bytes into v, answering
+// 413 or 400 itself when it cannot
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, limit int64, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", limit))
+		return false
+	}
+	h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+	return false
+}
+
 // writeError writes an error response
 func (h *Handler) writeError(w http.ResponseWriter, status int, message string) {
 	response := Response{
@@ -228,10 +238,27 @@ func (h *Handler) writeError(w http.ResponseWriter, status int, message string)
 	h.writeJSON(w, status, response)
 }
 
+// writeEnqueueError reports a queue failure; a saturated or closing queue is a 503
+// so callers retry rather than assume the fracture was recorded
+func (h *Handler) writeEnqueueError(w http.ResponseWriter, err error) {
+	if errors.Is(err, queue.ErrFull) || errors.Is(err, queue.ErrClosed) {
+		log.Printf("[Handler] Rejecting fracture: %v", err)
+		w.Header().Set("Retry-After", "1")
+		h.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	log.Printf("[Handler] ERROR: Failed to queue fracture: %v", err)
+	h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to queue fracture: %v", err))
+}
+
 // RegisterRoutes sets up HTTP routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/fracture", h.LogFracture)
 	mux.HandleFunc("/fracture/batch", h.LogFractureBatch)
 	mux.HandleFunc("/fractures", h.QueryFractures)
+	mux.HandleFunc("/fractures/replay", h.ReplayFractures)
+	mux.HandleFunc("/fractures/stats", h.FractureStats)
+	mux.HandleFunc("/fractures/", h.FractureByID)
 }
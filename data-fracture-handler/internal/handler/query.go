@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// QueryFractures handles GET /fractures
+//
+// Filters: event_id, actor_id, failed_check, source, correlation_id, triage
+// status and assignee, and a time range given as start/end (RFC 3339) or a
+// single date (YYYY-MM-DD). Without a start the index window is searched.
+// Results are in time order, limit per page, with next_cursor for the next
+// page. With format=ndjson (or Accept: application/x-ndjson) fractures are
+// streamed one per line and the cursor is returned in X-Next-Cursor
+func (h *Handler) QueryFractures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter.Include, err = h.triageFilter(r.URL.Query().Get("status"), r.URL.Query().Get("assignee"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Results carry triage state, which may have been changed on another instance
+	if err := h.triage.Sync(r.Context()); err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read triage state: %v", err))
+		return
+	}
+
+	entries, nextCursor, err := h.index.Query(r.Context(), filter)
+	if errors.Is(err, index.ErrInvalidCursor) || errors.Is(err, index.ErrRangeTooLarge) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to query index: %v", err))
+		return
+	}
+
+	log.Printf("[Handler] Query matched %d fractures", len(entries))
+
+	if wantsNDJSON(r) {
+		h.streamFractures(w, r.Context(), entries, nextCursor)
+		return
+	}
+
+	var fractures []fractureView
+	err = h.fetchFractures(r.Context(), entries, func(fracture *models.FracturedEvent) error {
+		fractures = append(fractures, h.view(fracture))
+		return nil
+	})
+	if err != nil {
+		log.Printf("[Handler] ERROR: Failed to read fractures: %v", err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read fractures: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d fractures", len(fractures)),
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"count":       len(fractures),
+			"next_cursor": nextCursor,
+			"fractures":   fractures,
+		},
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// streamFractures writes fractures as NDJSON, flushing after each storage hour
+func (h *Handler) streamFractures(w http.ResponseWriter, ctx context.Context, entries []index.Entry, nextCursor string) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if nextCursor != "" {
+		w.Header().Set("X-Next-Cursor", nextCursor)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	err := h.fetchFractures(ctx, entries, func(fracture *models.FracturedEvent) error {
+		return encoder.Encode(h.view(fracture))
+	})
+	if err != nil {
+		// Headers are already sent; the truncated stream is the only signal
+		log.Printf("[Handler] ERROR: Fracture stream aborted: %v", err)
+	}
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// fetchFractures reads the full fractures for index entries in order
+// Entries are grouped by storage hour so each hour is read once
+func (h *Handler) fetchFractures(ctx context.Context, entries []index.Entry, emit func(*models.FracturedEvent) error) error {
+	for start := 0; start < len(entries); {
+		hour := entries[start].Timestamp.Truncate(time.Hour)
+
+		end := start
+		for end < len(entries) && entries[end].Timestamp.Truncate(time.Hour).Equal(hour) {
+			end++
+		}
+
+		stored, err := h.storage.ReadHour(ctx, hour)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[uuid.UUID]*models.FracturedEvent, len(stored))
+		for _, fracture := range stored {
+			byID[fracture.FractureID] = fracture
+		}
+
+		for _, entry := range entries[start:end] {
+			fracture, ok := byID[entry.FractureID]
+			if !ok {
+				log.Printf("[Handler] Warning: indexed fracture %s not found in storage", entry.FractureID)
+				continue
+			}
+			if err := emit(fracture); err != nil {
+				return err
+			}
+		}
+
+		start = end
+	}
+
+	return nil
+}
+
+// parseFilter reads the query parameters of GET /fractures
+func parseFilter(r *http.Request) (index.Filter, error) {
+	query := r.URL.Query()
+
+	filter := index.Filter{
+		EventID:       query.Get("event_id"),
+		ActorID:       query.Get("actor_id"),
+		FailedCheck:   query.Get("failed_check"),
+		Source:        query.Get("source"),
+		CorrelationID: query.Get("correlation_id"),
+		Cursor:        query.Get("cursor"),
+	}
+
+	if dateStr := query.Get("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return filter, errors.New("invalid date format (expected: YYYY-MM-DD)")
+		}
+		filter.Start = date
+		filter.End = date.Add(24 * time.Hour)
+	}
+
+	if startStr := query.Get("start"); startStr != "" {
+		start, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return filter, errors.New("invalid start (expected RFC 3339)")
+		}
+		filter.Start = start
+	}
+
+	if endStr := query.Get("end"); endStr != "" {
+		end, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return filter, errors.New("invalid end (expected RFC 3339)")
+		}
+		filter.End = end
+	}
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && !filter.Start.Before(filter.End) {
+		return filter, errors.New("start must be before end")
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+// wantsNDJSON reports whether the client asked for a streamed response
+func wantsNDJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "ndjson" ||
+		strings.Contains(r.Header.Get("Accept"), "application/x-ndjson")
+}
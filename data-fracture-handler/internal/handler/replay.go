@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// replayWorkers is the number of events of one run sent concurrently
+const replayWorkers = 4
+
+// ReplayFractures handles /fractures/replay
+//
+// POST selects fractures (by ID or with the same filters as GET /fractures)
+// and re-sends their events to the Boundary Adapter or Veto Service. Each
+// fracture is claimed before it is sent and is never replayed twice. With
+// dry_run the selection is returned and nothing is sent or recorded. Claims
+// and outcomes are shared by every instance through the fracture bucket.
+//
+// GET returns replay records by run_id or fracture_id
+func (h *Handler) ReplayFractures(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.startReplay(w, r)
+	case http.MethodGet:
+		h.getReplays(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET and POST methods are allowed")
+	}
+}
+
+// startReplay selects fractures and starts a replay run
+func (h *Handler) startReplay(w http.ResponseWriter, r *http.Request) {
+	var req models.ReplayRequest
+	if !h.decodeJSON(w, r, maxControlBody, &req) {
+		return
+	}
+	defer r.Body.Close()
+
+	if req.Target == "" {
+		req.Target = models.ReplayTargetBoundary
+	}
+	if req.Target != models.ReplayTargetBoundary && req.Target != models.ReplayTargetVeto {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown target %q (expected: boundary or veto)", req.Target))
+		return
+	}
+	if !req.DryRun && !h.replayer.Supports(req.Target) {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("replay target %s is not configured", req.Target))
+		return
+	}
+
+	// Skip fractures other instances have replayed
+	if err := h.replays.Sync(r.Context()); err != nil {
+		log.Printf("[Handler] ERROR: Failed to read replay records: %v", err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read replay records: %v", err))
+		return
+	}
+
+	entries, alreadyReplayed, err := h.selectForReplay(r.Context(), req)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.DryRun {
+		response := Response{
+			Success:   true,
+			Message:   fmt.Sprintf("Dry run: %d fractures would be replayed", len(entries)),
+			Timestamp: time.Now().UTC(),
+			Data: map[string]interface{}{
+				"dry_run":          true,
+				"target":           req.Target,
+				"count":            len(entries),
+				"already_replayed": alreadyReplayed,
+				"fractures":        entries,
+			},
+		}
+		h.writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	runID := uuid.New().String()
+
+	records := make([]models.ReplayRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = models.ReplayRecord{
+			FractureID: entry.FractureID,
+			RunID:      runID,
+			Target:     req.Target,
+			EventID:    entry.EventID,
+		}
+	}
+
+	// The run outlives the request; the caller polls GET /fractures/replay
+	ctx := context.WithoutCancel(r.Context())
+
+	claimed, err := h.replays.Claim(ctx, records)
+	if err != nil {
+		log.Printf("[Handler] ERROR: Failed to claim fractures for replay: %v", err)
+		// Fractures claimed before the error are closed out rather than left pending
+		for _, record := range claimed {
+			record.Status = models.ReplayFailed
+			record.Error = "replay run aborted: " + err.Error()
+			h.complete(ctx, record)
+		}
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to claim fractures: %v", err))
+		return
+	}
+	// A concurrent run may have claimed some of the selection first
+	alreadyReplayed += len(records) - len(claimed)
+
+	log.Printf("[Handler] Replay run %s started: %d fractures to %s", runID, len(claimed), req.Target)
+
+	go h.runReplay(ctx, req.Target, claimed)
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Replaying %d fractures", len(claimed)),
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"run_id":           runID,
+			"target":           req.Target,
+			"count":            len(claimed),
+			"already_replayed": alreadyReplayed,
+		},
+	}
+	h.writeJSON(w, http.StatusAccepted, response)
+}
+
+// selectForReplay returns the selected fractures that have not been replayed,
+// and how many selected fractures were skipped because they have
+func (h *Handler) selectForReplay(ctx context.Context, req models.ReplayRequest) ([]index.Entry, int, error) {
+	if len(req.FractureIDs) > 0 {
+		if len(req.FractureIDs) > index.MaxLimit {
+			return nil, 0, fmt.Errorf("at most %d fracture_ids per replay", index.MaxLimit)
+		}
+
+		var entries []index.Entry
+		skipped := 0
+		for _, id := range req.FractureIDs {
+			entry, ok, err := h.index.Get(ctx, id)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !ok {
+				return nil, 0, fmt.Errorf("fracture %s not found", id)
+			}
+			if _, replayed := h.replays.Get(id); replayed {
+				skipped++
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return entries, skipped, nil
+	}
+
+	filter := index.Filter{
+		EventID:       req.EventID,
+		ActorID:       req.ActorID,
+		FailedCheck:   req.FailedCheck,
+		Source:        req.Source,
+		CorrelationID: req.CorrelationID,
+		Start:         req.Start,
+		End:           req.End,
+	}
+	if filter.EventID == "" && filter.ActorID == "" && filter.FailedCheck == "" && filter.Source == "" &&
+		filter.CorrelationID == "" && filter.Start.IsZero() && filter.End.IsZero() {
+		return nil, 0, fmt.Errorf("fracture_ids or at least one filter is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = index.DefaultLimit
+	}
+	if limit > index.MaxLimit {
+		limit = index.MaxLimit
+	}
+	filter.Limit = limit
+
+	var entries []index.Entry
+	skipped := 0
+	for len(entries) < limit {
+		page, next, err := h.index.Query(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, entry := range page {
+			if _, replayed := h.replays.Get(entry.FractureID); replayed {
+				skipped++
+				continue
+			}
+			if len(entries) < limit {
+				entries = append(entries, entry)
+			}
+		}
+
+		if next == "" {
+			break
+		}
+		filter.Cursor = next
+	}
+
+	return entries, skipped, nil
+}
+
+// runReplay sends claimed fractures to the target and records each outcome
+func (h *Handler) runReplay(ctx context.Context, target string, claimed []models.ReplayRecord) {
+	records := make(map[uuid.UUID]models.ReplayRecord, len(claimed))
+	entries := make([]index.Entry, 0, len(claimed))
+	for _, record := range claimed {
+		entry, ok, err := h.index.Get(ctx, record.FractureID)
+		if err != nil || !ok {
+			continue
+		}
+		records[record.FractureID] = record
+		entries = append(entries, entry)
+	}
+
+	fractures := make(chan *models.FracturedEvent)
+	var wg sync.WaitGroup
+	for i := 0; i < replayWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fracture := range fractures {
+				h.complete(ctx, h.replayOne(ctx, target, records[fracture.FractureID], fracture))
+			}
+		}()
+	}
+
+	sent := make(map[uuid.UUID]bool, len(entries))
+	err := h.fetchFractures(ctx, entries, func(fracture *models.FracturedEvent) error {
+		if _, ok := records[fracture.FractureID]; ok && !sent[fracture.FractureID] {
+			sent[fracture.FractureID] = true
+			fractures <- fracture
+		}
+		return nil
+	})
+	close(fractures)
+	wg.Wait()
+
+	reason := "fracture not found in storage"
+	if err != nil {
+		reason = fmt.Sprintf("failed to read fracture: %v", err)
+	}
+
+	// Claimed fractures that were never sent are closed out, not retried
+	for _, record := range claimed {
+		if sent[record.FractureID] {
+			continue
+		}
+		record.Status = models.ReplayFailed
+		record.Error = reason
+		h.complete(ctx, record)
+	}
+
+	log.Printf("[Handler] Replay run finished: %d of %d fractures sent to %s", len(sent), len(claimed), target)
+}
+
+// replayOne sends one fracture and returns its completed record
+func (h *Handler) replayOne(ctx context.Context, target string, record models.ReplayRecord, fracture *models.FracturedEvent) models.ReplayRecord {
+	outcome, err := h.replayer.Replay(ctx, target, fracture)
+	if outcome != nil {
+		record.HTTPStatus = outcome.HTTPStatus
+		record.NewEventID = outcome.EventID
+		record.FailedChecks = outcome.FailedChecks
+		record.Reasons = outcome.Reasons
+	}
+
+	switch {
+	case err != nil:
+		record.Status = models.ReplayFailed
+		record.Error = err.Error()
+		log.Printf("[Handler] Replay of fracture %s failed: %v", fracture.FractureID, err)
+	case outcome.Accepted:
+		record.Status = models.ReplayAccepted
+		log.Printf("[Handler] Replay of fracture %s accepted", fracture.FractureID)
+		if target == models.ReplayTargetBoundary {
+			h.markReplayed(ctx, record)
+		}
+	default:
+		record.Status = models.ReplayVetoed
+		log.Printf("[Handler] Replay of fracture %s vetoed again: %v", fracture.FractureID, outcome.Reasons)
+	}
+
+	return record
+}
+
+// markReplayed moves a fracture whose event was re-ingested to the replayed triage status
+func (h *Handler) markReplayed(ctx context.Context, record models.ReplayRecord) {
+	update := models.TriageUpdate{
+		Status: models.TriageReplayed,
+		Note:   fmt.Sprintf("replayed as event %s (run %s)", record.NewEventID, record.RunID),
+		By:     "replay",
+	}
+	if _, err := h.triage.Update(ctx, record.FractureID, update); err != nil {
+		log.Printf("[Handler] Warning: failed to mark fracture %s replayed: %v", record.FractureID, err)
+	}
+}
+
+// complete records a replay outcome, logging instead of failing the run
+func (h *Handler) complete(ctx context.Context, record models.ReplayRecord) {
+	if err := h.replays.Complete(ctx, record); err != nil {
+		log.Printf("[Handler] ERROR: Failed to record replay of fracture %s: %v", record.FractureID, err)
+	}
+}
+
+// getReplays returns replay records by run_id or fracture_id
+func (h *Handler) getReplays(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if err := h.replays.Sync(r.Context()); err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read replay records: %v", err))
+		return
+	}
+
+	var records []models.ReplayRecord
+	switch {
+	case query.Get("run_id") != "":
+		records = h.replays.ByRun(query.Get("run_id"))
+	case query.Get("fracture_id") != "":
+		id, err := uuid.Parse(query.Get("fracture_id"))
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid fracture_id")
+			return
+		}
+		if record, ok := h.replays.Get(id); ok {
+			records = append(records, record)
+		}
+	default:
+		h.writeError(w, http.StatusBadRequest, "run_id or fracture_id is required")
+		return
+	}
+
+	summary := make(map[string]int)
+	for _, record := range records {
+		summary[record.Status]++
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d replay records", len(records)),
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"count":   len(records),
+			"summary": summary,
+			"replays": records,
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
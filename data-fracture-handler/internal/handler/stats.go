@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/internal/stats"
+)
+
+const (
+	defaultTopActors = 10
+	maxTopActors     = 100
+)
+
+// FractureStats handles GET /fractures/stats
+//
+// Returns fracture counts over a window (start/end, RFC 3339) at minute,
+// hour or day granularity: totals by failed check, event type, source and
+// veto node, the top actors, and a series broken down by the group_by
+// dimensions. Counts come from rollups updated as fractures are indexed;
+// the index first picks up what other instances wrote in the window
+func (h *Handler) FractureStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	query, err := h.parseStatsQuery(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start, end, err := h.stats.Window(query)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.index.Refresh(r.Context(), start, end); err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to refresh index: %v", err))
+		return
+	}
+
+	result, err := h.stats.Query(query)
+	if errors.Is(err, stats.ErrInvalidWindow) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read stats: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("%d fractures between %s and %s", result.Total, result.Start.Format(time.RFC3339), result.End.Format(time.RFC3339)),
+		Timestamp: time.Now().UTC(),
+		Data:      result,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// parseStatsQuery reads the query parameters of GET /fractures/stats
+func (h *Handler) parseStatsQuery(r *http.Request) (stats.Query, error) {
+	params := r.URL.Query()
+
+	query := stats.Query{
+		Granularity: params.Get("granularity"),
+		End:         time.Now().UTC(),
+		GroupBy:     []string{stats.DimFailedCheck},
+		Top:         defaultTopActors,
+	}
+	if query.Granularity == "" {
+		query.Granularity = stats.Hour
+	}
+
+	if endStr := params.Get("end"); endStr != "" {
+		end, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return query, errors.New("invalid end (expected RFC 3339)")
+		}
+		query.End = end
+	}
+
+	query.Start = query.End.Add(-h.stats.DefaultWindow(query.Granularity))
+	if startStr := params.Get("start"); startStr != "" {
+		start, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return query, errors.New("invalid start (expected RFC 3339)")
+		}
+		query.Start = start
+	}
+
+	if groupBy := params.Get("group_by"); groupBy != "" {
+		query.GroupBy = nil
+		for _, dim := range strings.Split(groupBy, ",") {
+			if !validDimension(dim) {
+				return query, fmt.Errorf("unknown group_by dimension %q", dim)
+			}
+			query.GroupBy = append(query.GroupBy, dim)
+		}
+	}
+
+	if topStr := params.Get("top"); topStr != "" {
+		top, err := strconv.Atoi(topStr)
+		if err != nil || top <= 0 {
+			return query, errors.New("invalid top")
+		}
+		query.Top = min(top, maxTopActors)
+	}
+
+	return query, nil
+}
+
+// validDimension reports whether dim is a stats dimension
+func validDimension(dim string) bool {
+	for _, d := range stats.Dimensions {
+		if d == dim {
+			return true
+		}
+	}
+	return false
+}
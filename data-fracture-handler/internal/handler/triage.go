@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+	"github.com/veps-service-480701/data-fracture-handler/internal/triage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// fractureView is a stored fracture with its current triage state
+type fractureView struct {
+	*models.FracturedEvent
+	Triage models.Triage `json:"triage"`
+}
+
+// view attaches triage state to a fracture
+func (h *Handler) view(fracture *models.FracturedEvent) fractureView {
+	return fractureView{
+		FracturedEvent: fracture,
+		Triage:         h.triage.Get(fracture.FractureID),
+	}
+}
+
+// FractureByID handles /fractures/{id}
+//
+// GET returns the fracture, its triage state and its triage history.
+// PATCH updates the status, assignee or adds a note; every update is
+// appended to the history
+func (h *Handler) FractureByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/fractures/"))
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, "invalid fracture ID")
+		return
+	}
+
+	entry, ok, err := h.index.Get(r.Context(), id)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to look up fracture: %v", err))
+		return
+	}
+	if !ok {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("fracture %s not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getFracture(w, r, entry)
+	case http.MethodPatch:
+		h.patchFracture(w, r, id)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET and PATCH methods are allowed")
+	}
+}
+
+// getFracture returns one fracture with its triage history
+func (h *Handler) getFracture(w http.ResponseWriter, r *http.Request, entry index.Entry) {
+	if err := h.triage.Sync(r.Context()); err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read triage state: %v", err))
+		return
+	}
+
+	var fracture *models.FracturedEvent
+	err := h.fetchFractures(r.Context(), []index.Entry{entry}, func(f *models.FracturedEvent) error {
+		fracture = f
+		return nil
+	})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read fracture: %v", err))
+		return
+	}
+	if fracture == nil {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("fracture %s not found in storage", entry.FractureID))
+		return
+	}
+
+	response := Response{
+		Success:    true,
+		FractureID: fracture.FractureID.String(),
+		Timestamp:  time.Now().UTC(),
+		Data: map[string]interface{}{
+			"fracture": h.view(fracture),
+			"history":  h.triage.History(fracture.FractureID),
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// patchFracture applies a triage update
+func (h *Handler) patchFracture(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	var update models.TriageUpdate
+	if !h.decodeJSON(w, r, maxControlBody, &update) {
+		return
+	}
+	defer r.Body.Close()
+
+	state, err := h.triage.Update(r.Context(), id, update)
+	if errors.Is(err, triage.ErrInvalidUpdate) || errors.Is(err, triage.ErrNoChange) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] ERROR: Triage update for fracture %s failed: %v", id, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update triage: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:    true,
+		Message:    "Fracture triage updated",
+		FractureID: id.String(),
+		Timestamp:  time.Now().UTC(),
+		Data: map[string]interface{}{
+			"triage": state,
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// triageFilter builds an index filter for the status and assignee query parameters
+func (h *Handler) triageFilter(status, assignee string) (func(uuid.UUID) bool, error) {
+	if status == "" && assignee == "" {
+		return nil, nil
+	}
+	if status != "" && !models.ValidTriageStatus(status) {
+		return nil, fmt.Errorf("unknown status %q", status)
+	}
+
+	return func(id uuid.UUID) bool {
+		state := h.triage.Get(id)
+		if status != "" && state.Status != status {
+			return false
+		}
+		if assignee != "" && state.Assignee != assignee {
+			return false
+		}
+		return true
+	}, nil
+}
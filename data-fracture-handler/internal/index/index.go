@@ -0,0 +1,537 @@
+// Package index is a secondary index over stored fractures
+// Each fracture is reduced to a small Entry holding the fields the query API
+// filters on. Storage is the source of truth: before answering, the index
+// lists the days a query covers and reads only the objects it has not seen
+// at their current version, so every instance sees fractures written by the
+// others, and queries only read the storage hours that hold matches
+package index
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+const (
+	// DefaultLimit is the page size when a query does not set one
+	DefaultLimit = 100
+	// MaxLimit caps the page size
+	MaxLimit = 1000
+	// MaxDays caps the days a single query may span
+	MaxDays = 31
+	// listInterval is how long a day's listing is reused before storage is listed again
+	listInterval = 5 * time.Second
+)
+
+var (
+	// ErrInvalidCursor is returned for a cursor this index did not produce
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrRangeTooLarge is returned for a time range spanning more than MaxDays
+	ErrRangeTooLarge = fmt.Errorf("time range spans more than %d days", MaxDays)
+)
+
+// Entry is the indexed view of one fracture
+type Entry struct {
+	FractureID    uuid.UUID `json:"fracture_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	EventID       string    `json:"event_id"`
+	EventType     string    `json:"event_type"`
+	ActorID       string    `json:"actor_id"`
+	Source        string    `json:"source"`
+	CorrelationID string    `json:"correlation_id"`
+	FailedChecks  []string  `json:"failed_checks"`
+	VetoNode      string    `json:"veto_node"`
+}
+
+// NewEntry extracts the indexed fields of a fracture
+func NewEntry(fracture *models.FracturedEvent) Entry {
+	return Entry{
+		FractureID:    fracture.FractureID,
+		Timestamp:     fracture.Timestamp,
+		EventID:       fracture.Event.ID.String(),
+		EventType:     fracture.Event.Type,
+		ActorID:       fracture.Event.Actor.ID,
+		Source:        fracture.Event.Source,
+		CorrelationID: fracture.Context.CorrelationID,
+		FailedChecks:  fracture.Rejection.FailedChecks,
+		VetoNode:      fracture.Rejection.VetoNode,
+	}
+}
+
+// before orders entries by timestamp, then fracture ID
+func (e *Entry) before(other *Entry) bool {
+	if !e.Timestamp.Equal(other.Timestamp) {
+		return e.Timestamp.Before(other.Timestamp)
+	}
+	return e.FractureID.String() < other.FractureID.String()
+}
+
+// Filter selects fractures; empty fields match everything
+type Filter struct {
+	EventID       string
+	ActorID       string
+	FailedCheck   string
+	Source        string
+	CorrelationID string
+	Start         time.Time // inclusive
+	End           time.Time // exclusive
+	Cursor        string
+	Limit         int
+
+	// Include, if set, filters on state kept outside the index (e.g. triage)
+	Include func(fractureID uuid.UUID) bool
+}
+
+// matches reports whether an entry passes every field of the filter
+func (f *Filter) matches(e *Entry) bool {
+	if f.EventID != "" && e.EventID != f.EventID {
+		return false
+	}
+	if f.ActorID != "" && e.ActorID != f.ActorID {
+		return false
+	}
+	if f.Source != "" && e.Source != f.Source {
+		return false
+	}
+	if f.CorrelationID != "" && e.CorrelationID != f.CorrelationID {
+		return false
+	}
+	if f.FailedCheck != "" && !contains(e.FailedChecks, f.FailedCheck) {
+		return false
+	}
+	if !f.Start.IsZero() && e.Timestamp.Before(f.Start) {
+		return false
+	}
+	if !f.End.IsZero() && !e.Timestamp.Before(f.End) {
+		return false
+	}
+	if f.Include != nil && !f.Include(e.FractureID) {
+		return false
+	}
+	return true
+}
+
+// Index holds entries ordered by time plus posting lists per filter field
+type Index struct {
+	store  storage.FractureStore
+	window time.Duration
+	added  func([]Entry)
+
+	// refreshMu serializes refreshes so an object is read once
+	refreshMu sync.Mutex
+	days      map[int64]*day // keyed by the day's Unix time
+
+	mu       sync.RWMutex
+	entries  []*Entry // ordered by (timestamp, fracture ID)
+	byID     map[uuid.UUID]*Entry
+	postings map[string]map[string][]*Entry // field -> value -> entries
+}
+
+// day is what the index has read of one storage day
+type day struct {
+	listedAt time.Time
+	objects  map[string]string // path -> indexed version
+}
+
+// New creates an empty index over store
+// Queries without a start cover the last window; added, if set, is called
+// with every batch of newly indexed entries
+func New(store storage.FractureStore, window time.Duration, added func([]Entry)) *Index {
+	return &Index{
+		store:    store,
+		window:   window,
+		added:    added,
+		days:     make(map[int64]*day),
+		byID:     make(map[uuid.UUID]*Entry),
+		postings: make(map[string]map[string][]*Entry),
+	}
+}
+
+// Window returns the time range covered by queries without a start
+func (idx *Index) Window() time.Duration {
+	return idx.window
+}
+
+// bounds fills in the default time range of a query
+func (idx *Index) bounds(start, end time.Time) (time.Time, time.Time) {
+	now := time.Now().UTC()
+	if start.IsZero() {
+		start = now.Add(-idx.window)
+	}
+	if end.IsZero() || end.After(now) {
+		end = now
+	}
+	return start, end
+}
+
+// Refresh indexes the fractures stored between start and end that it has
+// not seen yet, whichever instance wrote them. Each day in the range is
+// listed, and objects are read only if new or changed since the last read.
+// Days before both the window and the range are dropped first
+func (idx *Index) Refresh(ctx context.Context, start, end time.Time) error {
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+	return idx.refresh(ctx, start, end)
+}
+
+// refresh is Refresh with refreshMu held
+func (idx *Index) refresh(ctx context.Context, start, end time.Time) error {
+	start, end = idx.bounds(start, end)
+
+	if end.Sub(start) > MaxDays*24*time.Hour {
+		return ErrRangeTooLarge
+	}
+	first := start.UTC().Truncate(24 * time.Hour)
+
+	// A query may reach back past the window; its days are kept until the
+	// next refresh that does not cover them
+	keep := time.Now().UTC().Add(-idx.window).Truncate(24 * time.Hour)
+	if first.Before(keep) {
+		keep = first
+	}
+	idx.prune(keep)
+
+	for date := first; date.Before(end); date = date.Add(24 * time.Hour) {
+		if err := idx.refreshDay(ctx, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune drops the days before cutoff and the entries stored in them (caller holds refreshMu)
+func (idx *Index) prune(cutoff time.Time) {
+	for key := range idx.days {
+		if key < cutoff.Unix() {
+			delete(idx.days, key)
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	n := sort.Search(len(idx.entries), func(i int) bool {
+		return !idx.entries[i].Timestamp.Before(cutoff)
+	})
+	if n == 0 {
+		return
+	}
+
+	// Each posting list holding a dropped entry is filtered once
+	lists := make(map[[2]string]bool)
+	for _, e := range idx.entries[:n] {
+		delete(idx.byID, e.FractureID)
+		lists[[2]string{"event_id", e.EventID}] = true
+		lists[[2]string{"actor_id", e.ActorID}] = true
+		lists[[2]string{"source", e.Source}] = true
+		lists[[2]string{"correlation_id", e.CorrelationID}] = true
+		for _, check := range e.FailedChecks {
+			lists[[2]string{"failed_check", check}] = true
+		}
+	}
+	for list := range lists {
+		idx.unpost(list[0], list[1], cutoff)
+	}
+	idx.entries = append([]*Entry(nil), idx.entries[n:]...)
+}
+
+// unpost drops the entries before cutoff from one posting list (caller holds mu)
+// Posting lists are in arrival order, so the whole list is scanned
+func (idx *Index) unpost(field, value string, cutoff time.Time) {
+	list, ok := idx.postings[field][value]
+	if !ok {
+		return
+	}
+
+	kept := list[:0]
+	for _, e := range list {
+		if !e.Timestamp.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == 0 {
+		delete(idx.postings[field], value)
+		return
+	}
+	// Clear the tail so the dropped entries can be collected
+	clear(list[len(kept):])
+	idx.postings[field][value] = kept
+}
+
+// refreshDay lists one day and reads its new or changed objects (caller holds refreshMu)
+func (idx *Index) refreshDay(ctx context.Context, date time.Time) error {
+	d, ok := idx.days[date.Unix()]
+	if ok && time.Since(d.listedAt) < listInterval {
+		return nil
+	}
+
+	listed, err := idx.store.ListDay(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to list fractures for %s: %w", date.Format("2006-01-02"), err)
+	}
+
+	objects := make(map[string]string, len(listed))
+	for _, object := range listed {
+		if ok && d.objects[object.Path] == object.Version {
+			objects[object.Path] = object.Version
+			continue
+		}
+
+		fractures, err := idx.store.ReadObject(ctx, object.Path)
+		if err != nil {
+			// Left out of objects, so it is read again on the next refresh
+			log.Printf("[Index] Warning: failed to read %s: %v", object.Path, err)
+			continue
+		}
+		idx.Add(fractures)
+		objects[object.Path] = object.Version
+	}
+
+	// Parts folded into their hourly object drop out of the listing here
+	idx.days[date.Unix()] = &day{listedAt: time.Now(), objects: objects}
+	return nil
+}
+
+// Len returns the number of indexed fractures
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Get returns the entry of one fracture
+// A fracture not indexed yet is looked for in the default window of storage
+func (idx *Index) Get(ctx context.Context, fractureID uuid.UUID) (Entry, bool, error) {
+	if entry, ok := idx.get(fractureID); ok {
+		return entry, true, nil
+	}
+
+	if err := idx.Refresh(ctx, time.Time{}, time.Time{}); err != nil {
+		return Entry{}, false, err
+	}
+
+	entry, ok := idx.get(fractureID)
+	return entry, ok, nil
+}
+
+// get returns the entry of one fracture if it is indexed
+func (idx *Index) get(fractureID uuid.UUID) (Entry, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	entry, ok := idx.byID[fractureID]
+	if !ok {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Add indexes fractures that are not indexed yet, e.g. right after this
+// instance stored them. Re-adding a fracture is a no-op
+func (idx *Index) Add(fractures []*models.FracturedEvent) {
+	idx.mu.Lock()
+
+	var added []Entry
+	for _, fracture := range fractures {
+		if _, exists := idx.byID[fracture.FractureID]; exists {
+			continue
+		}
+
+		entry := NewEntry(fracture)
+		idx.insert(&entry)
+		added = append(added, entry)
+	}
+
+	idx.mu.Unlock()
+
+	if len(added) > 0 && idx.added != nil {
+		idx.added(added)
+	}
+}
+
+// insert places an entry in time order and in its posting lists (caller holds mu)
+func (idx *Index) insert(e *Entry) {
+	if _, exists := idx.byID[e.FractureID]; exists {
+		return
+	}
+	idx.byID[e.FractureID] = e
+
+	// Entries arrive nearly in order, so this is almost always an append
+	i := len(idx.entries)
+	for i > 0 && e.before(idx.entries[i-1]) {
+		i--
+	}
+	idx.entries = append(idx.entries, nil)
+	copy(idx.entries[i+1:], idx.entries[i:])
+	idx.entries[i] = e
+
+	idx.post("event_id", e.EventID, e)
+	idx.post("actor_id", e.ActorID, e)
+	idx.post("source", e.Source, e)
+	idx.post("correlation_id", e.CorrelationID, e)
+	for _, check := range e.FailedChecks {
+		idx.post("failed_check", check, e)
+	}
+}
+
+// post adds an entry to one posting list
+func (idx *Index) post(field, value string, e *Entry) {
+	if value == "" {
+		return
+	}
+	values, ok := idx.postings[field]
+	if !ok {
+		values = make(map[string][]*Entry)
+		idx.postings[field] = values
+	}
+	values[value] = append(values[value], e)
+}
+
+// Query returns up to f.Limit matching entries in time order, and a cursor
+// for the next page ("" when there are no more matches). Without a start it
+// covers the last window. The range is refreshed from storage first
+func (idx *Index) Query(ctx context.Context, f Filter) ([]Entry, string, error) {
+	if f.Start.IsZero() {
+		f.Start, _ = idx.bounds(f.Start, f.End)
+	}
+
+	// Held until the page is read, so a concurrent refresh cannot prune the range
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+	if err := idx.refresh(ctx, f.Start, f.End); err != nil {
+		return nil, "", err
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+
+	var after *Entry
+	if f.Cursor != "" {
+		c, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = c
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	candidates, sorted := idx.candidates(&f)
+	if !sorted {
+		candidates = append([]*Entry(nil), candidates...)
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].before(candidates[j])
+		})
+	}
+
+	// Skip to the cursor and the start of the time range
+	start := sort.Search(len(candidates), func(i int) bool {
+		e := candidates[i]
+		if after != nil && !after.before(e) {
+			return false
+		}
+		return f.Start.IsZero() || !e.Timestamp.Before(f.Start)
+	})
+
+	var page []Entry
+	for _, e := range candidates[start:] {
+		if !f.End.IsZero() && !e.Timestamp.Before(f.End) {
+			break
+		}
+		if !f.matches(e) {
+			continue
+		}
+		if len(page) == f.Limit {
+			last := page[len(page)-1]
+			return page, encodeCursor(&last), nil
+		}
+		page = append(page, *e)
+	}
+
+	return page, "", nil
+}
+
+// candidates picks the smallest posting list among the filter's fields,
+// falling back to every entry. The bool reports whether the list is already
+// in time order
+func (idx *Index) candidates(f *Filter) ([]*Entry, bool) {
+	var best []*Entry
+	found := false
+
+	for field, value := range map[string]string{
+		"event_id":       f.EventID,
+		"actor_id":       f.ActorID,
+		"source":         f.Source,
+		"correlation_id": f.CorrelationID,
+		"failed_check":   f.FailedCheck,
+	} {
+		if value == "" {
+			continue
+		}
+		list := idx.postings[field][value]
+		if !found || len(list) < len(best) {
+			best = list
+			found = true
+		}
+	}
+
+	if !found {
+		return idx.entries, true
+	}
+	return best, false
+}
+
+// encodeCursor packs an entry's sort key into an opaque cursor
+func encodeCursor(e *Entry) string {
+	raw := strconv.FormatInt(e.Timestamp.UnixNano(), 10) + "|" + e.FractureID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor unpacks a cursor into a sort key
+func decodeCursor(cursor string) (*Entry, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	fractureID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Entry{Timestamp: time.Unix(0, n).UTC(), FractureID: fractureID}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+func newStore(t *testing.T) *storage.LocalStore {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	return store
+}
+
+func newFracture(ts time.Time, actorID string) *models.FracturedEvent {
+	return &models.FracturedEvent{
+		FractureID: uuid.New(),
+		Timestamp:  ts,
+		Event: models.Event{
+			ID:    uuid.New(),
+			Type:  "payment",
+			Actor: models.Actor{ID: actorID},
+		},
+		Rejection: models.RejectionDetails{FailedChecks: []string{"balance"}},
+	}
+}
+
+// write stores fractures the way the queue sink does
+func write(t *testing.T, store storage.FractureStore, fractures ...*models.FracturedEvent) {
+	t.Helper()
+	if err := store.WriteFractureBatch(context.Background(), fractures); err != nil {
+		t.Fatalf("WriteFractureBatch: %v", err)
+	}
+}
+
+func TestQueryPagesAcrossEqualTimestamps(t *testing.T) {
+	store := newStore(t)
+	ts := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	// Seven fractures share one timestamp, so only the fracture ID orders them
+	var fractures []*models.FracturedEvent
+	for i := 0; i < 7; i++ {
+		fractures = append(fractures, newFracture(ts, fmt.Sprintf("actor-%d", i%2)))
+	}
+	write(t, store, fractures...)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{name: "every entry", filter: Filter{}, want: 7},
+		{name: "posting list", filter: Filter{ActorID: "actor-0"}, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := New(store, 24*time.Hour, nil)
+			seen := make(map[uuid.UUID]bool)
+			var last *Entry
+
+			f := tt.filter
+			f.Limit = 2
+			for pages := 0; ; pages++ {
+				if pages > tt.want {
+					t.Fatal("paging did not terminate")
+				}
+				page, cursor, err := idx.Query(context.Background(), f)
+				if err != nil {
+					t.Fatalf("Query: %v", err)
+				}
+				for i := range page {
+					e := &page[i]
+					if seen[e.FractureID] {
+						t.Fatalf("fracture %s returned twice", e.FractureID)
+					}
+					if last != nil && !last.before(e) {
+						t.Fatalf("fracture %s out of order", e.FractureID)
+					}
+					seen[e.FractureID] = true
+					last = e
+				}
+				if cursor == "" {
+					break
+				}
+				f.Cursor = cursor
+			}
+
+			if len(seen) != tt.want {
+				t.Errorf("paged through %d fractures, want %d", len(seen), tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryRejectsForeignCursor(t *testing.T) {
+	idx := New(newStore(t), 24*time.Hour, nil)
+	if _, _, err := idx.Query(context.Background(), Filter{Cursor: "not-a-cursor"}); err != ErrInvalidCursor {
+		t.Errorf("Query with a bad cursor = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestRefreshPrunesOutsideWindow(t *testing.T) {
+	store := newStore(t)
+	now := time.Now().UTC()
+
+	old := newFracture(now.Add(-5*24*time.Hour), "actor-old")
+	recent := newFracture(now.Add(-time.Hour), "actor-new")
+	write(t, store, old, recent)
+
+	var counted int
+	idx := New(store, 2*24*time.Hour, func(entries []Entry) { counted += len(entries) })
+
+	// A query reaching past the window reads the old day back
+	page, _, err := idx.Query(context.Background(), Filter{
+		ActorID: "actor-old",
+		Start:   now.Add(-6 * 24 * time.Hour),
+		End:     now.Add(-4 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(page) != 1 || page[0].FractureID != old.FractureID {
+		t.Fatalf("old range = %+v, want the old fracture", page)
+	}
+
+	// The next refresh of the window drops it again, postings included
+	if err := idx.Refresh(context.Background(), time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if n := idx.Len(); n != 1 {
+		t.Errorf("Len after pruning = %d, want 1", n)
+	}
+	if _, ok := idx.get(old.FractureID); ok {
+		t.Error("old fracture is still indexed by ID")
+	}
+	if list := idx.postings["actor_id"]["actor-old"]; len(list) != 0 {
+		t.Errorf("old actor still has %d postings", len(list))
+	}
+	if _, ok := idx.days[now.Add(-5*24*time.Hour).Truncate(24*time.Hour).Unix()]; ok {
+		t.Error("old day is still listed")
+	}
+
+	// Queries over the window are unaffected
+	page, _, err = idx.Query(context.Background(), Filter{FailedCheck: "balance"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(page) != 1 || page[0].FractureID != recent.FractureID {
+		t.Errorf("window = %+v, want the recent fracture", page)
+	}
+	if counted != 2 {
+		t.Errorf("added called for %d entries, want 2", counted)
+	}
+}
@@ -0,0 +1,413 @@
+// Package queue is a bounded, disk-backed write queue for fractures
+// Requests are acknowledged once their fractures are fsynced to the queue log;
+// a fixed pool of workers writes them to storage with retries, and records
+// that keep failing are moved to a dead-letter log instead of being dropped
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+	"github.com/veps-service-480701/veps-common/durablelog"
+)
+
+const (
+	logName      = "queue" // queue.log and queue.ack
+	deadFileName = "dead-letter.jsonl"
+
+	// compactAfterAcks rewrites the log once this many records were acknowledged
+	compactAfterAcks = 10000
+	// writeTimeout bounds a single storage write
+	writeTimeout = 30 * time.Second
+	// initialBackoff is the retry delay after the first failure, doubled per attempt
+	initialBackoff = 500 * time.Millisecond
+	// maxBackoff caps the retry delay
+	maxBackoff = 30 * time.Second
+)
+
+var (
+	// ErrFull is returned by Enqueue when the queue is at capacity
+	ErrFull = errors.New("fracture queue is full")
+	// ErrClosed is returned by Enqueue after Close has been called
+	ErrClosed = errors.New("fracture queue is closed")
+)
+
+// Sink writes fractures to storage
+type Sink func(ctx context.Context, fractures []*models.FracturedEvent) error
+
+// Config sizes the queue
+type Config struct {
+	Dir         string
+	Capacity    int // maximum queued requests
+	Workers     int
+	MaxAttempts int // attempts before a record is dead-lettered
+}
+
+// Record is one accepted request persisted in the queue log
+type Record struct {
+	Seq        uint64                   `json:"seq"`
+	EnqueuedAt time.Time                `json:"enqueued_at"`
+	Fractures  []*models.FracturedEvent `json:"fractures"`
+}
+
+// deadRecord is a record that exhausted its attempts
+type deadRecord struct {
+	Record
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// Stats describes the current state of the queue
+type Stats struct {
+	Depth        int    `json:"depth"`
+	InFlight     int    `json:"in_flight"`
+	Capacity     int    `json:"capacity"`
+	Workers      int    `json:"workers"`
+	OldestAge    string `json:"oldest_age,omitempty"`
+	Written      uint64 `json:"written"`
+	Retries      uint64 `json:"retries"`
+	DeadLettered uint64 `json:"dead_lettered"`
+	Rejected     uint64 `json:"rejected"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+// Queue is a durable, bounded queue drained by a fixed worker pool
+type Queue struct {
+	cfg  Config
+	sink Sink
+
+	mu           sync.Mutex
+	records      *durablelog.Log[Record]
+	dead         *durablelog.DeadLetters
+	pending      map[uint64]*Record
+	inFlight     int
+	closed       bool
+	written      uint64
+	retries      uint64
+	deadLettered uint64
+	rejected     uint64
+	lastError    string
+
+	work  chan *Record
+	abort chan struct{} // closed when Close gives up on draining
+	wg    sync.WaitGroup
+}
+
+// Open opens (or creates) the queue in cfg.Dir and recovers any pending records
+func Open(cfg Config, sink Sink) (*Queue, error) {
+	records, recovered, err := durablelog.Open(cfg.Dir, logName, func(r *Record) *uint64 { return &r.Seq })
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue: %w", err)
+	}
+
+	dead, err := durablelog.OpenDeadLetters(filepath.Join(cfg.Dir, deadFileName))
+	if err != nil {
+		records.Close()
+		return nil, err
+	}
+
+	q := &Queue{
+		cfg:     cfg,
+		sink:    sink,
+		records: records,
+		dead:    dead,
+		pending: make(map[uint64]*Record, len(recovered)),
+		abort:   make(chan struct{}),
+	}
+
+	// Recovered records may exceed the configured capacity
+	q.work = make(chan *Record, max(cfg.Capacity, len(recovered)))
+	for _, record := range recovered {
+		q.pending[record.Seq] = record
+		q.work <- record
+	}
+
+	log.Printf("[Queue] Opened at %s with %d pending requests", cfg.Dir, len(q.pending))
+	return q, nil
+}
+
+// sortedPending returns pending records in enqueue order
+func (q *Queue) sortedPending() []*Record {
+	records := make([]*Record, 0, len(q.pending))
+	for _, record := range q.pending {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Seq < records[j].Seq
+	})
+	return records
+}
+
+// compact rewrites the log with only pending records (caller holds mu)
+func (q *Queue) compact() error {
+	return q.records.Compact(q.sortedPending())
+}
+
+// Enqueue durably appends fractures; it returns once they are on disk
+// It fails with ErrFull when the queue is at capacity and ErrClosed during shutdown
+func (q *Queue) Enqueue(fractures []*models.FracturedEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		q.rejected++
+		return ErrClosed
+	}
+	if len(q.pending) >= q.cfg.Capacity {
+		q.rejected++
+		return ErrFull
+	}
+
+	record := &Record{
+		EnqueuedAt: time.Now().UTC(),
+		Fractures:  fractures,
+	}
+	if err := q.records.Append(record); err != nil {
+		return fmt.Errorf("failed to append to queue: %w", err)
+	}
+
+	q.pending[record.Seq] = record
+
+	// Never blocks: the channel holds at most len(q.pending) records
+	q.work <- record
+
+	return nil
+}
+
+// Start launches the worker pool
+func (q *Queue) Start() {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+}
+
+// worker writes queued records until the queue is closed and drained
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	for record := range q.work {
+		select {
+		case <-q.abort:
+			// Leave the record on disk for the next start
+			continue
+		default:
+		}
+
+		q.setInFlight(1)
+		q.process(record)
+		q.setInFlight(-1)
+	}
+}
+
+func (q *Queue) setInFlight(delta int) {
+	q.mu.Lock()
+	q.inFlight += delta
+	q.mu.Unlock()
+}
+
+// process writes one record, retrying with backoff, and acknowledges or dead-letters it
+// A record that cannot be dead-lettered either goes back through the retries,
+// so it is never left pending with no worker on it
+func (q *Queue) process(record *Record) {
+	for {
+		err := q.write(record)
+		if err == nil || errors.Is(err, errAborted) {
+			return
+		}
+
+		log.Printf("[Queue] ERROR: Request %d failed %d times, moving %d fractures to dead letter: %v",
+			record.Seq, q.cfg.MaxAttempts, len(record.Fractures), err)
+
+		dlErr := q.deadLetter(record, err)
+		if dlErr == nil {
+			return
+		}
+
+		log.Printf("[Queue] ERROR: failed to dead-letter request %d, retrying in %s: %v",
+			record.Seq, deadLetterRetry, dlErr)
+		if !q.wait(deadLetterRetry) {
+			return
+		}
+	}
+}
+
+var (
+	// deadLetterRetry is how long a record that could not be dead-lettered
+	// waits before it goes back through the retries
+	deadLetterRetry = maxBackoff
+
+	// errAborted is returned by write when the shutdown deadline interrupts it
+	errAborted = errors.New("write aborted by shutdown")
+)
+
+// write makes up to MaxAttempts attempts to write a record and acknowledges
+// it on success; it returns the last error once the attempts are used up
+func (q *Queue) write(record *Record) error {
+	var err error
+
+	for attempt := 1; attempt <= q.cfg.MaxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		err = q.sink(ctx, record.Fractures)
+		cancel()
+
+		if err == nil {
+			q.ack(record, func() { q.written++ })
+			return nil
+		}
+
+		q.mu.Lock()
+		q.lastError = err.Error()
+		q.mu.Unlock()
+
+		if attempt == q.cfg.MaxAttempts {
+			break
+		}
+
+		backoff := initialBackoff << (attempt - 1)
+		if backoff > maxBackoff || backoff <= 0 {
+			backoff = maxBackoff
+		}
+
+		log.Printf("[Queue] Write of request %d (%d fractures) failed (attempt %d, retry in %s): %v",
+			record.Seq, len(record.Fractures), attempt, backoff, err)
+
+		q.mu.Lock()
+		q.retries++
+		q.mu.Unlock()
+
+		if !q.wait(backoff) {
+			return errAborted
+		}
+	}
+
+	return err
+}
+
+// wait sleeps for d; false means the shutdown deadline was reached first, in
+// which case the record stays pending on disk
+func (q *Queue) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-q.abort:
+		return false
+	}
+}
+
+// deadLetter appends a record to the dead-letter log and acknowledges it
+// On error the record is still pending
+func (q *Queue) deadLetter(record *Record, cause error) error {
+	q.mu.Lock()
+	err := q.dead.Write(deadRecord{
+		Record:    *record,
+		Attempts:  q.cfg.MaxAttempts,
+		LastError: cause.Error(),
+		FailedAt:  time.Now().UTC(),
+	})
+	q.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	q.ack(record, func() { q.deadLettered++ })
+	return nil
+}
+
+// ack removes a record from the queue; count updates the matching counter
+func (q *Queue) ack(record *Record, count func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Losing an ack only causes the record to be written again on restart
+	if err := q.records.Ack(record.Seq); err != nil {
+		log.Printf("[Queue] Warning: %v", err)
+	}
+
+	delete(q.pending, record.Seq)
+	count()
+
+	// In-flight records are still pending, so they survive compaction
+	if len(q.pending) == 0 || q.records.Acked() >= compactAfterAcks {
+		if err := q.compact(); err != nil {
+			log.Printf("[Queue] Warning: compaction failed: %v", err)
+		}
+	}
+}
+
+// Stats returns a snapshot of the queue state for health reporting
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := Stats{
+		Depth:        len(q.pending),
+		InFlight:     q.inFlight,
+		Capacity:     q.cfg.Capacity,
+		Workers:      q.cfg.Workers,
+		Written:      q.written,
+		Retries:      q.retries,
+		DeadLettered: q.deadLettered,
+		Rejected:     q.rejected,
+		LastError:    q.lastError,
+	}
+
+	var oldest time.Time
+	for _, record := range q.pending {
+		if oldest.IsZero() || record.EnqueuedAt.Before(oldest) {
+			oldest = record.EnqueuedAt
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestAge = time.Since(oldest).Round(time.Millisecond).String()
+	}
+
+	return stats
+}
+
+// Close stops accepting requests and drains the queue until ctx is done
+// Records not written by then stay on disk and are retried on the next start
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	close(q.work)
+	q.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		close(q.abort)
+		<-drained
+		err = fmt.Errorf("fracture queue not drained: %w", ctx.Err())
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.records.Close()
+	q.dead.Close()
+
+	if remaining := len(q.pending); remaining > 0 {
+		log.Printf("[Queue] %d requests left on disk for the next start", remaining)
+	}
+	return err
+}
@@ -0,0 +1,212 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+	"github.com/veps-service-480701/veps-common/durablelog"
+)
+
+// fakeSink records written fractures and fails while failing is set
+type fakeSink struct {
+	mu      sync.Mutex
+	failing bool
+	calls   int
+	written []uuid.UUID
+}
+
+func (s *fakeSink) write(ctx context.Context, fractures []*models.FracturedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.failing {
+		return errors.New("storage unavailable")
+	}
+	for _, f := range fractures {
+		s.written = append(s.written, f.FractureID)
+	}
+	return nil
+}
+
+func (s *fakeSink) setFailing(failing bool) {
+	s.mu.Lock()
+	s.failing = failing
+	s.mu.Unlock()
+}
+
+func (s *fakeSink) snapshot() (int, []uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls, append([]uuid.UUID(nil), s.written...)
+}
+
+func openQueue(t *testing.T, dir string, sink *fakeSink, maxAttempts int) *Queue {
+	t.Helper()
+	q, err := Open(Config{Dir: dir, Capacity: 10, Workers: 1, MaxAttempts: maxAttempts}, sink.write)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return q
+}
+
+func newFracture() *models.FracturedEvent {
+	return &models.FracturedEvent{FractureID: uuid.New(), Timestamp: time.Now().UTC()}
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestQueueRecoversPendingRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	// Accepted but never written: the workers were not started
+	sink := &fakeSink{}
+	q := openQueue(t, dir, sink, 3)
+	fracture := newFracture()
+	if err := q.Enqueue([]*models.FracturedEvent{fracture}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened := openQueue(t, dir, sink, 3)
+	if depth := reopened.Stats().Depth; depth != 1 {
+		t.Fatalf("depth after reopen = %d, want 1", depth)
+	}
+	reopened.Start()
+	waitFor(t, "the recovered record to be written", func() bool { return reopened.Stats().Depth == 0 })
+	if err := reopened.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, written := sink.snapshot(); len(written) != 1 || written[0] != fracture.FractureID {
+		t.Errorf("written = %v, want [%s]", written, fracture.FractureID)
+	}
+}
+
+func TestQueueRetriesFailedWrites(t *testing.T) {
+	sink := &fakeSink{failing: true}
+	q := openQueue(t, t.TempDir(), sink, 5)
+	q.Start()
+	defer q.Close(context.Background())
+
+	if err := q.Enqueue([]*models.FracturedEvent{newFracture()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, "a failed attempt", func() bool { calls, _ := sink.snapshot(); return calls >= 1 })
+	sink.setFailing(false)
+
+	waitFor(t, "the retry to succeed", func() bool { return q.Stats().Written == 1 })
+	stats := q.Stats()
+	if stats.Depth != 0 || stats.Retries == 0 || stats.DeadLettered != 0 {
+		t.Errorf("stats = %+v, want one retried write and nothing pending", stats)
+	}
+}
+
+func TestQueueDeadLettersExhaustedRecords(t *testing.T) {
+	dir := t.TempDir()
+	sink := &fakeSink{failing: true}
+	q := openQueue(t, dir, sink, 1)
+	q.Start()
+
+	if err := q.Enqueue([]*models.FracturedEvent{newFracture()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, "the record to be dead-lettered", func() bool { return q.Stats().DeadLettered == 1 })
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, deadFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "storage unavailable") {
+		t.Errorf("dead-letter log = %q, want the last error", data)
+	}
+}
+
+func TestQueueRetriesRecordsItCannotDeadLetter(t *testing.T) {
+	defer func(d time.Duration) { deadLetterRetry = d }(deadLetterRetry)
+	deadLetterRetry = 10 * time.Millisecond
+
+	dir := t.TempDir()
+	sink := &fakeSink{failing: true}
+	q := openQueue(t, dir, sink, 1)
+
+	// Dead-lettering fails while the dead-letter file is closed
+	q.dead.Close()
+	q.Start()
+
+	if err := q.Enqueue([]*models.FracturedEvent{newFracture()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// The record goes back through the retries instead of sitting pending
+	waitFor(t, "the record to be retried", func() bool { calls, _ := sink.snapshot(); return calls >= 3 })
+	if stats := q.Stats(); stats.Depth != 1 || stats.DeadLettered != 0 {
+		t.Fatalf("stats = %+v, want the record still pending", stats)
+	}
+
+	sink.setFailing(false)
+	waitFor(t, "the record to be written", func() bool { return q.Stats().Written == 1 })
+
+	q.mu.Lock()
+	dead, err := durablelog.OpenDeadLetters(filepath.Join(dir, deadFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.dead = dead
+	q.mu.Unlock()
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if depth := q.Stats().Depth; depth != 0 {
+		t.Errorf("depth = %d, want 0", depth)
+	}
+}
+
+func TestQueueCloseLeavesUnwrittenRecordsOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	sink := &fakeSink{failing: true}
+	q := openQueue(t, dir, sink, 100)
+	q.Start()
+
+	if err := q.Enqueue([]*models.FracturedEvent{newFracture()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, "a failed attempt", func() bool { calls, _ := sink.snapshot(); return calls >= 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); err == nil {
+		t.Error("Close with a failing sink drained the queue")
+	}
+	if err := q.Enqueue([]*models.FracturedEvent{newFracture()}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Enqueue after Close = %v, want %v", err, ErrClosed)
+	}
+
+	reopened := openQueue(t, dir, &fakeSink{}, 100)
+	defer reopened.Close(context.Background())
+	if depth := reopened.Stats().Depth; depth != 1 {
+		t.Errorf("depth after reopen = %d, want 1", depth)
+	}
+}
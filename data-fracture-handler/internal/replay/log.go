@@ -0,0 +1,208 @@
+// Package replay records fracture replays
+// Records are kept in the fracture bucket and shared by every instance. A
+// fracture is claimed by creating its claim record, which only one caller
+// can do, before anything is sent, so it is replayed at most once across
+// instances and restarts
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/sharedlog"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+const (
+	claimPrefix = "replays/claims/"
+	logPrefix   = "replays/log/"
+
+	// claimWorkers is the number of claim records created concurrently
+	claimWorkers = 16
+	// staleAfter is how long a replay may stay pending before it is reported
+	// interrupted; a full run of 1000 fractures takes about an hour at worst
+	staleAfter = 2 * time.Hour
+)
+
+// Log holds the latest replay record per fracture, following a shared log
+type Log struct {
+	store   storage.RecordStore
+	entries *sharedlog.Log[[]models.ReplayRecord]
+
+	mu      sync.RWMutex
+	records map[uuid.UUID]*models.ReplayRecord
+}
+
+// Open creates a replay log over the records in store
+func Open(store storage.RecordStore) *Log {
+	return &Log{
+		store:   store,
+		entries: sharedlog.New[[]models.ReplayRecord](store, logPrefix),
+		records: make(map[uuid.UUID]*models.ReplayRecord),
+	}
+}
+
+// Sync reads the replay records written by every instance since the last Sync
+func (l *Log) Sync(ctx context.Context) error {
+	return l.entries.Sync(ctx, func(records []models.ReplayRecord) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.apply(records)
+	})
+}
+
+// Get returns the replay record of a fracture
+func (l *Log) Get(fractureID uuid.UUID) (models.ReplayRecord, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	record, ok := l.records[fractureID]
+	if !ok {
+		return models.ReplayRecord{}, false
+	}
+	return current(*record), true
+}
+
+// Claim creates claim records for the given records' fractures and returns
+// the ones it claimed. Fractures claimed before, by any instance, are skipped
+func (l *Log) Claim(ctx context.Context, records []models.ReplayRecord) ([]models.ReplayRecord, error) {
+	var candidates []models.ReplayRecord
+	seen := make(map[uuid.UUID]bool, len(records))
+	for _, record := range records {
+		if _, exists := l.Get(record.FractureID); exists || seen[record.FractureID] {
+			continue
+		}
+		seen[record.FractureID] = true
+		record.Status = models.ReplayPending
+		record.ClaimedAt = time.Now().UTC()
+		candidates = append(candidates, record)
+	}
+
+	won, err := l.createClaims(ctx, candidates)
+
+	var claimed []models.ReplayRecord
+	for i, record := range candidates {
+		if won[i] {
+			claimed = append(claimed, record)
+		}
+	}
+
+	// Claims that were created are recorded even if others failed
+	if len(claimed) > 0 {
+		if appendErr := l.entries.Append(ctx, claimed); appendErr != nil && err == nil {
+			err = appendErr
+		}
+		l.mu.Lock()
+		l.apply(claimed)
+		l.mu.Unlock()
+	}
+
+	return claimed, err
+}
+
+// createClaims creates the claim record of each candidate concurrently and
+// reports which ones this call created
+func (l *Log) createClaims(ctx context.Context, candidates []models.ReplayRecord) ([]bool, error) {
+	won := make([]bool, len(candidates))
+	errs := make([]error, len(candidates))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(claimWorkers, len(candidates)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				won[i], errs[i] = l.createClaim(ctx, candidates[i])
+			}
+		}()
+	}
+
+	for i := range candidates {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return won, errors.Join(errs...)
+}
+
+// createClaim creates one claim record; false means another caller claimed it first
+func (l *Log) createClaim(ctx context.Context, record models.ReplayRecord) (bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal replay claim: %w", err)
+	}
+
+	err = l.store.CreateRecord(ctx, claimPrefix+record.FractureID.String()+".json", data)
+	if errors.Is(err, storage.ErrRecordExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim fracture %s: %w", record.FractureID, err)
+	}
+	return true, nil
+}
+
+// Complete records the outcome of a claimed replay
+func (l *Log) Complete(ctx context.Context, record models.ReplayRecord) error {
+	completed := time.Now().UTC()
+	record.CompletedAt = &completed
+
+	if err := l.entries.Append(ctx, []models.ReplayRecord{record}); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.apply([]models.ReplayRecord{record})
+	return nil
+}
+
+// ByRun returns the records of one replay run, in claim order
+func (l *Log) ByRun(runID string) []models.ReplayRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var records []models.ReplayRecord
+	for _, record := range l.records {
+		if record.RunID == runID {
+			records = append(records, current(*record))
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ClaimedAt.Before(records[j].ClaimedAt)
+	})
+	return records
+}
+
+// apply stores records as the latest of their fractures (caller holds mu)
+// Entries from other instances can arrive out of order, so a pending record
+// never replaces a completed one
+func (l *Log) apply(records []models.ReplayRecord) {
+	for i := range records {
+		record := records[i]
+		if existing, ok := l.records[record.FractureID]; ok && existing.CompletedAt != nil && record.CompletedAt == nil {
+			continue
+		}
+		l.records[record.FractureID] = &record
+	}
+}
+
+// current reports a replay left pending for too long as interrupted
+// The instance running it stopped mid-run; the event may have reached the
+// target, so it is not sent again
+func current(record models.ReplayRecord) models.ReplayRecord {
+	if record.Status == models.ReplayPending && time.Since(record.ClaimedAt) > staleAfter {
+		record.Status = models.ReplayInterrupted
+	}
+	return record
+}
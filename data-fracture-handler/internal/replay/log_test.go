@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+func newStore(t *testing.T) *storage.LocalStore {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	return store
+}
+
+func replayRecords(runID string, fractureIDs ...uuid.UUID) []models.ReplayRecord {
+	records := make([]models.ReplayRecord, len(fractureIDs))
+	for i, id := range fractureIDs {
+		records[i] = models.ReplayRecord{FractureID: id, RunID: runID, Target: models.ReplayTargetBoundary}
+	}
+	return records
+}
+
+func TestClaimedFracturesAreNeverClaimedAgain(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a, b := uuid.New(), uuid.New()
+
+	l := Open(store)
+	claimed, err := l.Claim(ctx, replayRecords("run-1", a, b, a))
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("claimed %d fractures, want 2", len(claimed))
+	}
+
+	record := claimed[0]
+	record.Status = models.ReplayVetoed
+	if err := l.Complete(ctx, record); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	// Neither this instance nor a restarted one claims them again, whatever
+	// the outcome, even before reading the shared log
+	if again, err := l.Claim(ctx, replayRecords("run-2", a, b)); err != nil || len(again) != 0 {
+		t.Errorf("second claim = %d fractures (%v), want none", len(again), err)
+	}
+	restarted := Open(store)
+	if again, err := restarted.Claim(ctx, replayRecords("run-3", a, b)); err != nil || len(again) != 0 {
+		t.Errorf("claim after restart = %d fractures (%v), want none", len(again), err)
+	}
+
+	if err := restarted.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	got, ok := restarted.Get(record.FractureID)
+	if !ok || got.Status != models.ReplayVetoed || got.RunID != "run-1" {
+		t.Errorf("record after restart = %+v, want run-1 vetoed", got)
+	}
+	if runs := restarted.ByRun("run-1"); len(runs) != 2 {
+		t.Errorf("run-1 has %d records, want 2", len(runs))
+	}
+}
+
+func TestConcurrentClaimsAreExclusive(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+
+	var ids []uuid.UUID
+	for i := 0; i < 50; i++ {
+		ids = append(ids, uuid.New())
+	}
+
+	// Two instances race for the same selection
+	instances := []*Log{Open(store), Open(store)}
+	results := make([][]models.ReplayRecord, len(instances))
+	var wg sync.WaitGroup
+	for i, l := range instances {
+		wg.Add(1)
+		go func(i int, l *Log) {
+			defer wg.Done()
+			claimed, err := l.Claim(ctx, replayRecords("run", ids...))
+			if err != nil {
+				t.Errorf("Claim: %v", err)
+			}
+			results[i] = claimed
+		}(i, l)
+	}
+	wg.Wait()
+
+	owners := make(map[uuid.UUID]int)
+	for _, claimed := range results {
+		for _, record := range claimed {
+			owners[record.FractureID]++
+		}
+	}
+	if len(owners) != len(ids) {
+		t.Errorf("%d of %d fractures claimed", len(owners), len(ids))
+	}
+	for id, n := range owners {
+		if n != 1 {
+			t.Errorf("fracture %s claimed %d times", id, n)
+		}
+	}
+}
+
+func TestStalePendingReplayIsInterrupted(t *testing.T) {
+	l := Open(newStore(t))
+	id := uuid.New()
+
+	l.apply([]models.ReplayRecord{{
+		FractureID: id,
+		Status:     models.ReplayPending,
+		ClaimedAt:  time.Now().Add(-staleAfter - time.Minute),
+	}})
+
+	got, ok := l.Get(id)
+	if !ok || got.Status != models.ReplayInterrupted {
+		t.Errorf("stale record = %+v, want interrupted", got)
+	}
+	if again, _ := l.Claim(context.Background(), replayRecords("run", id)); len(again) != 0 {
+		t.Error("an interrupted replay was claimed again")
+	}
+}
@@ -0,0 +1,166 @@
+// Package sharedlog is an append-only log of JSON entries kept in the
+// fracture bucket and shared by every instance of the service
+// Each entry is its own record, named by its append time, so writers never
+// overwrite each other. Instances follow the log by listing the entries
+// after the newest ones they have read
+package sharedlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+)
+
+const (
+	// lateWindow is how far behind the newest entry read a listing starts, so
+	// entries named by a lagging clock or stored by a slow upload are not missed
+	lateWindow = 5 * time.Minute
+	// readWorkers is the number of entries read concurrently
+	readWorkers = 16
+)
+
+// Log is a shared append-only log of entries of type T under a prefix
+type Log[T any] struct {
+	store  storage.RecordStore
+	prefix string
+
+	mu      sync.Mutex
+	applied map[string]time.Time // entries within the late window -> append time
+	newest  time.Time
+}
+
+// New creates a log of the records under prefix, which ends in "/"
+func New[T any](store storage.RecordStore, prefix string) *Log[T] {
+	return &Log[T]{
+		store:   store,
+		prefix:  prefix,
+		applied: make(map[string]time.Time),
+	}
+}
+
+// Append stores an entry and returns once it is durable
+// The caller applies its own entry; Sync does not hand it back
+func (l *Log[T]) Append(ctx context.Context, entry T) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+
+	at := time.Now().UTC()
+	name := fmt.Sprintf("%s%020d-%s.json", l.prefix, at.UnixNano(), uuid.NewString()[:8])
+
+	if err := l.store.CreateRecord(ctx, name, data); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", l.prefix, err)
+	}
+
+	l.mu.Lock()
+	l.markApplied(name, at)
+	l.mu.Unlock()
+	return nil
+}
+
+// Sync reads the entries appended since the last Sync, by any instance, and
+// passes each to apply once, in append order
+func (l *Log[T]) Sync(ctx context.Context, apply func(T)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	startAfter := ""
+	if !l.newest.IsZero() {
+		startAfter = fmt.Sprintf("%s%020d", l.prefix, l.newest.Add(-lateWindow).UnixNano())
+	}
+
+	names, err := l.store.ListRecords(ctx, l.prefix, startAfter)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", l.prefix, err)
+	}
+
+	var unread []string
+	for _, name := range names {
+		if _, ok := l.applied[name]; !ok {
+			unread = append(unread, name)
+		}
+	}
+	if len(unread) == 0 {
+		return nil
+	}
+
+	entries := l.read(ctx, unread)
+	for i, name := range unread {
+		if entries[i] == nil {
+			// Left unapplied, so it is read again on the next Sync
+			continue
+		}
+		apply(*entries[i])
+		l.markApplied(name, appendTime(strings.TrimPrefix(name, l.prefix)))
+	}
+
+	// Entries older than the late window are no longer listed
+	for name, at := range l.applied {
+		if at.Before(l.newest.Add(-lateWindow)) {
+			delete(l.applied, name)
+		}
+	}
+	return nil
+}
+
+// read reads and decodes entries concurrently; unreadable entries are nil
+func (l *Log[T]) read(ctx context.Context, names []string) []*T {
+	entries := make([]*T, len(names))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(readWorkers, len(names)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				data, err := l.store.ReadRecord(ctx, names[i])
+				if err != nil {
+					log.Printf("[SharedLog] Warning: failed to read %s: %v", names[i], err)
+					continue
+				}
+				entry := new(T)
+				if err := json.Unmarshal(data, entry); err != nil {
+					log.Printf("[SharedLog] Warning: skipping unreadable entry %s: %v", names[i], err)
+					continue
+				}
+				entries[i] = entry
+			}
+		}()
+	}
+
+	for i := range names {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return entries
+}
+
+// markApplied records an entry as applied (caller holds mu)
+func (l *Log[T]) markApplied(name string, at time.Time) {
+	l.applied[name] = at
+	if at.After(l.newest) {
+		l.newest = at
+	}
+}
+
+// appendTime parses the append time from an entry name
+func appendTime(name string) time.Time {
+	nanos, _, _ := strings.Cut(name, "-")
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
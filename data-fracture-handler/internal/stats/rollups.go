@@ -0,0 +1,298 @@
+// Package stats keeps fracture counts rolled up by time bucket
+// Counts are added as fractures are indexed, at minute, hour and day
+// granularity, so reading a window only sums the buckets it covers. The
+// index follows shared storage, so every instance counts every fracture in
+// the window the index covers; older windows are rejected
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+)
+
+// Dimensions fractures are counted by
+const (
+	DimFailedCheck = "failed_check"
+	DimEventType   = "event_type"
+	DimSource      = "source"
+	DimActor       = "actor"
+	DimVetoNode    = "veto_node"
+)
+
+// Dimensions lists every dimension
+var Dimensions = []string{DimFailedCheck, DimEventType, DimSource, DimActor, DimVetoNode}
+
+// Granularities
+const (
+	Minute = "minute"
+	Hour   = "hour"
+	Day    = "day"
+)
+
+// MaxBuckets caps the buckets a single query may span
+const MaxBuckets = 1440
+
+// ErrInvalidWindow is returned for a window that cannot be answered
+var ErrInvalidWindow = errors.New("invalid stats window")
+
+// level is one granularity of rollups
+type level struct {
+	size      time.Duration
+	retention time.Duration // 0 keeps buckets forever
+	buckets   map[int64]*bucket
+}
+
+// bucket holds the counts of one time bucket
+type bucket struct {
+	total  int64
+	counts map[string]map[string]int64 // dimension -> value -> count
+}
+
+// Rollups holds fracture counts at every granularity
+type Rollups struct {
+	mu       sync.RWMutex
+	coverage time.Duration
+	levels   map[string]*level
+}
+
+// New creates empty rollups answering windows within the last coverage,
+// the window the index keeps up to date with storage
+// Buckets are kept for the coverage, and minute buckets for 2 days at most.
+// Nothing older is counted: the index drops fractures outside its window and
+// would count them again if a query read them back
+func New(coverage time.Duration) *Rollups {
+	return &Rollups{
+		coverage: coverage,
+		levels: map[string]*level{
+			Minute: {size: time.Minute, retention: min(48*time.Hour, coverage), buckets: make(map[int64]*bucket)},
+			Hour:   {size: time.Hour, retention: coverage, buckets: make(map[int64]*bucket)},
+			Day:    {size: 24 * time.Hour, retention: coverage, buckets: make(map[int64]*bucket)},
+		},
+	}
+}
+
+// DefaultWindow is the window a query covers when it sets no start
+func (r *Rollups) DefaultWindow(granularity string) time.Duration {
+	switch granularity {
+	case Minute:
+		return time.Hour
+	case Hour:
+		return 24 * time.Hour
+	}
+	return r.coverage
+}
+
+// Add counts newly indexed fractures
+func (r *Rollups) Add(entries []index.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, lvl := range r.levels {
+		created := false
+		for i := range entries {
+			e := &entries[i]
+			if lvl.retention > 0 && e.Timestamp.Before(now.Add(-lvl.retention)) {
+				continue
+			}
+
+			key := e.Timestamp.Truncate(lvl.size).Unix()
+			b, ok := lvl.buckets[key]
+			if !ok {
+				b = &bucket{counts: make(map[string]map[string]int64)}
+				lvl.buckets[key] = b
+				created = true
+			}
+
+			b.total++
+			for _, check := range e.FailedChecks {
+				b.add(DimFailedCheck, check)
+			}
+			b.add(DimEventType, e.EventType)
+			b.add(DimSource, e.Source)
+			b.add(DimActor, e.ActorID)
+			b.add(DimVetoNode, e.VetoNode)
+		}
+		if created {
+			lvl.prune(now)
+		}
+	}
+}
+
+// add increments one dimension value
+func (b *bucket) add(dim, value string) {
+	if value == "" {
+		value = "unknown"
+	}
+	values, ok := b.counts[dim]
+	if !ok {
+		values = make(map[string]int64)
+		b.counts[dim] = values
+	}
+	values[value]++
+}
+
+// prune drops buckets older than the retention
+func (lvl *level) prune(now time.Time) {
+	if lvl.retention == 0 {
+		return
+	}
+	cutoff := now.Add(-lvl.retention).Truncate(lvl.size).Unix()
+	for key := range lvl.buckets {
+		if key < cutoff {
+			delete(lvl.buckets, key)
+		}
+	}
+}
+
+// Query selects a window of rollups
+type Query struct {
+	Granularity string
+	Start       time.Time // rounded down to the granularity
+	End         time.Time // exclusive
+	GroupBy     []string  // dimensions broken down per bucket
+	Top         int       // number of top actors
+}
+
+// Point is one time bucket of a result series
+type Point struct {
+	Start  time.Time                   `json:"start"`
+	Total  int64                       `json:"total"`
+	Counts map[string]map[string]int64 `json:"counts,omitempty"`
+}
+
+// ActorCount is one entry of the top actors list
+type ActorCount struct {
+	ActorID string `json:"actor_id"`
+	Count   int64  `json:"count"`
+}
+
+// Result is the answer to a Query
+type Result struct {
+	Granularity string                      `json:"granularity"`
+	Start       time.Time                   `json:"start"`
+	End         time.Time                   `json:"end"`
+	Total       int64                       `json:"total"`
+	Totals      map[string]map[string]int64 `json:"totals"`
+	TopActors   []ActorCount                `json:"top_actors"`
+	Series      []Point                     `json:"series"`
+}
+
+// Window returns the bucket-aligned window of a query, or ErrInvalidWindow
+// if the rollups cannot answer it
+func (r *Rollups) Window(q Query) (time.Time, time.Time, error) {
+	lvl, ok := r.levels[q.Granularity]
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown granularity %q", ErrInvalidWindow, q.Granularity)
+	}
+
+	start := q.Start.UTC().Truncate(lvl.size)
+	end := q.End.UTC()
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("%w: start must be before end", ErrInvalidWindow)
+	}
+	if n := end.Sub(start) / lvl.size; n > MaxBuckets {
+		return start, end, fmt.Errorf("%w: window spans %d %s buckets (max %d)", ErrInvalidWindow, n, q.Granularity, MaxBuckets)
+	}
+	if lvl.retention > 0 && start.Before(time.Now().Add(-lvl.retention).Truncate(lvl.size)) {
+		return start, end, fmt.Errorf("%w: %s rollups are kept for %s", ErrInvalidWindow, q.Granularity, lvl.retention)
+	}
+	if start.Before(time.Now().Add(-r.coverage).Truncate(lvl.size)) {
+		return start, end, fmt.Errorf("%w: rollups cover the last %s", ErrInvalidWindow, r.coverage)
+	}
+	return start, end, nil
+}
+
+// Query sums the buckets of a window
+func (r *Rollups) Query(q Query) (*Result, error) {
+	start, end, err := r.Window(q)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lvl := r.levels[q.Granularity]
+
+	result := &Result{
+		Granularity: q.Granularity,
+		Start:       start,
+		End:         end,
+		Totals:      make(map[string]map[string]int64),
+		Series:      []Point{},
+	}
+	actors := make(map[string]int64)
+
+	for t := start; t.Before(end); t = t.Add(lvl.size) {
+		point := Point{Start: t}
+
+		if b, ok := lvl.buckets[t.Unix()]; ok {
+			point.Total = b.total
+			result.Total += b.total
+
+			for dim, values := range b.counts {
+				for value, count := range values {
+					if dim == DimActor {
+						actors[value] += count
+						continue
+					}
+					addCount(result.Totals, dim, value, count)
+				}
+			}
+
+			for _, dim := range q.GroupBy {
+				for value, count := range b.counts[dim] {
+					if point.Counts == nil {
+						point.Counts = make(map[string]map[string]int64)
+					}
+					addCount(point.Counts, dim, value, count)
+				}
+			}
+		}
+
+		result.Series = append(result.Series, point)
+	}
+
+	result.TopActors = topActors(actors, q.Top)
+	return result, nil
+}
+
+// addCount adds to one dimension value of a count map
+func addCount(counts map[string]map[string]int64, dim, value string, count int64) {
+	values, ok := counts[dim]
+	if !ok {
+		values = make(map[string]int64)
+		counts[dim] = values
+	}
+	values[value] += count
+}
+
+// topActors returns the n actors with the most fractures
+func topActors(actors map[string]int64, n int) []ActorCount {
+	top := make([]ActorCount, 0, len(actors))
+	for actor, count := range actors {
+		top = append(top, ActorCount{ActorID: actor, Count: count})
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].ActorID < top[j].ActorID
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/internal/index"
+)
+
+func entry(ts time.Time, actorID string) index.Entry {
+	return index.Entry{Timestamp: ts, ActorID: actorID, EventType: "payment", FailedChecks: []string{"balance"}}
+}
+
+func TestRollupsCountWithinCoverage(t *testing.T) {
+	now := time.Now().UTC()
+	r := New(3 * 24 * time.Hour)
+
+	r.Add([]index.Entry{
+		entry(now.Add(-time.Minute), "actor-1"),
+		entry(now.Add(-2*time.Minute), "actor-1"),
+		entry(now.Add(-2*time.Minute), "actor-2"),
+		// Older than the coverage: the index drops it and may read it back
+		entry(now.Add(-10*24*time.Hour), "actor-3"),
+	})
+
+	result, err := r.Query(Query{Granularity: Day, Start: now.Add(-r.DefaultWindow(Day)), End: now, Top: 10})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if result.Total != 3 {
+		t.Errorf("total = %d, want 3", result.Total)
+	}
+	if len(result.TopActors) != 2 || result.TopActors[0] != (ActorCount{ActorID: "actor-1", Count: 2}) {
+		t.Errorf("top actors = %+v, want actor-1 first with 2", result.TopActors)
+	}
+	if got := result.Totals[DimFailedCheck]["balance"]; got != 3 {
+		t.Errorf("balance failures = %d, want 3", got)
+	}
+	if len(r.levels[Day].buckets) > 2 || len(r.levels[Hour].buckets) > 2 {
+		t.Errorf("kept %d day and %d hour buckets for fractures in the last few minutes",
+			len(r.levels[Day].buckets), len(r.levels[Hour].buckets))
+	}
+}
+
+func TestRollupsPruneOldBuckets(t *testing.T) {
+	now := time.Now().UTC()
+	r := New(3 * 24 * time.Hour)
+
+	// Counted while in the coverage, then the coverage moves past it
+	r.Add([]index.Entry{entry(now.Add(-48*time.Hour), "actor-1")})
+	for _, lvl := range r.levels {
+		lvl.prune(now.Add(4 * 24 * time.Hour))
+	}
+
+	for name, lvl := range r.levels {
+		if len(lvl.buckets) != 0 {
+			t.Errorf("%s level kept %d buckets past its retention", name, len(lvl.buckets))
+		}
+	}
+}
+
+func TestRollupsRejectWindowsOutsideCoverage(t *testing.T) {
+	now := time.Now().UTC()
+	r := New(3 * 24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		query Query
+	}{
+		{name: "day before the coverage", query: Query{Granularity: Day, Start: now.Add(-5 * 24 * time.Hour), End: now}},
+		{name: "minute before two days", query: Query{Granularity: Minute, Start: now.Add(-49 * time.Hour), End: now.Add(-48 * time.Hour)}},
+		{name: "unknown granularity", query: Query{Granularity: "week", Start: now.Add(-time.Hour), End: now}},
+		{name: "empty window", query: Query{Granularity: Hour, Start: now, End: now.Add(-time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Query(tt.query); !errors.Is(err, ErrInvalidWindow) {
+				t.Errorf("Query = %v, want %v", err, ErrInvalidWindow)
+			}
+		})
+	}
+}
+
+func TestRollupsSeriesAndGroupBy(t *testing.T) {
+	now := time.Now().UTC()
+	r := New(3 * 24 * time.Hour)
+
+	thisHour := now.Truncate(time.Hour)
+	lastHour := thisHour.Add(-time.Hour)
+	r.Add([]index.Entry{
+		{Timestamp: lastHour.Add(time.Minute), ActorID: "actor-1", VetoNode: "veto-1", FailedChecks: []string{"balance", "velocity"}},
+		{Timestamp: thisHour, ActorID: "actor-2", VetoNode: "veto-2", FailedChecks: []string{"balance"}},
+		{Timestamp: thisHour, ActorID: "actor-2", FailedChecks: []string{"schema"}},
+	})
+
+	result, err := r.Query(Query{
+		Granularity: Hour,
+		Start:       lastHour.Add(30 * time.Minute), // rounded down to the hour
+		End:         thisHour.Add(time.Hour),
+		GroupBy:     []string{DimVetoNode},
+		Top:         1,
+	})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+
+	if !result.Start.Equal(lastHour) || len(result.Series) != 2 {
+		t.Fatalf("series = %d points from %s, want 2 from %s", len(result.Series), result.Start, lastHour)
+	}
+	if result.Series[0].Total != 1 || result.Series[1].Total != 2 {
+		t.Errorf("series totals = %d, %d, want 1, 2", result.Series[0].Total, result.Series[1].Total)
+	}
+	if got := result.Series[1].Counts[DimVetoNode]; got["veto-2"] != 1 || got["unknown"] != 1 {
+		t.Errorf("veto nodes this hour = %v, want veto-2 and unknown once each", got)
+	}
+	if result.Series[0].Counts[DimFailedCheck] != nil {
+		t.Error("series broken down by a dimension that was not grouped")
+	}
+	if got := result.Totals[DimFailedCheck]; got["balance"] != 2 || got["velocity"] != 1 || got["schema"] != 1 {
+		t.Errorf("failed check totals = %v", got)
+	}
+	if _, ok := result.Totals[DimActor]; ok {
+		t.Error("actors are counted in totals, want them only in top_actors")
+	}
+	if len(result.TopActors) != 1 || result.TopActors[0] != (ActorCount{ActorID: "actor-2", Count: 2}) {
+		t.Errorf("top actors = %+v, want actor-2 with 2", result.TopActors)
+	}
+
+	// Each fracture is counted once at every granularity
+	for name := range r.levels {
+		var total int64
+		for _, b := range r.levels[name].buckets {
+			total += b.total
+		}
+		if total != 3 {
+			t.Errorf("%s buckets count %d fractures, want 3", name, total)
+		}
+	}
+}
+
+func TestRollupsRejectTooManyBuckets(t *testing.T) {
+	now := time.Now().UTC()
+	r := New(3 * 24 * time.Hour)
+
+	_, err := r.Query(Query{Granularity: Minute, Start: now.Add(-(MaxBuckets + 1) * time.Minute), End: now})
+	if !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("Query over %d minute buckets = %v, want %v", MaxBuckets+1, err, ErrInvalidWindow)
+	}
+}
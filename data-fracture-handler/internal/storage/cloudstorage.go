@@ -2,21 +2,36 @@ package storage
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"strconv"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
+const (
+	// maxComposeSources is the GCS limit on sources per compose request
+	maxComposeSources = 32
+	// maxComponentCount is the GCS limit on components of a composite object
+	maxComponentCount = 1024
+)
+
 // CloudStorageWriter handles writing fractured events to Google Cloud Storage
+//
+// GCS objects cannot be appended to, so fractures are written as part objects
+// (see partWriter) and parts of closed hours are composed onto
+// YYYY/MM/DD/fractures-HH.jsonl
 type CloudStorageWriter struct {
 	client     *storage.Client
 	bucketName string
+	parts      *partWriter
 }
 
 // NewCloudStorageWriter creates a new Cloud Storage writer
@@ -26,35 +41,41 @@ func NewCloudStorageWriter(ctx context.Context, bucketName string) (*CloudStorag
 		return nil, fmt.Errorf("failed to create storage client: %w", err)
 	}
 
-	return &CloudStorageWriter{
+	w := &CloudStorageWriter{
 		client:     client,
 		bucketName: bucketName,
-	}, nil
+	}
+	w.parts = newPartWriter("CloudStorage", w.writePart, w.Compact)
+
+	return w, nil
 }
 
 // WriteFracture writes a fractured event to Cloud Storage
 // Organizes by date: gs://bucket/YYYY/MM/DD/fractures-HH.jsonl
+// It returns once the fracture is durable in a part object
 func (w *CloudStorageWriter) WriteFracture(ctx context.Context, fracture *models.FracturedEvent) error {
-	// Generate object path based on timestamp
-	objectPath := w.generateObjectPath(fracture.Timestamp)
-
-	// Get bucket and object
-	bucket := w.client.Bucket(w.bucketName)
-	obj := bucket.Object(objectPath)
+	return w.parts.write(ctx, []*models.FracturedEvent{fracture})
+}
 
-	// Open writer in append mode (for JSONL format)
-	writer := obj.NewWriter(ctx)
-	writer.ContentType = "application/x-ndjson" // Newline-delimited JSON
+// WriteFractureBatch writes multiple fractured events efficiently
+func (w *CloudStorageWriter) WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error {
+	return w.parts.write(ctx, fractures)
+}
 
-	// Serialize fracture to JSON
-	jsonData, err := json.Marshal(fracture)
+// writePart writes fractures to a new, uniquely named part object
+func (w *CloudStorageWriter) writePart(ctx context.Context, partPath string, fractures []*models.FracturedEvent) error {
+	data, err := encodeFractures(fractures)
 	if err != nil {
-		return fmt.Errorf("failed to marshal fracture: %w", err)
+		return err
 	}
 
-	// Write JSON line (with newline for JSONL format)
-	jsonLine := append(jsonData, '\n')
-	if _, err := writer.Write(jsonLine); err != nil {
+	// DoesNotExist guarantees a part is never replaced
+	obj := w.client.Bucket(w.bucketName).Object(partPath).If(storage.Conditions{DoesNotExist: true})
+
+	writer := obj.NewWriter(ctx)
+	writer.ContentType = "application/x-ndjson" // Newline-delimited JSON
+
+	if _, err := writer.Write(data); err != nil {
 		writer.Close()
 		return fmt.Errorf("failed to write to Cloud Storage: %w", err)
 	}
@@ -64,74 +85,206 @@ func (w *CloudStorageWriter) WriteFracture(ctx context.Context, fracture *models
 		return fmt.Errorf("failed to close Cloud Storage writer: %w", err)
 	}
 
-	log.Printf("[CloudStorage] Fracture %s written to gs://%s/%s", 
-		fracture.FractureID, w.bucketName, objectPath)
+	log.Printf("[CloudStorage] %d fractures written to gs://%s/%s",
+		len(fractures), w.bucketName, partPath)
 
 	return nil
 }
 
-// WriteFractureBatch writes multiple fractured events efficiently
-func (w *CloudStorageWriter) WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error {
-	if len(fractures) == 0 {
-		return nil
+// Compact composes the part objects of one hour onto its hourly object
+// Parts are deleted only after the compose succeeds; a crash in between leaves
+// duplicates, which ReadFractures drops by fracture ID
+func (w *CloudStorageWriter) Compact(ctx context.Context, hour time.Time) error {
+	objectPath := generateObjectPath(hour)
+	bucket := w.client.Bucket(w.bucketName)
+
+	parts, err := w.listObjects(ctx, partPrefix(objectPath))
+	if err != nil {
+		return err
 	}
 
-	// Group by hour for efficient batching
-	batches := w.groupByHour(fractures)
+	for len(parts) > 0 {
+		dst := bucket.Object(objectPath)
+
+		var sources []*storage.ObjectHandle
+		conds := storage.Conditions{DoesNotExist: true}
+
+		attrs, err := dst.Attrs(ctx)
+		switch {
+		case err == nil:
+			// Every compose adds its parts as components; rewriting the object
+			// onto itself makes it a single component again
+			if attrs.ComponentCount+maxComposeSources > maxComponentCount {
+				if attrs, err = w.rewrite(ctx, dst, attrs.Generation); err != nil {
+					return err
+				}
+			}
+			// Guard against another instance compacting the same hour
+			sources = append(sources, dst)
+			conds = storage.Conditions{GenerationMatch: attrs.Generation}
+		case !errors.Is(err, storage.ErrObjectNotExist):
+			return fmt.Errorf("failed to stat %s: %w", objectPath, err)
+		}
+
+		n := min(len(parts), maxComposeSources-len(sources))
+		batch := parts[:n]
+		parts = parts[n:]
+
+		for _, part := range batch {
+			sources = append(sources, bucket.Object(part))
+		}
+
+		composer := dst.If(conds).ComposerFrom(sources...)
+		composer.ContentType = "application/x-ndjson"
+		if _, err := composer.Run(ctx); err != nil {
+			return fmt.Errorf("failed to compose %s: %w", objectPath, err)
+		}
 
-	for objectPath, batch := range batches {
-		if err := w.writeBatch(ctx, objectPath, batch); err != nil {
-			return err
+		for _, part := range batch {
+			if err := bucket.Object(part).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				log.Printf("[CloudStorage] Warning: failed to delete compacted part %s: %v", part, err)
+			}
 		}
+
+		log.Printf("[CloudStorage] Compacted %d parts into gs://%s/%s", len(batch), w.bucketName, objectPath)
 	}
 
 	return nil
 }
 
-// writeBatch writes a batch of fractures to a single file
-func (w *CloudStorageWriter) writeBatch(ctx context.Context, objectPath string, fractures []*models.FracturedEvent) error {
-	bucket := w.client.Bucket(w.bucketName)
-	obj := bucket.Object(objectPath)
+// rewrite copies a composite object onto itself, which resets its component count
+func (w *CloudStorageWriter) rewrite(ctx context.Context, obj *storage.ObjectHandle, generation int64) (*storage.ObjectAttrs, error) {
+	copier := obj.If(storage.Conditions{GenerationMatch: generation}).CopierFrom(obj)
+	copier.ContentType = "application/x-ndjson"
 
-	writer := obj.NewWriter(ctx)
-	writer.ContentType = "application/x-ndjson"
+	attrs, err := copier.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite %s: %w", obj.ObjectName(), err)
+	}
+
+	log.Printf("[CloudStorage] Rewrote gs://%s/%s to reset its component count", w.bucketName, obj.ObjectName())
+	return attrs, nil
+}
+
+// ReadFractures reads fractured events from Cloud Storage (for queries/debugging)
+// Hourly objects and uncompacted parts are read together
+func (w *CloudStorageWriter) ReadFractures(ctx context.Context, date time.Time) ([]*models.FracturedEvent, error) {
+	return w.readPrefix(ctx, generateDatePrefix(date))
+}
+
+// ReadHour reads the fractures of one hour, including uncompacted parts
+func (w *CloudStorageWriter) ReadHour(ctx context.Context, t time.Time) ([]*models.FracturedEvent, error) {
+	return w.readPrefix(ctx, generateHourPrefix(t))
+}
 
-	for _, fracture := range fractures {
-		jsonData, err := json.Marshal(fracture)
+// readPrefix reads every object under a prefix
+func (w *CloudStorageWriter) readPrefix(ctx context.Context, prefix string) ([]*models.FracturedEvent, error) {
+	names, err := w.listObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	set := newFractureSet()
+	for _, name := range names {
+		// Read object
+		fractures, err := w.readObject(ctx, name)
 		if err != nil {
-			writer.Close()
-			return fmt.Errorf("failed to marshal fracture: %w", err)
+			log.Printf("[CloudStorage] Warning: failed to read %s: %v", name, err)
+			continue
 		}
+		set.add(fractures)
+	}
 
-		jsonLine := append(jsonData, '\n')
-		if _, err := writer.Write(jsonLine); err != nil {
-			writer.Close()
-			return fmt.Errorf("failed to write to Cloud Storage: %w", err)
-		}
+	return set.sorted(), nil
+}
+
+// ListDay lists the hourly objects and parts of a day with their generations
+func (w *CloudStorageWriter) ListDay(ctx context.Context, date time.Time) ([]ObjectInfo, error) {
+	return w.listObjectInfo(ctx, &storage.Query{Prefix: generateDatePrefix(date)})
+}
+
+// ReadObject reads one object returned by ListDay
+func (w *CloudStorageWriter) ReadObject(ctx context.Context, path string) ([]*models.FracturedEvent, error) {
+	return w.readObject(ctx, path)
+}
+
+// CreateRecord writes a record with a DoesNotExist precondition, so it never
+// replaces one
+func (w *CloudStorageWriter) CreateRecord(ctx context.Context, path string, data []byte) error {
+	obj := w.client.Bucket(w.bucketName).Object(path).If(storage.Conditions{DoesNotExist: true})
+
+	writer := obj.NewWriter(ctx)
+	writer.ContentType = "application/json"
+
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to close Cloud Storage writer: %w", err)
+	err := writer.Close()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return ErrRecordExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
+	return nil
+}
 
-	log.Printf("[CloudStorage] Batch of %d fractures written to gs://%s/%s", 
-		len(fractures), w.bucketName, objectPath)
+// ReadRecord reads one record
+func (w *CloudStorageWriter) ReadRecord(ctx context.Context, path string) ([]byte, error) {
+	reader, err := w.client.Bucket(w.bucketName).Object(path).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer reader.Close()
 
-	return nil
+	return io.ReadAll(reader)
 }
 
-// ReadFractures reads fractured events from Cloud Storage (for queries/debugging)
-func (w *CloudStorageWriter) ReadFractures(ctx context.Context, date time.Time) ([]*models.FracturedEvent, error) {
-	// List all objects for the given date
-	prefix := w.generateDatePrefix(date)
-	
-	bucket := w.client.Bucket(w.bucketName)
+// ListRecords lists the records under a prefix after startAfter
+func (w *CloudStorageWriter) ListRecords(ctx context.Context, prefix, startAfter string) ([]string, error) {
 	query := &storage.Query{Prefix: prefix}
-	
-	it := bucket.Objects(ctx, query)
-	
-	var allFractures []*models.FracturedEvent
-	
+	if startAfter != "" {
+		// StartOffset is inclusive
+		query.StartOffset = startAfter + "\x00"
+	}
+
+	objects, err := w.listObjectInfo(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(objects))
+	for i, object := range objects {
+		paths[i] = object.Path
+	}
+	return paths, nil
+}
+
+// listObjects returns the names of all objects under a prefix
+func (w *CloudStorageWriter) listObjects(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := w.listObjectInfo(ctx, &storage.Query{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.Path
+	}
+	return names, nil
+}
+
+// listObjectInfo returns the names and generations of the objects a query matches
+func (w *CloudStorageWriter) listObjectInfo(ctx context.Context, query *storage.Query) ([]ObjectInfo, error) {
+	it := w.client.Bucket(w.bucketName).Objects(ctx, query)
+
+	var objects []ObjectInfo
 	for {
 		attrs, err := it.Next()
 		if err == iterator.Done {
@@ -140,18 +293,13 @@ func (w *CloudStorageWriter) ReadFractures(ctx context.Context, date time.Time)
 		if err != nil {
 			return nil, fmt.Errorf("failed to iterate objects: %w", err)
 		}
-
-		// Read object
-		fractures, err := w.readObject(ctx, attrs.Name)
-		if err != nil {
-			log.Printf("[CloudStorage] Warning: failed to read %s: %v", attrs.Name, err)
-			continue
-		}
-
-		allFractures = append(allFractures, fractures...)
+		objects = append(objects, ObjectInfo{
+			Path:    attrs.Name,
+			Version: strconv.FormatInt(attrs.Generation, 10),
+		})
 	}
 
-	return allFractures, nil
+	return objects, nil
 }
 
 // readObject reads a single JSONL file and parses fractures
@@ -165,55 +313,11 @@ func (w *CloudStorageWriter) readObject(ctx context.Context, objectPath string)
 	}
 	defer reader.Close()
 
-	var fractures []*models.FracturedEvent
-	decoder := json.NewDecoder(reader)
-
-	for {
-		var fracture models.FracturedEvent
-		if err := decoder.Decode(&fracture); err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, fmt.Errorf("failed to decode fracture: %w", err)
-		}
-		fractures = append(fractures, &fracture)
-	}
-
-	return fractures, nil
-}
-
-// generateObjectPath creates the GCS path for a fracture
-// Format: YYYY/MM/DD/fractures-HH.jsonl
-func (w *CloudStorageWriter) generateObjectPath(timestamp time.Time) string {
-	return fmt.Sprintf("%04d/%02d/%02d/fractures-%02d.jsonl",
-		timestamp.Year(),
-		timestamp.Month(),
-		timestamp.Day(),
-		timestamp.Hour(),
-	)
-}
-
-// generateDatePrefix creates the prefix for listing objects by date
-func (w *CloudStorageWriter) generateDatePrefix(date time.Time) string {
-	return fmt.Sprintf("%04d/%02d/%02d/",
-		date.Year(),
-		date.Month(),
-		date.Day(),
-	)
-}
-
-// groupByHour groups fractures by hour for efficient batching
-func (w *CloudStorageWriter) groupByHour(fractures []*models.FracturedEvent) map[string][]*models.FracturedEvent {
-	batches := make(map[string][]*models.FracturedEvent)
-
-	for _, fracture := range fractures {
-		path := w.generateObjectPath(fracture.Timestamp)
-		batches[path] = append(batches[path], fracture)
-	}
-
-	return batches
+	return decodeFractures(reader)
 }
 
-// Close closes the Cloud Storage client
+// Close flushes pending fractures and closes the Cloud Storage client
 func (w *CloudStorageWriter) Close() error {
+	w.parts.close()
 	return w.client.Close()
 }
@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// LocalStore writes fractured events to a local directory
+// It uses the same YYYY/MM/DD/fractures-HH.jsonl layout as the object stores,
+// but files support appends, so there are no parts to compact
+type LocalStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewLocalStore creates a fracture store rooted at dir
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fracture directory: %w", err)
+	}
+
+	log.Printf("[LocalStore] Writing fractures under %s", dir)
+
+	return &LocalStore{dir: dir}, nil
+}
+
+// WriteFracture appends a fractured event to its hourly file
+func (s *LocalStore) WriteFracture(ctx context.Context, fracture *models.FracturedEvent) error {
+	return s.WriteFractureBatch(ctx, []*models.FracturedEvent{fracture})
+}
+
+// WriteFractureBatch appends fractured events to their hourly files
+func (s *LocalStore) WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for objectPath, batch := range groupByHour(fractures) {
+		if err := s.appendFile(objectPath, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendFile appends fractures to one hourly file and syncs it
+func (s *LocalStore) appendFile(objectPath string, fractures []*models.FracturedEvent) error {
+	data, err := encodeFractures(fractures)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(objectPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+
+	log.Printf("[LocalStore] %d fractures written to %s", len(fractures), path)
+
+	return nil
+}
+
+// ReadFractures reads every fracture stored for a day
+func (s *LocalStore) ReadFractures(ctx context.Context, date time.Time) ([]*models.FracturedEvent, error) {
+	dayDir := filepath.Join(s.dir, filepath.FromSlash(generateDatePrefix(date)))
+
+	paths, err := filepath.Glob(filepath.Join(dayDir, "fractures-*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dayDir, err)
+	}
+	sort.Strings(paths)
+
+	set := newFractureSet()
+	for _, path := range paths {
+		fractures, err := readFile(path)
+		if err != nil {
+			log.Printf("[LocalStore] Warning: failed to read %s: %v", path, err)
+			continue
+		}
+		set.add(fractures)
+	}
+
+	return set.sorted(), nil
+}
+
+// ReadHour reads the fractures of one hour
+func (s *LocalStore) ReadHour(ctx context.Context, t time.Time) ([]*models.FracturedEvent, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(generateObjectPath(t)))
+
+	fractures, err := readFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	set := newFractureSet()
+	set.add(fractures)
+	return set.sorted(), nil
+}
+
+// ListDay lists the hourly files of a day; a file's version is its size and
+// modification time, which change on every append
+func (s *LocalStore) ListDay(ctx context.Context, date time.Time) ([]ObjectInfo, error) {
+	dayDir := filepath.Join(s.dir, filepath.FromSlash(generateDatePrefix(date)))
+
+	paths, err := filepath.Glob(filepath.Join(dayDir, "fractures-*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dayDir, err)
+	}
+	sort.Strings(paths)
+
+	objects := make([]ObjectInfo, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, ObjectInfo{
+			Path:    filepath.ToSlash(rel),
+			Version: fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano()),
+		})
+	}
+
+	return objects, nil
+}
+
+// ReadObject reads one hourly file returned by ListDay
+func (s *LocalStore) ReadObject(ctx context.Context, path string) ([]*models.FracturedEvent, error) {
+	fractures, err := readFile(filepath.Join(s.dir, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return fractures, nil
+}
+
+// CreateRecord writes a record to a temporary file and links it into place;
+// the link fails if the record exists, so a record is never replaced
+func (s *LocalStore) CreateRecord(ctx context.Context, path string, data []byte) error {
+	target := filepath.Join(s.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".record-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	tmp.Close()
+
+	if err := os.Link(tmp.Name(), target); err != nil {
+		if os.IsExist(err) {
+			return ErrRecordExists
+		}
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadRecord reads one record
+func (s *LocalStore) ReadRecord(ctx context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(path)))
+	if os.IsNotExist(err) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListRecords lists the records under a prefix after startAfter
+func (s *LocalStore) ListRecords(ctx context.Context, prefix, startAfter string) ([]string, error) {
+	// Walk the deepest directory the prefix names, then match the rest
+	root := filepath.Join(s.dir, filepath.FromSlash(prefix[:strings.LastIndex(prefix, "/")+1]))
+
+	var paths []string
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".record-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, file)
+		if err != nil {
+			return err
+		}
+		path := filepath.ToSlash(rel)
+		if strings.HasPrefix(path, prefix) && path > startAfter {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readFile parses one JSONL file
+func readFile(path string) ([]*models.FracturedEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return decodeFractures(file)
+}
+
+// Close is a no-op; every write is synced before it returns
+func (s *LocalStore) Close() error {
+	return nil
+}
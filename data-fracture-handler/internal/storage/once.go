@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// storedPrefix holds one marker record per stored fracture
+const storedPrefix = "fractures/stored/"
+
+// storedMarker records where a fracture was stored
+type storedMarker struct {
+	FractureID uuid.UUID `json:"fracture_id"`
+	EventID    uuid.UUID `json:"event_id"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// WriteNew writes the fractures that are not stored yet and returns them
+// Fracture IDs are derived from the event and veto node, so a request the
+// Boundary Adapter redelivers carries the ID of the fracture it already
+// created. A marker record is created once a fracture is durable, and
+// fractures with a marker are skipped. A crash between the write and the
+// marker leaves a duplicate, which readers drop by ID
+func WriteNew(ctx context.Context, store FractureStore, fractures []*models.FracturedEvent) ([]*models.FracturedEvent, error) {
+	fresh := make([]*models.FracturedEvent, 0, len(fractures))
+	seen := make(map[uuid.UUID]bool, len(fractures))
+	for _, fracture := range fractures {
+		if seen[fracture.FractureID] {
+			continue
+		}
+		seen[fracture.FractureID] = true
+
+		_, err := store.ReadRecord(ctx, storedPath(fracture.FractureID))
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check fracture %s: %w", fracture.FractureID, err)
+		}
+		fresh = append(fresh, fracture)
+	}
+
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+	if err := store.WriteFractureBatch(ctx, fresh); err != nil {
+		return nil, err
+	}
+
+	for _, fracture := range fresh {
+		data, err := json.Marshal(storedMarker{
+			FractureID: fracture.FractureID,
+			EventID:    fracture.Event.ID,
+			Timestamp:  fracture.Timestamp,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal fracture marker: %w", err)
+		}
+		err = store.CreateRecord(ctx, storedPath(fracture.FractureID), data)
+		if err != nil && !errors.Is(err, ErrRecordExists) {
+			return nil, fmt.Errorf("failed to mark fracture %s stored: %w", fracture.FractureID, err)
+		}
+	}
+
+	return fresh, nil
+}
+
+// storedPath is the marker record of a fracture
+func storedPath(fractureID uuid.UUID) string {
+	return storedPrefix + fractureID.String() + ".json"
+}
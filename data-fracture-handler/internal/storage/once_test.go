@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// fractureFor builds the fracture the handler would for a veto of eventID
+func fractureFor(eventID uuid.UUID, vetoNode string) *models.FracturedEvent {
+	req := models.FractureRequest{
+		Event:        models.Event{ID: eventID},
+		FailedChecks: []string{"balance"},
+		VetoNode:     vetoNode,
+	}
+	return req.ToFracturedEvent()
+}
+
+func TestWriteNewSkipsRedeliveredFractures(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	eventID := uuid.New()
+	first := fractureFor(eventID, "veto-1")
+	written, err := WriteNew(ctx, store, []*models.FracturedEvent{first})
+	if err != nil {
+		t.Fatalf("WriteNew: %v", err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("first delivery wrote %d fractures, want 1", len(written))
+	}
+
+	// A redelivery of the same request, alongside a veto from another node
+	// and a duplicate within the batch
+	redelivered := fractureFor(eventID, "veto-1")
+	other := fractureFor(eventID, "veto-2")
+	written, err = WriteNew(ctx, store, []*models.FracturedEvent{redelivered, other, fractureFor(eventID, "veto-2")})
+	if err != nil {
+		t.Fatalf("WriteNew: %v", err)
+	}
+	if len(written) != 1 || written[0].FractureID != other.FractureID {
+		t.Errorf("redelivery wrote %d fractures, want only the veto-2 fracture", len(written))
+	}
+
+	stored, err := store.ReadFractures(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ReadFractures: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("stored %d fractures, want 2", len(stored))
+	}
+}
+
+// failingStore fails every fracture write
+type failingStore struct {
+	*LocalStore
+}
+
+func (s failingStore) WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error {
+	return errors.New("bucket unavailable")
+}
+
+func TestWriteNewMarksOnlyStoredFractures(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	fracture := fractureFor(uuid.New(), "veto-1")
+	if _, err := WriteNew(ctx, failingStore{local}, []*models.FracturedEvent{fracture}); err == nil {
+		t.Fatal("WriteNew succeeded with a failing store")
+	}
+
+	// The failed write left no marker, so the retry stores the fracture
+	written, err := WriteNew(ctx, local, []*models.FracturedEvent{fracture})
+	if err != nil {
+		t.Fatalf("WriteNew: %v", err)
+	}
+	if len(written) != 1 {
+		t.Errorf("retry wrote %d fractures, want 1", len(written))
+	}
+}
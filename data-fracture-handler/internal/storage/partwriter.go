@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+const (
+	// flushInterval is how long a fracture may wait to be grouped into a part
+	flushInterval = 500 * time.Millisecond
+	// maxPartRecords flushes a part early once this many fractures are pending
+	maxPartRecords = 500
+	// compactInterval is how often closed hours are compacted
+	compactInterval = 15 * time.Minute
+)
+
+// partWriter implements append-only writes for object stores that can only
+// replace whole objects
+//
+// Writes are grouped into uniquely named part objects under
+// YYYY/MM/DD/fractures-HH/, and once the hour has closed the backend's compact
+// function folds the parts into YYYY/MM/DD/fractures-HH.jsonl. Readers list
+// the day prefix, which covers both
+type partWriter struct {
+	name      string
+	writePart func(ctx context.Context, partPath string, fractures []*models.FracturedEvent) error
+	compact   func(ctx context.Context, hour time.Time) error
+
+	mu      sync.Mutex
+	pending map[string][]*pendingWrite // keyed by hourly object path
+	flushCh chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// pendingWrite is a group of fractures waiting for their part to be written
+type pendingWrite struct {
+	fractures []*models.FracturedEvent
+	result    chan error
+}
+
+// newPartWriter starts the flush and compaction loops for a backend
+func newPartWriter(
+	name string,
+	writePart func(ctx context.Context, partPath string, fractures []*models.FracturedEvent) error,
+	compact func(ctx context.Context, hour time.Time) error,
+) *partWriter {
+	pw := &partWriter{
+		name:      name,
+		writePart: writePart,
+		compact:   compact,
+		pending:   make(map[string][]*pendingWrite),
+		flushCh:   make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	pw.wg.Add(2)
+	go pw.flushLoop()
+	go pw.compactLoop()
+
+	return pw
+}
+
+// write adds fractures to the next part for their hour and waits until it is stored
+func (pw *partWriter) write(ctx context.Context, fractures []*models.FracturedEvent) error {
+	if len(fractures) == 0 {
+		return nil
+	}
+
+	// Group by hour for efficient batching
+	batches := groupByHour(fractures)
+
+	results := make([]chan error, 0, len(batches))
+	for objectPath, batch := range batches {
+		results = append(results, pw.enqueue(objectPath, batch))
+	}
+
+	for _, result := range results {
+		select {
+		case err := <-result:
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// enqueue adds fractures to the next part for an hour
+func (pw *partWriter) enqueue(objectPath string, fractures []*models.FracturedEvent) chan error {
+	w := &pendingWrite{
+		fractures: fractures,
+		result:    make(chan error, 1),
+	}
+
+	pw.mu.Lock()
+	pw.pending[objectPath] = append(pw.pending[objectPath], w)
+	full := countPending(pw.pending[objectPath]) >= maxPartRecords
+	pw.mu.Unlock()
+
+	if full {
+		select {
+		case pw.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return w.result
+}
+
+// flushLoop writes pending fractures as part objects
+func (pw *partWriter) flushLoop() {
+	defer pw.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-pw.flushCh:
+		case <-pw.done:
+			pw.flush()
+			return
+		}
+		pw.flush()
+	}
+}
+
+// flush writes one part object per hour with pending fractures
+func (pw *partWriter) flush() {
+	pw.mu.Lock()
+	pending := pw.pending
+	pw.pending = make(map[string][]*pendingWrite)
+	pw.mu.Unlock()
+
+	for objectPath, writes := range pending {
+		var fractures []*models.FracturedEvent
+		for _, w := range writes {
+			fractures = append(fractures, w.fractures...)
+		}
+
+		// Parts outlive the request that produced them, so they are not
+		// tied to any caller's context
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := pw.writePart(ctx, generatePartPath(objectPath), fractures)
+		cancel()
+
+		for _, w := range writes {
+			w.result <- err
+		}
+	}
+}
+
+// compactLoop periodically compacts the parts of closed hours
+func (pw *partWriter) compactLoop() {
+	defer pw.wg.Done()
+
+	ticker := time.NewTicker(compactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), compactInterval)
+			pw.compactClosedHours(ctx, time.Now().UTC())
+			cancel()
+		case <-pw.done:
+			return
+		}
+	}
+}
+
+// compactClosedHours compacts every hour of the last two days that has ended
+func (pw *partWriter) compactClosedHours(ctx context.Context, now time.Time) {
+	currentHour := now.Truncate(time.Hour)
+
+	for hour := currentHour.Add(-48 * time.Hour); hour.Before(currentHour); hour = hour.Add(time.Hour) {
+		if err := pw.compact(ctx, hour); err != nil {
+			log.Printf("[%s] Warning: failed to compact %s: %v", pw.name, generateObjectPath(hour), err)
+		}
+	}
+}
+
+// close flushes pending fractures and stops the background loops
+func (pw *partWriter) close() {
+	close(pw.done)
+	pw.wg.Wait()
+}
+
+// countPending returns the number of fractures in a set of pending writes
+func countPending(writes []*pendingWrite) int {
+	n := 0
+	for _, w := range writes {
+		n += len(w.fractures)
+	}
+	return n
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// S3Config configures the S3-compatible backend
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Store writes fractured events to an S3-compatible bucket such as MinIO
+//
+// Like GCS, S3 objects cannot be appended to, so fractures are written as
+// part objects (see partWriter). Closed hours are compacted by rewriting the
+// hourly object with a conditional PUT, then deleting the folded-in parts
+type S3Store struct {
+	client *s3Client
+	parts  *partWriter
+}
+
+// NewS3Store creates an S3-compatible fracture store
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	client, err := newS3Client(cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &S3Store{client: client}
+	s.parts = newPartWriter("S3Store", s.writePart, s.Compact)
+
+	log.Printf("[S3Store] Writing fractures to %s/%s", cfg.Endpoint, cfg.Bucket)
+
+	return s, nil
+}
+
+// WriteFracture writes a fractured event and returns once its part is stored
+func (s *S3Store) WriteFracture(ctx context.Context, fracture *models.FracturedEvent) error {
+	return s.parts.write(ctx, []*models.FracturedEvent{fracture})
+}
+
+// WriteFractureBatch writes multiple fractured events efficiently
+func (s *S3Store) WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error {
+	return s.parts.write(ctx, fractures)
+}
+
+// writePart writes fractures to a new, uniquely named part object
+func (s *S3Store) writePart(ctx context.Context, partPath string, fractures []*models.FracturedEvent) error {
+	data, err := encodeFractures(fractures)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.put(ctx, partPath, "application/x-ndjson", data, true, ""); err != nil {
+		return fmt.Errorf("failed to write %s: %w", partPath, err)
+	}
+
+	log.Printf("[S3Store] %d fractures written to %s", len(fractures), partPath)
+
+	return nil
+}
+
+// Compact folds the part objects of one hour into its hourly object
+// The rewrite is conditional on the hourly object's ETag, so a concurrent
+// compaction fails instead of dropping records. Parts are deleted only after
+// the rewrite; a crash in between leaves duplicates that readers drop
+func (s *S3Store) Compact(ctx context.Context, hour time.Time) error {
+	objectPath := generateObjectPath(hour)
+
+	parts, err := s.client.list(ctx, partPrefix(objectPath))
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	existing, etag, err := s.client.get(ctx, objectPath)
+	if err != nil && !errors.Is(err, errNoSuchKey) {
+		return fmt.Errorf("failed to read %s: %w", objectPath, err)
+	}
+
+	merged := bytes.NewBuffer(existing)
+	for _, part := range parts {
+		data, _, err := s.client.get(ctx, part)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", part, err)
+		}
+		merged.Write(data)
+	}
+
+	if err := s.client.put(ctx, objectPath, "application/x-ndjson", merged.Bytes(), etag == "", etag); err != nil {
+		return fmt.Errorf("failed to write %s: %w", objectPath, err)
+	}
+
+	for _, part := range parts {
+		if err := s.client.delete(ctx, part); err != nil {
+			log.Printf("[S3Store] Warning: failed to delete compacted part %s: %v", part, err)
+		}
+	}
+
+	log.Printf("[S3Store] Compacted %d parts into %s", len(parts), objectPath)
+
+	return nil
+}
+
+// ReadFractures reads every fracture stored for a day, including uncompacted parts
+func (s *S3Store) ReadFractures(ctx context.Context, date time.Time) ([]*models.FracturedEvent, error) {
+	return s.readPrefix(ctx, generateDatePrefix(date))
+}
+
+// ReadHour reads the fractures of one hour, including uncompacted parts
+func (s *S3Store) ReadHour(ctx context.Context, t time.Time) ([]*models.FracturedEvent, error) {
+	return s.readPrefix(ctx, generateHourPrefix(t))
+}
+
+// ListDay lists the hourly objects and parts of a day with their ETags
+func (s *S3Store) ListDay(ctx context.Context, date time.Time) ([]ObjectInfo, error) {
+	return s.client.listObjects(ctx, generateDatePrefix(date), "")
+}
+
+// ReadObject reads one object returned by ListDay
+func (s *S3Store) ReadObject(ctx context.Context, path string) ([]*models.FracturedEvent, error) {
+	data, _, err := s.client.get(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return decodeFractures(bytes.NewReader(data))
+}
+
+// CreateRecord writes a record with If-None-Match, so it never replaces one
+func (s *S3Store) CreateRecord(ctx context.Context, path string, data []byte) error {
+	err := s.client.put(ctx, path, "application/json", data, true, "")
+	if errors.Is(err, errPreconditionFailed) {
+		return ErrRecordExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadRecord reads one record
+func (s *S3Store) ReadRecord(ctx context.Context, path string) ([]byte, error) {
+	data, _, err := s.client.get(ctx, path)
+	if errors.Is(err, errNoSuchKey) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListRecords lists the records under a prefix after startAfter
+func (s *S3Store) ListRecords(ctx context.Context, prefix, startAfter string) ([]string, error) {
+	objects, err := s.client.listObjects(ctx, prefix, startAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(objects))
+	for i, object := range objects {
+		paths[i] = object.Path
+	}
+	return paths, nil
+}
+
+// readPrefix reads every object under a prefix
+func (s *S3Store) readPrefix(ctx context.Context, prefix string) ([]*models.FracturedEvent, error) {
+	keys, err := s.client.list(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	set := newFractureSet()
+	for _, key := range keys {
+		data, _, err := s.client.get(ctx, key)
+		if err != nil {
+			log.Printf("[S3Store] Warning: failed to read %s: %v", key, err)
+			continue
+		}
+
+		fractures, err := decodeFractures(bytes.NewReader(data))
+		if err != nil {
+			log.Printf("[S3Store] Warning: failed to read %s: %v", key, err)
+			continue
+		}
+		set.add(fractures)
+	}
+
+	return set.sorted(), nil
+}
+
+// Close flushes pending fractures
+func (s *S3Store) Close() error {
+	s.parts.close()
+	return nil
+}
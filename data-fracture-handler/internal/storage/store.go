@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+// FractureStore persists fractured events and reads them back by day
+// Every backend uses the same YYYY/MM/DD/fractures-HH.jsonl layout
+type FractureStore interface {
+	RecordStore
+
+	// WriteFracture returns once the fracture is durably stored
+	WriteFracture(ctx context.Context, fracture *models.FracturedEvent) error
+	WriteFractureBatch(ctx context.Context, fractures []*models.FracturedEvent) error
+	// ReadFractures returns every fracture stored for a UTC day, ordered by timestamp
+	ReadFractures(ctx context.Context, date time.Time) ([]*models.FracturedEvent, error)
+	// ReadHour returns every fracture stored for the UTC hour containing t
+	ReadHour(ctx context.Context, t time.Time) ([]*models.FracturedEvent, error)
+	// ListDay lists the hourly objects and parts holding a UTC day's fractures
+	ListDay(ctx context.Context, date time.Time) ([]ObjectInfo, error)
+	// ReadObject reads the fractures of one object returned by ListDay
+	ReadObject(ctx context.Context, path string) ([]*models.FracturedEvent, error)
+	Close() error
+}
+
+// RecordStore keeps small JSON records in the fracture bucket, beside the
+// fractures but outside the YYYY/ layout, so every instance shares them
+// Records are write-once
+type RecordStore interface {
+	// CreateRecord writes a record unless one already exists at path, in
+	// which case it returns ErrRecordExists
+	CreateRecord(ctx context.Context, path string, data []byte) error
+	// ReadRecord returns ErrRecordNotFound for a missing record
+	ReadRecord(ctx context.Context, path string) ([]byte, error)
+	// ListRecords returns the paths under a prefix that sort after startAfter, in order
+	ListRecords(ctx context.Context, prefix, startAfter string) ([]string, error)
+}
+
+var (
+	// ErrRecordExists is returned by CreateRecord when the record already exists
+	ErrRecordExists = errors.New("record already exists")
+	// ErrRecordNotFound is returned by ReadRecord for a missing record
+	ErrRecordNotFound = errors.New("record not found")
+)
+
+// ObjectInfo identifies one version of a stored object
+type ObjectInfo struct {
+	Path    string
+	Version string // changes whenever the object's content does
+}
+
+// Backend names accepted by FRACTURE_STORAGE
+const (
+	BackendGCS   = "gcs"
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Config selects and configures a fracture storage backend
+type Config struct {
+	Backend    string
+	BucketName string // GCS bucket
+	LocalDir   string
+	S3         S3Config
+}
+
+// New creates the fracture store selected by cfg.Backend
+func New(ctx context.Context, cfg Config) (FractureStore, error) {
+	switch cfg.Backend {
+	case BackendGCS, "":
+		return NewCloudStorageWriter(ctx, cfg.BucketName)
+	case BackendLocal:
+		return NewLocalStore(cfg.LocalDir)
+	case BackendS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown fracture storage backend %q (expected %s, %s or %s)",
+			cfg.Backend, BackendGCS, BackendLocal, BackendS3)
+	}
+}
+
+// generateObjectPath creates the storage path for a fracture
+// Format: YYYY/MM/DD/fractures-HH.jsonl
+func generateObjectPath(timestamp time.Time) string {
+	return fmt.Sprintf("%04d/%02d/%02d/fractures-%02d.jsonl",
+		timestamp.Year(),
+		timestamp.Month(),
+		timestamp.Day(),
+		timestamp.Hour(),
+	)
+}
+
+// generateDatePrefix creates the prefix for listing objects by date
+func generateDatePrefix(date time.Time) string {
+	return fmt.Sprintf("%04d/%02d/%02d/",
+		date.Year(),
+		date.Month(),
+		date.Day(),
+	)
+}
+
+// generateHourPrefix creates the prefix matching an hourly object and its parts
+// Format: YYYY/MM/DD/fractures-HH
+func generateHourPrefix(timestamp time.Time) string {
+	return strings.TrimSuffix(generateObjectPath(timestamp), ".jsonl")
+}
+
+// generatePartPath creates a unique part path for an hourly object
+// Format: YYYY/MM/DD/fractures-HH/part-<unix nanos>-<random>.jsonl
+func generatePartPath(objectPath string) string {
+	return fmt.Sprintf("%spart-%d-%s.jsonl",
+		partPrefix(objectPath),
+		time.Now().UnixNano(),
+		uuid.NewString()[:8],
+	)
+}
+
+// partPrefix is the prefix holding the uncompacted parts of an hourly object
+func partPrefix(objectPath string) string {
+	return strings.TrimSuffix(objectPath, ".jsonl") + "/"
+}
+
+// groupByHour groups fractures by hourly object path for efficient batching
+func groupByHour(fractures []*models.FracturedEvent) map[string][]*models.FracturedEvent {
+	batches := make(map[string][]*models.FracturedEvent)
+
+	for _, fracture := range fractures {
+		path := generateObjectPath(fracture.Timestamp)
+		batches[path] = append(batches[path], fracture)
+	}
+
+	return batches
+}
+
+// encodeFractures serializes fractures as JSON lines
+func encodeFractures(fractures []*models.FracturedEvent) ([]byte, error) {
+	var buf []byte
+	for _, fracture := range fractures {
+		jsonData, err := json.Marshal(fracture)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal fracture: %w", err)
+		}
+		buf = append(buf, jsonData...)
+		buf = append(buf, '\n')
+	}
+	return buf, nil
+}
+
+// decodeFractures parses a JSONL stream of fractures
+func decodeFractures(r io.Reader) ([]*models.FracturedEvent, error) {
+	var fractures []*models.FracturedEvent
+	decoder := json.NewDecoder(r)
+
+	for {
+		var fracture models.FracturedEvent
+		if err := decoder.Decode(&fracture); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode fracture: %w", err)
+		}
+		fractures = append(fractures, &fracture)
+	}
+
+	return fractures, nil
+}
+
+// fractureSet collects fractures from several objects, dropping duplicates
+// left behind by an interrupted compaction
+type fractureSet struct {
+	seen      map[uuid.UUID]bool
+	fractures []*models.FracturedEvent
+}
+
+func newFractureSet() *fractureSet {
+	return &fractureSet{seen: make(map[uuid.UUID]bool)}
+}
+
+// add appends fractures not already in the set
+func (s *fractureSet) add(fractures []*models.FracturedEvent) {
+	for _, fracture := range fractures {
+		if s.seen[fracture.FractureID] {
+			continue
+		}
+		s.seen[fracture.FractureID] = true
+		s.fractures = append(s.fractures, fracture)
+	}
+}
+
+// sorted returns the fractures ordered by timestamp
+func (s *fractureSet) sorted() []*models.FracturedEvent {
+	sort.SliceStable(s.fractures, func(i, j int) bool {
+		return s.fractures[i].Timestamp.Before(s.fractures[j].Timestamp)
+	})
+	return s.fractures
+}
@@ -0,0 +1,162 @@
+// Package triage tracks the review state of fractures
+// Fractures themselves are write-once; their triage state is derived from an
+// append-only history of changes kept in the fracture bucket and shared by
+// every instance
+package triage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/sharedlog"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+const logPrefix = "triage/log/"
+
+var (
+	// ErrInvalidUpdate is returned for a malformed triage update
+	ErrInvalidUpdate = errors.New("invalid triage update")
+	// ErrNoChange is returned for an update that would not change anything
+	ErrNoChange = errors.New("update does not change the fracture's triage state")
+)
+
+// Log holds the triage state and history of every triaged fracture
+type Log struct {
+	changes *sharedlog.Log[models.TriageChange]
+
+	mu      sync.RWMutex
+	states  map[uuid.UUID]*models.Triage
+	history map[uuid.UUID][]models.TriageChange
+}
+
+// Open creates a triage log over the records in store
+func Open(store storage.RecordStore) *Log {
+	return &Log{
+		changes: sharedlog.New[models.TriageChange](store, logPrefix),
+		states:  make(map[uuid.UUID]*models.Triage),
+		history: make(map[uuid.UUID][]models.TriageChange),
+	}
+}
+
+// Sync reads the triage changes made on every instance since the last Sync
+func (l *Log) Sync(ctx context.Context) error {
+	return l.changes.Sync(ctx, func(change models.TriageChange) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.apply(change)
+	})
+}
+
+// Get returns the triage state of a fracture; untriaged fractures are open
+func (l *Log) Get(fractureID uuid.UUID) models.Triage {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.get(fractureID)
+}
+
+// get copies a fracture's state (caller holds mu)
+func (l *Log) get(fractureID uuid.UUID) models.Triage {
+	state, ok := l.states[fractureID]
+	if !ok {
+		return models.Triage{FractureID: fractureID, Status: models.TriageOpen}
+	}
+
+	triage := *state
+	triage.Notes = append([]models.TriageNote(nil), state.Notes...)
+	return triage
+}
+
+// History returns a fracture's triage changes, oldest first
+func (l *Log) History(fractureID uuid.UUID) []models.TriageChange {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]models.TriageChange(nil), l.history[fractureID]...)
+}
+
+// Update applies a triage update and returns the new state
+// The shared log is synced first, so the change is computed against the
+// latest state whichever instance made the previous change
+func (l *Log) Update(ctx context.Context, fractureID uuid.UUID, update models.TriageUpdate) (models.Triage, error) {
+	if update.By == "" {
+		return models.Triage{}, fmt.Errorf("%w: by is required", ErrInvalidUpdate)
+	}
+	if update.Status != "" && !models.ValidTriageStatus(update.Status) {
+		return models.Triage{}, fmt.Errorf("%w: unknown status %q", ErrInvalidUpdate, update.Status)
+	}
+
+	if err := l.Sync(ctx); err != nil {
+		return models.Triage{}, err
+	}
+
+	current := l.Get(fractureID)
+
+	change := models.TriageChange{
+		FractureID: fractureID,
+		At:         time.Now().UTC(),
+		By:         update.By,
+		Note:       update.Note,
+	}
+	if update.Status != "" && update.Status != current.Status {
+		change.PreviousStatus = current.Status
+		change.Status = update.Status
+	}
+	if update.Assignee != nil && *update.Assignee != current.Assignee {
+		change.Assignee = update.Assignee
+	}
+
+	if change.Status == "" && change.Assignee == nil && change.Note == "" {
+		return current, ErrNoChange
+	}
+
+	if err := l.changes.Append(ctx, change); err != nil {
+		return current, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.apply(change)
+
+	log.Printf("[Triage] Fracture %s updated by %s (status: %s)", fractureID, change.By, l.states[fractureID].Status)
+	return l.get(fractureID), nil
+}
+
+// apply adds one change to its fracture's history and refolds the state (caller holds mu)
+// Changes from other instances can arrive out of order, so the history is
+// kept in time order and the state rebuilt from it
+func (l *Log) apply(change models.TriageChange) {
+	history := append(l.history[change.FractureID], change)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].At.Before(history[j].At)
+	})
+	l.history[change.FractureID] = history
+
+	state := &models.Triage{FractureID: change.FractureID, Status: models.TriageOpen}
+	for _, c := range history {
+		if c.Status != "" {
+			state.Status = c.Status
+		}
+		if c.Assignee != nil {
+			state.Assignee = *c.Assignee
+		}
+		if c.Note != "" {
+			state.Notes = append(state.Notes, models.TriageNote{
+				Author: c.By,
+				Text:   c.Note,
+				At:     c.At,
+			})
+		}
+
+		at := c.At
+		state.UpdatedAt = &at
+		state.UpdatedBy = c.By
+	}
+	l.states[change.FractureID] = state
+}
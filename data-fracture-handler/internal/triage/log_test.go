@@ -0,0 +1,132 @@
+package triage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/data-fracture-handler/internal/storage"
+	"github.com/veps-service-480701/data-fracture-handler/pkg/models"
+)
+
+func newStore(t *testing.T) *storage.LocalStore {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	return store
+}
+
+func update(t *testing.T, l *Log, id uuid.UUID, u models.TriageUpdate) models.Triage {
+	t.Helper()
+	state, err := l.Update(context.Background(), id, u)
+	if err != nil {
+		t.Fatalf("Update(%+v): %v", u, err)
+	}
+	return state
+}
+
+func TestUntriagedFractureIsOpen(t *testing.T) {
+	l := Open(newStore(t))
+	if got := l.Get(uuid.New()); got.Status != models.TriageOpen || got.UpdatedAt != nil {
+		t.Errorf("Get = %+v, want an open fracture with no history", got)
+	}
+}
+
+func TestUpdatesFoldIntoState(t *testing.T) {
+	l := Open(newStore(t))
+	id := uuid.New()
+	alice := "alice"
+
+	update(t, l, id, models.TriageUpdate{Status: models.TriageAcknowledged, Assignee: &alice, By: "bob"})
+	update(t, l, id, models.TriageUpdate{Note: "rule was too strict", By: "alice"})
+	state := update(t, l, id, models.TriageUpdate{Status: models.TriageFalsePositive, By: "alice"})
+
+	if state.Status != models.TriageFalsePositive || state.Assignee != "alice" || state.UpdatedBy != "alice" {
+		t.Errorf("state = %+v, want a false positive assigned to alice", state)
+	}
+	if len(state.Notes) != 1 || state.Notes[0].Author != "alice" || state.Notes[0].Text != "rule was too strict" {
+		t.Errorf("notes = %+v, want alice's note", state.Notes)
+	}
+
+	history := l.History(id)
+	if len(history) != 3 {
+		t.Fatalf("history has %d changes, want 3", len(history))
+	}
+	if last := history[2]; last.PreviousStatus != models.TriageAcknowledged || last.Status != models.TriageFalsePositive {
+		t.Errorf("last change = %+v, want acknowledged -> false_positive", last)
+	}
+	if history[1].Status != "" || history[1].Assignee != nil {
+		t.Errorf("note-only change = %+v, want no status or assignee", history[1])
+	}
+}
+
+func TestUpdateRejectsInvalidAndEmptyChanges(t *testing.T) {
+	l := Open(newStore(t))
+	id := uuid.New()
+
+	tests := []struct {
+		name   string
+		update models.TriageUpdate
+		want   error
+	}{
+		{name: "missing author", update: models.TriageUpdate{Status: models.TriageResolved}, want: ErrInvalidUpdate},
+		{name: "unknown status", update: models.TriageUpdate{Status: "closed", By: "bob"}, want: ErrInvalidUpdate},
+		{name: "same status", update: models.TriageUpdate{Status: models.TriageOpen, By: "bob"}, want: ErrNoChange},
+		{name: "nothing", update: models.TriageUpdate{By: "bob"}, want: ErrNoChange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := l.Update(context.Background(), id, tt.update); !errors.Is(err, tt.want) {
+				t.Errorf("Update = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if history := l.History(id); len(history) != 0 {
+		t.Errorf("rejected updates left %d changes", len(history))
+	}
+}
+
+func TestInstancesShareHistory(t *testing.T) {
+	store := newStore(t)
+	id := uuid.New()
+
+	first := Open(store)
+	second := Open(store)
+
+	update(t, first, id, models.TriageUpdate{Status: models.TriageAcknowledged, By: "bob"})
+
+	// The second instance syncs before computing its change
+	state := update(t, second, id, models.TriageUpdate{Status: models.TriageResolved, By: "carol"})
+	if state.Status != models.TriageResolved {
+		t.Errorf("status = %s, want resolved", state.Status)
+	}
+	if history := second.History(id); len(history) != 2 || history[1].PreviousStatus != models.TriageAcknowledged {
+		t.Errorf("history on the second instance = %+v, want acknowledged then resolved", history)
+	}
+
+	if err := first.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := first.Get(id); got.Status != models.TriageResolved || got.UpdatedBy != "carol" {
+		t.Errorf("first instance sees %+v, want carol's resolution", got)
+	}
+}
+
+func TestOutOfOrderChangesAreFoldedByTime(t *testing.T) {
+	l := Open(newStore(t))
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	// A later change read before an earlier one from another instance
+	l.apply(models.TriageChange{FractureID: id, At: now, By: "b", Status: models.TriageResolved})
+	l.apply(models.TriageChange{FractureID: id, At: now.Add(-time.Minute), By: "a", Status: models.TriageAcknowledged})
+
+	if got := l.Get(id); got.Status != models.TriageResolved || got.UpdatedBy != "b" {
+		t.Errorf("state = %+v, want the later resolution to win", got)
+	}
+}
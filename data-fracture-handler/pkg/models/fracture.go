@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
 // FracturedEvent represents a vetoed event stored for audit
@@ -33,35 +34,14 @@ type FractureContext struct {
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// Event represents the normalized event that was rejected
-// This mirrors the structure from VEPS
-type Event struct {
-	ID          uuid.UUID              `json:"id"`
-	Type        string                 `json:"type"`
-	Source      string                 `json:"source"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Actor       Actor                  `json:"actor"`
-	Evidence    map[string]interface{} `json:"evidence"`
-	VectorClock map[string]int64       `json:"vector_clock"`
-	Metadata    EventMetadata          `json:"metadata"`
-}
-
-// Actor represents the entity performing the action
-type Actor struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
-}
-
-// EventMetadata contains system-level metadata
-type EventMetadata struct {
-	ReceivedAt    time.Time `json:"received_at"`
-	ProcessedAt   time.Time `json:"processed_at"`
-	BoundaryNode  string    `json:"boundary_node"`
-	CorrelationID string    `json:"correlation_id"`
-	RetryCount    int       `json:"retry_count"`
-	SchemaVersion string    `json:"schema_version"`
-}
+// Event and its parts are the canonical VEPS types from veps-common
+// Fractures store the event exactly as the Boundary Adapter normalized it
+type (
+	Event         = model.Event
+	Actor         = model.Actor
+	VectorClock   = model.VectorClock
+	EventMetadata = model.EventMetadata
+)
 
 // FractureRequest represents the incoming request to log a vetoed event
 type FractureRequest struct {
@@ -74,10 +54,20 @@ type FractureRequest struct {
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// fractureNamespace is the UUID namespace fracture IDs are derived in
+var fractureNamespace = uuid.MustParse("6f1d3c2e-8a4b-5c7d-9e0f-1a2b3c4d5e6f")
+
+// FractureID returns the ID of the fracture for an event vetoed by vetoNode
+// The Boundary Adapter delivers fractures at least once, so the ID is derived
+// rather than random: a redelivered request maps onto the fracture it already created
+func FractureID(eventID uuid.UUID, vetoNode string) uuid.UUID {
+	return uuid.NewSHA1(fractureNamespace, []byte(eventID.String()+"|"+vetoNode))
+}
+
 // ToFracturedEvent converts a FractureRequest to a FracturedEvent
 func (fr *FractureRequest) ToFracturedEvent() *FracturedEvent {
 	return &FracturedEvent{
-		FractureID: uuid.New(),
+		FractureID: FractureID(fr.Event.ID, fr.VetoNode),
 		Timestamp:  time.Now().UTC(),
 		Event:      fr.Event,
 		Rejection: RejectionDetails{
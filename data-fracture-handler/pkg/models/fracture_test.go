@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFractureIDIsDerivedFromEventAndVetoNode(t *testing.T) {
+	eventID := uuid.New()
+
+	req := FractureRequest{Event: Event{ID: eventID}, VetoNode: "veto-1"}
+	first := req.ToFracturedEvent()
+	again := req.ToFracturedEvent()
+	if first.FractureID != again.FractureID {
+		t.Errorf("redelivered request got fracture %s, want %s", again.FractureID, first.FractureID)
+	}
+
+	other := FractureRequest{Event: Event{ID: eventID}, VetoNode: "veto-2"}
+	if other.ToFracturedEvent().FractureID == first.FractureID {
+		t.Error("vetoes from two nodes share a fracture ID")
+	}
+	if FractureID(uuid.New(), "veto-1") == first.FractureID {
+		t.Error("two events share a fracture ID")
+	}
+}
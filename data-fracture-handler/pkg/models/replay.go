@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Replay targets
+const (
+	ReplayTargetBoundary = "boundary" // re-ingest through the Boundary Adapter, sealing on success
+	ReplayTargetVeto     = "veto"     // re-validate with the Veto Service only
+)
+
+// Replay statuses
+const (
+	ReplayPending     = "pending"     // claimed, not sent yet or in flight
+	ReplayAccepted    = "accepted"    // passed the veto (and was sealed, for the boundary target)
+	ReplayVetoed      = "vetoed"      // vetoed again
+	ReplayFailed      = "failed"      // the target could not be reached or returned an error
+	ReplayInterrupted = "interrupted" // the handler stopped mid-replay; the outcome is unknown
+)
+
+// ReplayRequest selects fractures to replay
+// Either FractureIDs or at least one filter field must be set
+type ReplayRequest struct {
+	FractureIDs   []uuid.UUID `json:"fracture_ids,omitempty"`
+	EventID       string      `json:"event_id,omitempty"`
+	ActorID       string      `json:"actor_id,omitempty"`
+	FailedCheck   string      `json:"failed_check,omitempty"`
+	Source        string      `json:"source,omitempty"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+	Start         time.Time   `json:"start,omitempty"`
+	End           time.Time   `json:"end,omitempty"`
+	Limit         int         `json:"limit,omitempty"`
+	Target        string      `json:"target,omitempty"` // default "boundary"
+	DryRun        bool        `json:"dry_run,omitempty"`
+}
+
+// ReplayRecord is the replay outcome of one fracture
+// Records are keyed by FractureID; a fracture has at most one
+type ReplayRecord struct {
+	FractureID   uuid.UUID  `json:"fracture_id"`
+	RunID        string     `json:"run_id"`
+	Target       string     `json:"target"`
+	EventID      string     `json:"event_id"`               // the vetoed event
+	NewEventID   string     `json:"new_event_id,omitempty"` // the event created by the boundary target
+	Status       string     `json:"status"`
+	HTTPStatus   int        `json:"http_status,omitempty"`
+	FailedChecks []string   `json:"failed_checks,omitempty"`
+	Reasons      []string   `json:"reasons,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	ClaimedAt    time.Time  `json:"claimed_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// RawEvent is the Boundary Adapter's ingest request
+type RawEvent struct {
+	Data      map[string]any `json:"data"`
+	Source    string         `json:"source"`
+	Timestamp time.Time      `json:"timestamp,omitempty"`
+}
+
+// VetoRequest is the Veto Service's validate request
+type VetoRequest struct {
+	Event Event  `json:"event"`
+	Route string `json:"route"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Triage statuses; a fracture with no triage history is open
+const (
+	TriageOpen          = "open"
+	TriageAcknowledged  = "acknowledged"
+	TriageFalsePositive = "false_positive"
+	TriageResolved      = "resolved"
+	TriageReplayed      = "replayed"
+)
+
+// ValidTriageStatus reports whether status is a known triage status
+func ValidTriageStatus(status string) bool {
+	switch status {
+	case TriageOpen, TriageAcknowledged, TriageFalsePositive, TriageResolved, TriageReplayed:
+		return true
+	}
+	return false
+}
+
+// Triage is the current review state of a fracture
+type Triage struct {
+	FractureID uuid.UUID    `json:"fracture_id"`
+	Status     string       `json:"status"`
+	Assignee   string       `json:"assignee,omitempty"`
+	Notes      []TriageNote `json:"notes,omitempty"`
+	UpdatedAt  *time.Time   `json:"updated_at,omitempty"`
+	UpdatedBy  string       `json:"updated_by,omitempty"`
+}
+
+// TriageNote is a timestamped comment on a fracture
+type TriageNote struct {
+	Author string    `json:"author"`
+	Text   string    `json:"text"`
+	At     time.Time `json:"at"`
+}
+
+// TriageUpdate is the body of PATCH /fractures/{id}
+// Omitted fields are left unchanged; an empty assignee unassigns
+type TriageUpdate struct {
+	Status   string  `json:"status,omitempty"`
+	Assignee *string `json:"assignee,omitempty"`
+	Note     string  `json:"note,omitempty"`
+	By       string  `json:"by"`
+}
+
+// TriageChange is one entry of a fracture's append-only triage history
+type TriageChange struct {
+	FractureID     uuid.UUID `json:"fracture_id"`
+	At             time.Time `json:"at"`
+	By             string    `json:"by"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Status         string    `json:"status,omitempty"`   // set when the status changed
+	Assignee       *string   `json:"assignee,omitempty"` // set when the assignee changed
+	Note           string    `json:"note,omitempty"`
+}
@@ -30,6 +30,14 @@ if [ -z "$BOUNDARY_ADAPTER_URL" ]; then
         --format='value(status.url)' 2>/dev/null)
 fi
 
+# Get Veto Service URL (enables event explain)
+if [ -z "$VETO_SERVICE_URL" ]; then
+    VETO_SERVICE_URL=$(gcloud run services describe veto-service \
+        --region=${REGION} \
+        --project=${PROJECT_ID} \
+        --format='value(status.url)' 2>/dev/null)
+fi
+
 # Get Database connection string
 DB_INSTANCE="${PROJECT_ID}:${REGION}:veps-db"
 DB_CONNECTION="host=/cloudsql/${DB_INSTANCE} user=veps_user password=${VEPS_DB_PASSWORD:-veps_password} dbname=veps_db sslmode=disable"
@@ -38,6 +46,7 @@ echo "Project: $PROJECT_ID"
 echo "Region: $REGION"
 echo "Service: $SERVICE_NAME"
 echo "Boundary Adapter: $BOUNDARY_ADAPTER_URL"
+echo "Veto Service: $VETO_SERVICE_URL"
 echo "Database Instance: $DB_INSTANCE"
 echo ""
 
@@ -69,15 +78,30 @@ gcloud secrets add-iam-policy-binding veps-db-password \
     --member="serviceAccount:${SA_EMAIL}" \
     --role="roles/secretmanager.secretAccessor" \
     --project=${PROJECT_ID} 2>/dev/null || echo "Secret Manager permission already granted"
+
+# Event explain calls the Veto Service directly
+gcloud run services add-iam-policy-binding veto-service \
+    --member="serviceAccount:${SA_EMAIL}" \
+    --role="roles/run.invoker" \
+    --region=${REGION} \
+    --project=${PROJECT_ID} 2>/dev/null || echo "Veto Service invoker permission already granted"
     
 echo "✓ Permissions configured"
 echo ""
 
 # Step 3: Build and push image
 echo "[Step 3] Building and pushing Docker image..."
-cd api-gateway
-
-gcloud builds submit --tag ${IMAGE_TAG} --project=${PROJECT_ID}
+# Build from the repository root so the shared veps-common module is in context
+BUILD_CONFIG=$(mktemp)
+cat > ${BUILD_CONFIG} <<EOF
+steps:
+- name: 'gcr.io/cloud-builders/docker'
+  args: ['build', '-f', 'api-gateway/Dockerfile', '-t', '${IMAGE_TAG}', '.']
+images: ['${IMAGE_TAG}']
+EOF
+
+gcloud builds submit --config ${BUILD_CONFIG} --project=${PROJECT_ID} .
+rm -f ${BUILD_CONFIG}
 echo "✓ Image built and pushed"
 echo ""
 
@@ -90,6 +114,7 @@ gcloud run deploy ${SERVICE_NAME} \
     --platform managed \
     --service-account=${SA_EMAIL} \
     --set-env-vars "BOUNDARY_ADAPTER_URL=${BOUNDARY_ADAPTER_URL}" \
+    --set-env-vars "VETO_SERVICE_URL=${VETO_SERVICE_URL}" \
     --set-env-vars "GCP_PROJECT=${PROJECT_ID}" \
     --set-env-vars "DB_INSTANCE=${DB_INSTANCE}" \
     --set-env-vars "DB_USER=veps_user" \
@@ -101,6 +126,7 @@ gcloud run deploy ${SERVICE_NAME} \
     --memory=512Mi \
     --cpu=1 \
     --timeout=60 \
+    --no-cpu-throttling \
     --project=${PROJECT_ID}
 
 echo "✓ Service deployed"
@@ -120,6 +146,7 @@ echo "Service URL: ${SERVICE_URL}"
 echo ""
 echo "API Endpoints:"
 echo "  POST   ${SERVICE_URL}/api/v1/events          - Submit event"
+echo "  POST   ${SERVICE_URL}/api/v1/events/explain  - Explain veto checks"
 echo "  GET    ${SERVICE_URL}/api/v1/causality       - Check causality"
 echo "  GET    ${SERVICE_URL}/api/v1/events          - Batch retrieve"
 echo "  GET    ${SERVICE_URL}/health                 - Health check"
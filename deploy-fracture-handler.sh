@@ -88,9 +88,17 @@ echo ""
 
 # Step 4: Build and push image
 echo "[Step 4] Building and pushing Docker image..."
-cd data-fracture-handler
+# Build from the repository root so the shared veps-common module is in context
+BUILD_CONFIG=$(mktemp)
+cat > ${BUILD_CONFIG} <<EOF
+steps:
+- name: 'gcr.io/cloud-builders/docker'
+  args: ['build', '-f', 'data-fracture-handler/Dockerfile', '-t', '${IMAGE_TAG}', '.']
+images: ['${IMAGE_TAG}']
+EOF
 
-gcloud builds submit --tag ${IMAGE_TAG} --project=${PROJECT_ID}
+gcloud builds submit --config ${BUILD_CONFIG} --project=${PROJECT_ID} .
+rm -f ${BUILD_CONFIG}
 echo "✓ Image built and pushed"
 echo ""
 
@@ -103,6 +111,7 @@ gcloud run deploy ${SERVICE_NAME} \
     --service-account=${SA_EMAIL} \
     --set-env-vars "GCS_BUCKET_NAME=${BUCKET_NAME},FRACTURE_NODE_ID=${SERVICE_NAME}-${REGION}-001" \
     --allow-unauthenticated \
+    --no-cpu-throttling \
     --min-instances=0 \
     --max-instances=10 \
     --memory=256Mi \
@@ -44,9 +44,17 @@ echo ""
 
 # Step 2: Build and push image
 echo "[Step 2] Building and pushing Docker image..."
-cd monolith-submitter
-
-gcloud builds submit --tag ${IMAGE_TAG} --project=${PROJECT_ID}
+# Build from the repository root so the shared veps-common module is in context
+BUILD_CONFIG=$(mktemp)
+cat > ${BUILD_CONFIG} <<EOF
+steps:
+- name: 'gcr.io/cloud-builders/docker'
+  args: ['build', '-f', 'monolith-submitter/Dockerfile', '-t', '${IMAGE_TAG}', '.']
+images: ['${IMAGE_TAG}']
+EOF
+
+gcloud builds submit --config ${BUILD_CONFIG} --project=${PROJECT_ID} .
+rm -f ${BUILD_CONFIG}
 echo "✓ Image built and pushed"
 echo ""
 
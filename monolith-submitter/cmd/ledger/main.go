@@ -0,0 +1,94 @@
+// Command ledger runs a single-node ImmutableLedger for local development and CI
+// Point the Monolith Submitter at it with LEDGER_ADDRESS=localhost:50051
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	"github.com/veps-service-480701/monolith-submitter/internal/ledgerserver"
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+)
+
+func main() {
+	log.Println("[Main] Starting reference ImmutableLedger...")
+
+	config := loadConfig()
+
+	ledgerLog, err := ledgerserver.OpenLog(config.LogPath)
+	if err != nil {
+		log.Fatalf("[Main] Failed to open ledger log: %v", err)
+	}
+	defer ledgerLog.Close()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", config.Port))
+	if err != nil {
+		log.Fatalf("[Main] Failed to listen on port %s: %v", config.Port, err)
+	}
+
+	server := grpc.NewServer(
+		grpc.MaxRecvMsgSize(10*1024*1024), // 10MB, matches the submitter's client
+		grpc.MaxSendMsgSize(10*1024*1024),
+	)
+	pb.RegisterImmutableLedgerServer(server, ledgerserver.New(ledgerLog, config.ShardID, config.NodeID))
+
+	go func() {
+		log.Printf("[Main] Ledger %s listening on port %s (log: %s)", config.ShardID, config.Port, config.LogPath)
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("[Main] Server failed: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("[Main] Shutdown signal received, gracefully shutting down...")
+	server.GracefulStop()
+	log.Println("[Main] Ledger exited successfully")
+}
+
+// Config holds ledger configuration
+type Config struct {
+	Port    string
+	LogPath string
+	ShardID string
+	NodeID  string
+}
+
+// loadConfig loads configuration from environment variables
+func loadConfig() Config {
+	port := os.Getenv("LEDGER_PORT")
+	if port == "" {
+		port = "50051"
+	}
+
+	logPath := os.Getenv("LEDGER_LOG_PATH")
+	if logPath == "" {
+		logPath = "data/ledger.log"
+		log.Printf("[Main] Using default LEDGER_LOG_PATH: %s", logPath)
+	}
+
+	shardID := os.Getenv("LEDGER_SHARD_ID")
+	if shardID == "" {
+		shardID = "shard-0"
+	}
+
+	nodeID := os.Getenv("LEDGER_NODE_ID")
+	if nodeID == "" {
+		nodeID, _ = os.Hostname()
+	}
+
+	return Config{
+		Port:    port,
+		LogPath: logPath,
+		ShardID: shardID,
+		NodeID:  nodeID,
+	}
+}
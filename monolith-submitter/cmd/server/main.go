@@ -25,6 +25,7 @@ func main() {
 		config.LedgerAddress,
 		config.SecretKey,
 		config.NodeID,
+		config.TenantID,
 	)
 	if err != nil {
 		log.Fatalf("[Main] Failed to initialize Ledger client: %v", err)
@@ -95,6 +96,7 @@ type Config struct {
 	LedgerAddress string
 	SecretKey     string
 	NodeID        string
+	TenantID      string
 }
 
 // loadConfig loads configuration from environment variables
@@ -122,11 +124,18 @@ func loadConfig() Config {
 		log.Printf("[Main] Using default MONOLITH_NODE_ID: %s", nodeID)
 	}
 
+	tenantID := os.Getenv("LEDGER_TENANT_ID")
+	if tenantID == "" {
+		tenantID = "veps"
+		log.Printf("[Main] Using default LEDGER_TENANT_ID: %s", tenantID)
+	}
+
 	return Config{
 		Port:          port,
 		LedgerAddress: ledgerAddress,
 		SecretKey:     secretKey,
 		NodeID:        nodeID,
+		TenantID:      tenantID,
 	}
 }
 
@@ -0,0 +1,59 @@
+// Command verify checks the ImmutableLedger hash chain over a range of sequence numbers
+// It exits with status 1 if a link is broken, reporting the first broken link
+//
+//	LEDGER_ADDRESS=localhost:50051 VEPS_SECRET_KEY=... verify -from 1 -to 1000
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/veps-service-480701/monolith-submitter/internal/client"
+)
+
+func main() {
+	from := flag.Uint64("from", 1, "first sequence number to verify")
+	to := flag.Uint64("to", 0, "last sequence number to verify (0 = ledger head)")
+	timeout := flag.Duration("timeout", 10*time.Minute, "overall verification timeout")
+	flag.Parse()
+
+	ledgerAddress := os.Getenv("LEDGER_ADDRESS")
+	if ledgerAddress == "" {
+		ledgerAddress = "ledger-service.immutable-ledger.svc.cluster.local:50051"
+	}
+
+	secretKey := os.Getenv("VEPS_SECRET_KEY")
+	if secretKey == "" {
+		secretKey = "default-dev-secret-key-change-in-production"
+		log.Printf("[Main] Warning: Using default secret key (set VEPS_SECRET_KEY to match the submitter)")
+	}
+
+	ledgerClient, err := client.NewLedgerClient(ledgerAddress, secretKey, "", "")
+	if err != nil {
+		log.Fatalf("[Main] Failed to initialize Ledger client: %v", err)
+	}
+	defer ledgerClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// The command runs under its own timeout, so the range is not paged
+	report, err := ledgerClient.Verify(ctx, *from, *to, 0)
+	if err != nil {
+		log.Fatalf("[Main] Verification failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("[Main] Failed to write report: %v", err)
+	}
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
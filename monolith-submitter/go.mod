@@ -11,8 +11,11 @@ require (
 )
 
 require (
+	github.com/veps-service-480701/veps-common v0.0.0
 	golang.org/x/net v0.29.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
 )
+
+replace github.com/veps-service-480701/veps-common => ../veps-common
@@ -2,14 +2,12 @@ package client
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -23,10 +21,11 @@ type LedgerClient struct {
 	client    pb.ImmutableLedgerClient
 	secretKey []byte
 	nodeID    string
+	tenantID  string
 }
 
 // NewLedgerClient creates a new ImmutableLedger client
-func NewLedgerClient(address string, secretKey string, nodeID string) (*LedgerClient, error) {
+func NewLedgerClient(address string, secretKey string, nodeID string, tenantID string) (*LedgerClient, error) {
 	// Create gRPC connection
 	conn, err := grpc.Dial(
 		address,
@@ -49,6 +48,7 @@ func NewLedgerClient(address string, secretKey string, nodeID string) (*LedgerCl
 		client:    client,
 		secretKey: []byte(secretKey),
 		nodeID:    nodeID,
+		tenantID:  tenantID,
 	}, nil
 }
 
@@ -61,30 +61,9 @@ func (lc *LedgerClient) Close() error {
 func (lc *LedgerClient) SubmitEvent(ctx context.Context, event models.Event) (*models.SubmitResponse, error) {
 	startTime := time.Now()
 
-	// Serialize event to JSON
-	payload, err := json.Marshal(event)
+	certifiedEvent, err := lc.certify(event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal event: %w", err)
-	}
-
-	// Generate cryptographic signature
-	signature := lc.signEvent(payload)
-
-	// Create certified event
-	certifiedEvent := &pb.CertifiedEvent{
-		EventId:       event.ID.String(),
-		Payload:       payload,
-		VepsSignature: signature,
-		VepsTimestamp: time.Now().UnixMilli(),
-		Metadata: map[string]string{
-			"veps_node":       lc.nodeID,
-			"boundary_node":   event.Metadata.BoundaryNode,
-			"correlation_id":  event.Metadata.CorrelationID,
-			"event_type":      event.Type,
-			"event_source":    event.Source,
-			"actor_id":        event.Actor.ID,
-			"original_timestamp": event.Timestamp.Format(time.RFC3339Nano),
-		},
+		return nil, err
 	}
 
 	log.Printf("[LedgerClient] Submitting event %s to ImmutableLedger", event.ID)
@@ -115,11 +94,49 @@ func (lc *LedgerClient) SubmitEvent(ctx context.Context, event models.Event) (*m
 	return response, nil
 }
 
-// signEvent creates an HMAC-SHA256 signature for the event
-func (lc *LedgerClient) signEvent(payload []byte) string {
-	mac := hmac.New(sha256.New, lc.secretKey)
-	mac.Write(payload)
-	return hex.EncodeToString(mac.Sum(nil))
+// certify maps an event onto the ledger's CertifiedEvent and signs it
+func (lc *LedgerClient) certify(event models.Event) (*pb.CertifiedEvent, error) {
+	evidenceJSON, err := json.Marshal(event.Evidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evidence: %w", err)
+	}
+
+	vectorClockJSON, err := json.Marshal(event.VectorClock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal vector clock: %w", err)
+	}
+
+	certifiedEvent := &pb.CertifiedEvent{
+		EventId:   event.ID.String(),
+		TenantId:  lc.tenantID,
+		Type:      event.Type,
+		Source:    event.Source,
+		Timestamp: event.Timestamp.UnixNano(),
+		Actor: &pb.Actor{
+			Id:   event.Actor.ID,
+			Name: event.Actor.Name,
+			Type: event.Actor.Type,
+		},
+		EvidenceJson:    evidenceJSON,
+		VectorClockJson: vectorClockJSON,
+		VepsTimestamp:   time.Now().UnixMilli(),
+		BoundaryNode:    event.Metadata.BoundaryNode,
+		CorrelationId:   event.Metadata.CorrelationID,
+		Metadata: map[string]string{
+			"veps_node":      lc.nodeID,
+			"schema_version": event.Metadata.SchemaVersion,
+			"received_at":    event.Metadata.ReceivedAt.Format(time.RFC3339Nano),
+		},
+	}
+
+	// Generate cryptographic signature
+	signature, err := sign(lc.secretKey, certifiedFields(certifiedEvent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign event: %w", err)
+	}
+	certifiedEvent.VepsSignature = signature
+
+	return certifiedEvent, nil
 }
 
 // HealthCheck checks if the ImmutableLedger is healthy
@@ -135,11 +152,11 @@ func (lc *LedgerClient) HealthCheck(ctx context.Context) (bool, string, uint64,
 		return false, "", 0, fmt.Errorf("health check failed: %w", err)
 	}
 
-	return resp.Healthy, resp.Status, resp.LastSequenceNumber, nil
+	return resp.Status == "healthy", resp.Status, resp.LatestSequence, nil
 }
 
 // GetEvent retrieves a sealed event by sequence number
-func (lc *LedgerClient) GetEvent(ctx context.Context, sequenceNumber uint64) (*pb.SealedEvent, error) {
+func (lc *LedgerClient) GetEvent(ctx context.Context, sequenceNumber uint64) (*models.SealedEvent, error) {
 	req := &pb.GetEventRequest{
 		SequenceNumber: sequenceNumber,
 	}
@@ -149,5 +166,57 @@ func (lc *LedgerClient) GetEvent(ctx context.Context, sequenceNumber uint64) (*p
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
 
-	return resp, nil
+	return decodeSealed(resp)
+}
+
+// decodeSealed maps a SealedEvent from the ledger back onto the canonical event
+func decodeSealed(se *pb.SealedEvent) (*models.SealedEvent, error) {
+	id, err := uuid.Parse(se.GetEventId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ID %q: %w", se.GetEventId(), err)
+	}
+
+	event := models.Event{
+		ID:        id,
+		Type:      se.GetType(),
+		Source:    se.GetSource(),
+		Timestamp: time.Unix(0, se.GetTimestamp()).UTC(),
+		Actor: models.Actor{
+			ID:   se.GetActor().GetId(),
+			Name: se.GetActor().GetName(),
+			Type: se.GetActor().GetType(),
+		},
+		Metadata: models.EventMetadata{
+			BoundaryNode:  se.GetBoundaryNode(),
+			CorrelationID: se.GetCorrelationId(),
+			SchemaVersion: se.GetMetadata()["schema_version"],
+		},
+	}
+
+	if receivedAt, err := time.Parse(time.RFC3339Nano, se.GetMetadata()["received_at"]); err == nil {
+		event.Metadata.ReceivedAt = receivedAt
+	}
+
+	if len(se.GetEvidenceJson()) > 0 {
+		if err := json.Unmarshal(se.GetEvidenceJson(), &event.Evidence); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal evidence: %w", err)
+		}
+	}
+
+	if len(se.GetVectorClockJson()) > 0 {
+		if err := json.Unmarshal(se.GetVectorClockJson(), &event.VectorClock); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vector clock: %w", err)
+		}
+	}
+
+	return &models.SealedEvent{
+		SequenceNumber:  se.GetSequenceNumber(),
+		ShardID:         se.GetShardId(),
+		TenantID:        se.GetTenantId(),
+		EventHash:       se.GetEventHash(),
+		PreviousHash:    se.GetPreviousHash(),
+		SealedTimestamp: time.UnixMilli(se.GetSealedTimestamp()),
+		CommitLatencyMS: se.GetCommitLatencyMs(),
+		Event:           event,
+	}, nil
 }
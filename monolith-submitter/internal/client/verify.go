@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+	"github.com/veps-service-480701/monolith-submitter/pkg/models"
+)
+
+// verifyBatchSize is the GetEvents page size used while walking the chain
+const verifyBatchSize = 1000
+
+// ErrInvalidRange is returned when a verification range is empty or past the ledger head
+var ErrInvalidRange = errors.New("invalid verification range")
+
+// Verify walks sequence numbers from..to (inclusive) and checks the hash chain
+// For every event it checks the previous_hash link, recomputes event_hash and
+// verifies the VEPS HMAC signature. It stops at the first broken link.
+// A zero from starts at the first event; a zero to runs to the ledger head.
+// A non-zero limit checks at most that many events; the report's next_from
+// then says where to continue
+func (lc *LedgerClient) Verify(ctx context.Context, from, to, limit uint64) (*models.VerifyReport, error) {
+	if from == 0 {
+		from = 1
+	}
+
+	if to == 0 {
+		resp, err := lc.client.GetEvents(ctx, &pb.GetEventsRequest{Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest sequence: %w", err)
+		}
+		to = resp.LatestSequence
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("%w: from %d is after to %d", ErrInvalidRange, from, to)
+	}
+
+	requestedTo := to
+	if limit > 0 && to-from >= limit {
+		to = from + limit - 1
+	}
+
+	report := &models.VerifyReport{From: from, To: to, LastVerified: from - 1}
+
+	// The first event links to the event before the range
+	previousHash := pb.GenesisHash
+	if from > 1 {
+		resp, err := lc.client.GetEventHash(ctx, &pb.GetEventHashRequest{SequenceNumber: from - 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hash of sequence %d: %w", from-1, err)
+		}
+		previousHash = resp.EventHash
+	}
+
+	cursor := from - 1
+	for cursor < to {
+		resp, err := lc.client.GetEvents(ctx, &pb.GetEventsRequest{
+			StartSequence: cursor,
+			Limit:         verifyBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events after sequence %d: %w", cursor, err)
+		}
+
+		if len(resp.Events) == 0 {
+			report.BrokenLink = &models.BrokenLink{
+				SequenceNumber: cursor + 1,
+				Reason:         fmt.Sprintf("event not found (ledger head is %d)", resp.LatestSequence),
+			}
+			break
+		}
+
+		for _, se := range resp.Events {
+			if cursor == to {
+				break
+			}
+
+			if reason := lc.checkLink(se, cursor+1, previousHash); reason != "" {
+				report.BrokenLink = &models.BrokenLink{
+					SequenceNumber: cursor + 1,
+					EventID:        se.EventId,
+					Reason:         reason,
+				}
+				break
+			}
+
+			report.Checked++
+			previousHash = se.EventHash
+			cursor = se.SequenceNumber
+		}
+
+		if report.BrokenLink != nil {
+			break
+		}
+	}
+
+	report.LastVerified = cursor
+	report.Valid = report.BrokenLink == nil
+	if report.Valid && to < requestedTo {
+		report.NextFrom = to + 1
+	}
+
+	if report.Valid {
+		log.Printf("[Verifier] Chain verified from %d to %d (%d events)", from, to, report.Checked)
+	} else {
+		log.Printf("[Verifier] Chain broken at sequence %d: %s",
+			report.BrokenLink.SequenceNumber, report.BrokenLink.Reason)
+	}
+
+	return report, nil
+}
+
+// checkLink verifies one sealed event and returns why it is broken, or ""
+func (lc *LedgerClient) checkLink(se *pb.SealedEvent, expectedSequence uint64, previousHash string) string {
+	if se.SequenceNumber != expectedSequence {
+		return fmt.Sprintf("sequence gap: expected %d, got %d", expectedSequence, se.SequenceNumber)
+	}
+
+	if se.PreviousHash != previousHash {
+		return fmt.Sprintf("previous_hash %s does not match event_hash %s of sequence %d",
+			se.PreviousHash, previousHash, expectedSequence-1)
+	}
+
+	if computed := pb.EventHash(se); se.EventHash != computed {
+		return fmt.Sprintf("event_hash mismatch: stored %s, computed %s", se.EventHash, computed)
+	}
+
+	if se.VepsSignature == "" {
+		return "missing veps_signature"
+	}
+	if !verifySignature(lc.secretKey, sealedFields(se), se.VepsSignature) {
+		return "veps_signature does not match event contents"
+	}
+
+	return ""
+}
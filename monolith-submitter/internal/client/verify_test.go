@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+)
+
+const testSecret = "test-secret"
+
+// fakeLedger serves GetEvents and GetEventHash from a slice, in pages of at
+// most pageSize events so Verify has to walk several batches
+type fakeLedger struct {
+	pb.ImmutableLedgerClient
+	events   []*pb.SealedEvent // events[i] has sequence number i+1
+	pageSize int
+}
+
+func (f *fakeLedger) GetEvents(ctx context.Context, req *pb.GetEventsRequest, _ ...grpc.CallOption) (*pb.GetEventsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 || limit > f.pageSize {
+		limit = f.pageSize
+	}
+
+	resp := &pb.GetEventsResponse{LatestSequence: uint64(len(f.events))}
+	for i := req.GetStartSequence(); i < uint64(len(f.events)); i++ {
+		if len(resp.Events) == limit {
+			resp.HasMore = true
+			break
+		}
+		resp.Events = append(resp.Events, f.events[i])
+	}
+	return resp, nil
+}
+
+func (f *fakeLedger) GetEventHash(ctx context.Context, req *pb.GetEventHashRequest, _ ...grpc.CallOption) (*pb.GetEventHashResponse, error) {
+	seq := req.GetSequenceNumber()
+	if seq == 0 || seq > uint64(len(f.events)) {
+		return nil, status.Errorf(codes.NotFound, "no event with sequence %d", seq)
+	}
+	return &pb.GetEventHashResponse{EventHash: f.events[seq-1].EventHash}, nil
+}
+
+// reseal recomputes an event's signature and hash, as a forger with the key would
+func reseal(t *testing.T, se *pb.SealedEvent) {
+	t.Helper()
+	signature, err := sign([]byte(testSecret), sealedFields(se))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	se.VepsSignature = signature
+	se.EventHash = pb.EventHash(se)
+}
+
+// newChain returns a client over a correctly sealed chain of n events
+func newChain(t *testing.T, n int) (*LedgerClient, *fakeLedger) {
+	t.Helper()
+	ledger := &fakeLedger{pageSize: 3}
+	previousHash := pb.GenesisHash
+	for i := 1; i <= n; i++ {
+		se := &pb.SealedEvent{
+			SequenceNumber: uint64(i),
+			ShardId:        "shard-0",
+			PreviousHash:   previousHash,
+			EventId:        fmt.Sprintf("event-%d", i),
+			Type:           "payment",
+			Actor:          &pb.Actor{Id: "user-1"},
+			EvidenceJson:   []byte(fmt.Sprintf(`{"amount":%d}`, i)),
+			HashVersion:    pb.CurrentHashVersion,
+		}
+		reseal(t, se)
+		ledger.events = append(ledger.events, se)
+		previousHash = se.EventHash
+	}
+	return &LedgerClient{client: ledger, secretKey: []byte(testSecret)}, ledger
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	lc, _ := newChain(t, 10)
+
+	report, err := lc.Verify(context.Background(), 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !report.Valid || report.Checked != 10 || report.LastVerified != 10 || report.NextFrom != 0 {
+		t.Errorf("Verify = %+v, want 10 valid events and nothing left", report)
+	}
+
+	// A range inside the chain links to the event before it
+	report, err = lc.Verify(context.Background(), 4, 7, 0)
+	if err != nil {
+		t.Fatalf("Verify(4, 7): %v", err)
+	}
+	if !report.Valid || report.Checked != 4 || report.LastVerified != 7 {
+		t.Errorf("Verify(4, 7) = %+v, want 4 valid events up to 7", report)
+	}
+}
+
+func TestVerifyTamperedChain(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, events []*pb.SealedEvent)
+		want   string
+	}{
+		{
+			name: "edited evidence",
+			tamper: func(t *testing.T, events []*pb.SealedEvent) {
+				events[5].EvidenceJson = []byte(`{"amount":1000000}`)
+			},
+			want: "event_hash mismatch",
+		},
+		{
+			name: "rehashed without the key",
+			tamper: func(t *testing.T, events []*pb.SealedEvent) {
+				events[5].EvidenceJson = []byte(`{"amount":1000000}`)
+				events[5].EventHash = pb.EventHash(events[5])
+			},
+			want: "veps_signature does not match",
+		},
+		{
+			name: "resealed with the key but not relinked",
+			tamper: func(t *testing.T, events []*pb.SealedEvent) {
+				events[5].EvidenceJson = []byte(`{"amount":1000000}`)
+				reseal(t, events[5])
+			},
+			// The edited event verifies; the next one no longer links to it
+			want: "previous_hash",
+		},
+		{
+			name: "removed event",
+			tamper: func(t *testing.T, events []*pb.SealedEvent) {
+				events[5] = events[6]
+			},
+			want: "sequence gap",
+		},
+		{
+			name: "missing signature",
+			tamper: func(t *testing.T, events []*pb.SealedEvent) {
+				events[5].VepsSignature = ""
+				events[5].EventHash = pb.EventHash(events[5])
+			},
+			want: "missing veps_signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc, ledger := newChain(t, 10)
+			tt.tamper(t, ledger.events)
+
+			report, err := lc.Verify(context.Background(), 0, 0, 0)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if report.Valid || report.BrokenLink == nil {
+				t.Fatalf("Verify = %+v, want a broken link", report)
+			}
+
+			wantSeq := uint64(6)
+			if tt.want == "previous_hash" {
+				wantSeq = 7
+			}
+			if report.BrokenLink.SequenceNumber != wantSeq || !strings.Contains(report.BrokenLink.Reason, tt.want) {
+				t.Errorf("broken link = %+v, want sequence %d with %q", report.BrokenLink, wantSeq, tt.want)
+			}
+			if report.Checked != wantSeq-1 || report.LastVerified != wantSeq-1 {
+				t.Errorf("checked %d, last verified %d, want both %d", report.Checked, report.LastVerified, wantSeq-1)
+			}
+			if report.NextFrom != 0 {
+				t.Errorf("next_from = %d on a broken chain, want 0", report.NextFrom)
+			}
+		})
+	}
+}
+
+func TestVerifyPagesLongRanges(t *testing.T) {
+	lc, _ := newChain(t, 10)
+
+	var pages int
+	from := uint64(0)
+	for {
+		report, err := lc.Verify(context.Background(), from, 0, 4)
+		if err != nil {
+			t.Fatalf("Verify(from %d): %v", from, err)
+		}
+		pages++
+		if !report.Valid || report.Checked > 4 {
+			t.Fatalf("page %d = %+v, want at most 4 valid events", pages, report)
+		}
+		if report.NextFrom == 0 {
+			if report.LastVerified != 10 {
+				t.Errorf("last page ends at %d, want 10", report.LastVerified)
+			}
+			break
+		}
+		if report.NextFrom != report.LastVerified+1 {
+			t.Fatalf("next_from = %d after %d", report.NextFrom, report.LastVerified)
+		}
+		from = report.NextFrom
+	}
+
+	if pages != 3 {
+		t.Errorf("verified in %d pages, want 3", pages)
+	}
+}
+
+func TestVerifyRejectsEmptyRange(t *testing.T) {
+	lc, _ := newChain(t, 3)
+
+	if _, err := lc.Verify(context.Background(), 5, 0, 0); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Verify past the head = %v, want %v", err, ErrInvalidRange)
+	}
+	if _, err := lc.Verify(context.Background(), 3, 2, 0); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Verify(3, 2) = %v, want %v", err, ErrInvalidRange)
+	}
+}
+
+func TestVerifyReportsMissingEvents(t *testing.T) {
+	lc, _ := newChain(t, 3)
+
+	report, err := lc.Verify(context.Background(), 1, 5, 0)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if report.Valid || report.BrokenLink == nil || report.BrokenLink.SequenceNumber != 4 {
+		t.Errorf("Verify past the head = %+v, want a broken link at 4", report)
+	}
+}
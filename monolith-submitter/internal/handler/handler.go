@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +13,11 @@ import (
 	"github.com/veps-service-480701/monolith-submitter/pkg/models"
 )
 
+// maxVerifyRange bounds how many events one /verify request checks, so it
+// finishes within its timeout however long the chain is; clients page
+// through longer ranges with next_from
+const maxVerifyRange = 10000
+
 // Handler manages HTTP requests for the Monolith Submitter
 type Handler struct {
 	ledgerClient *client.LedgerClient
@@ -100,6 +106,11 @@ func (h *Handler) SubmitEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := submitReq.Event.UpgradeSchema(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	log.Printf("[Handler] Submitting event %s to ImmutableLedger", submitReq.Event.ID)
 
 	// Submit to ImmutableLedger with timeout
@@ -163,25 +174,67 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse payload back to JSON
-	var eventPayload map[string]interface{}
-	if err := json.Unmarshal(sealedEvent.Payload, &eventPayload); err != nil {
-		eventPayload = map[string]interface{}{"raw_bytes": string(sealedEvent.Payload)}
+	response := Response{
+		Success:   true,
+		Message:   "Event retrieved successfully",
+		Timestamp: time.Now().UTC(),
+		Data:      sealedEvent,
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// VerifyChain handles hash chain verification over a range of sequence numbers
+func (h *Handler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	// Both bounds are optional: from defaults to the first event, to to the ledger head
+	var from, to uint64
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if _, err := fmt.Sscanf(fromStr, "%d", &from); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid from sequence number")
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if _, err := fmt.Sscanf(toStr, "%d", &to); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid to sequence number")
+			return
+		}
+	}
+
+	log.Printf("[Handler] Verifying hash chain from %d to %d", from, to)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	report, err := h.ledgerClient.Verify(ctx, from, to, maxVerifyRange)
+	if errors.Is(err, client.ErrInvalidRange) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] Chain verification failed: %v", err)
+		h.writeError(w, http.StatusBadGateway, fmt.Sprintf("chain verification failed: %v", err))
+		return
+	}
+
+	message := "Hash chain verified"
+	if report.NextFrom != 0 {
+		message = fmt.Sprintf("Hash chain verified up to sequence %d; continue from %d", report.LastVerified, report.NextFrom)
+	}
+	if !report.Valid {
+		message = fmt.Sprintf("Hash chain broken at sequence %d", report.BrokenLink.SequenceNumber)
 	}
 
 	response := Response{
 		Success:   true,
-		Message:   "Event retrieved successfully",
+		Message:   message,
 		Timestamp: time.Now().UTC(),
-		Data: map[string]interface{}{
-			"sequence_number":  sealedEvent.SequenceNumber,
-			"event_id":         sealedEvent.EventId,
-			"event_hash":       sealedEvent.EventHash,
-			"previous_hash":    sealedEvent.PreviousHash,
-			"sealed_timestamp": time.UnixMilli(sealedEvent.SealedTimestamp),
-			"commit_latency_ms": sealedEvent.CommitLatencyMs,
-			"payload":          eventPayload,
-		},
+		Data:      report,
 	}
 
 	h.writeJSON(w, http.StatusOK, response)
@@ -211,4 +264,5 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/submit", h.SubmitEvent)
 	mux.HandleFunc("/event", h.GetEvent)
+	mux.HandleFunc("/verify", h.VerifyChain)
 }
@@ -0,0 +1,234 @@
+package ledgerserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+)
+
+// Log is a file-backed, append-only log of sealed events
+// Every event is kept in memory as well; the file is only read on startup
+type Log struct {
+	mu       sync.RWMutex
+	file     *os.File
+	size     int64             // offset of the end of the last complete record
+	broken   error             // set when a failed append could not be rolled back
+	events   []*pb.SealedEvent // events[i] has sequence number i+1
+	byID     map[string]uint64
+	tenants  map[string]struct{}
+	appended chan struct{} // closed and replaced on every append
+}
+
+// OpenLog opens or creates the log at path and loads its events
+func OpenLog(path string) (*Log, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+
+	l := &Log{
+		file:     file,
+		byID:     make(map[string]uint64),
+		tenants:  make(map[string]struct{}),
+		appended: make(chan struct{}),
+	}
+
+	if err := l.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	log.Printf("[Log] Loaded %d sealed events from %s", len(l.events), path)
+	return l, nil
+}
+
+// load reads every line of the log file into memory
+// A final line without a trailing newline is a torn write from a crash
+// and is truncated away
+func (l *Log) load() error {
+	reader := bufio.NewReader(l.file)
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				log.Printf("[Log] Warning: truncating %d bytes of incomplete record at offset %d", len(line), offset)
+				if err := l.file.Truncate(offset); err != nil {
+					return fmt.Errorf("failed to truncate log: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+
+		var se pb.SealedEvent
+		if err := protojson.Unmarshal(bytes.TrimSpace(line), &se); err != nil {
+			return fmt.Errorf("corrupt log record at offset %d: %w", offset, err)
+		}
+
+		expected := uint64(len(l.events)) + 1
+		if se.SequenceNumber != expected {
+			return fmt.Errorf("log record at offset %d has sequence %d, expected %d", offset, se.SequenceNumber, expected)
+		}
+
+		l.index(&se)
+		offset += int64(len(line))
+	}
+
+	if _, err := l.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek log: %w", err)
+	}
+	l.size = offset
+	return nil
+}
+
+// index adds an event to the in-memory views
+func (l *Log) index(se *pb.SealedEvent) {
+	l.events = append(l.events, se)
+	l.byID[se.EventId] = se.SequenceNumber
+	l.tenants[se.TenantId] = struct{}{}
+}
+
+// Close closes the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Append seals an event onto the end of the chain
+// seal is called under the write lock with the next sequence number and the
+// previous event's hash, and must return the fully hashed event. If the event
+// ID was already sealed the existing event is returned instead
+func (l *Log) Append(eventID string, seal func(sequence uint64, previousHash string) *pb.SealedEvent) (*pb.SealedEvent, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq, exists := l.byID[eventID]; exists {
+		return l.events[seq-1], false, nil
+	}
+	if l.broken != nil {
+		return nil, false, fmt.Errorf("log is unwritable after a failed append: %w", l.broken)
+	}
+
+	previousHash := pb.GenesisHash
+	if n := len(l.events); n > 0 {
+		previousHash = l.events[n-1].EventHash
+	}
+
+	se := seal(uint64(len(l.events))+1, previousHash)
+
+	line, err := protojson.Marshal(se)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := l.file.Write(line); err != nil {
+		return nil, false, l.rollback(fmt.Errorf("failed to write log: %w", err))
+	}
+	if err := l.file.Sync(); err != nil {
+		return nil, false, l.rollback(fmt.Errorf("failed to sync log: %w", err))
+	}
+
+	l.size += int64(len(line))
+	l.index(se)
+
+	close(l.appended)
+	l.appended = make(chan struct{})
+
+	return se, true, nil
+}
+
+// rollback truncates a partly written record so the next append starts on a
+// clean line; if that fails too, further appends are refused (caller holds mu)
+func (l *Log) rollback(cause error) error {
+	err := l.file.Truncate(l.size)
+	if err == nil {
+		_, err = l.file.Seek(l.size, io.SeekStart)
+	}
+	if err != nil {
+		l.broken = fmt.Errorf("%v; rollback failed: %w", cause, err)
+		log.Printf("[Log] ERROR: %v", l.broken)
+		return l.broken
+	}
+	return cause
+}
+
+// Get returns the event with the given sequence number
+func (l *Log) Get(sequence uint64) (*pb.SealedEvent, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if sequence == 0 || sequence > uint64(len(l.events)) {
+		return nil, false
+	}
+	return l.events[sequence-1], true
+}
+
+// Range returns up to limit events after the given sequence (exclusive),
+// optionally restricted to one tenant. It also returns the sequence number
+// the scan stopped at, to resume from, and whether more matching events exist
+func (l *Log) Range(after uint64, limit int, tenantID string) ([]*pb.SealedEvent, uint64, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var out []*pb.SealedEvent
+	for i := after; i < uint64(len(l.events)); i++ {
+		se := l.events[i]
+		if tenantID != "" && se.TenantId != tenantID {
+			continue
+		}
+		if len(out) == limit {
+			return out, i, true
+		}
+		out = append(out, se)
+	}
+	return out, max(after, uint64(len(l.events))), false
+}
+
+// Latest returns the highest sealed sequence number
+func (l *Log) Latest() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return uint64(len(l.events))
+}
+
+// TenantCount returns the number of distinct tenants in the log
+func (l *Log) TenantCount() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.tenants)
+}
+
+// Wait returns a channel that is closed when an event is appended after
+// the given sequence number
+func (l *Log) Wait(after uint64) <-chan struct{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if uint64(len(l.events)) > after {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	return l.appended
+}
@@ -0,0 +1,194 @@
+package ledgerserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+)
+
+// appendEvent seals an event the way Server.SubmitEvent does
+func appendEvent(t *testing.T, l *Log, eventID string) (*pb.SealedEvent, bool) {
+	t.Helper()
+	se, created, err := l.Append(eventID, func(sequence uint64, previousHash string) *pb.SealedEvent {
+		se := &pb.SealedEvent{
+			SequenceNumber: sequence,
+			PreviousHash:   previousHash,
+			EventId:        eventID,
+			TenantId:       "tenant-1",
+			HashVersion:    pb.CurrentHashVersion,
+		}
+		se.EventHash = pb.EventHash(se)
+		return se
+	})
+	if err != nil {
+		t.Fatalf("Append(%s): %v", eventID, err)
+	}
+	return se, created
+}
+
+// checkChain fails the test unless the log holds a linked chain of n events
+func checkChain(t *testing.T, l *Log, n uint64) {
+	t.Helper()
+	if got := l.Latest(); got != n {
+		t.Fatalf("Latest = %d, want %d", got, n)
+	}
+	previousHash := pb.GenesisHash
+	for seq := uint64(1); seq <= n; seq++ {
+		se, ok := l.Get(seq)
+		if !ok {
+			t.Fatalf("Get(%d) found nothing", seq)
+		}
+		if se.PreviousHash != previousHash || se.EventHash != pb.EventHash(se) {
+			t.Fatalf("event %d is not linked to the chain", seq)
+		}
+		previousHash = se.EventHash
+	}
+}
+
+func TestLogReopensWithChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.log")
+
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		appendEvent(t, l, fmt.Sprintf("event-%d", i))
+	}
+	l.Close()
+
+	reopened, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer reopened.Close()
+	checkChain(t, reopened, 3)
+
+	se, _ := appendEvent(t, reopened, "event-4")
+	if se.SequenceNumber != 4 {
+		t.Errorf("sequence after reopen = %d, want 4", se.SequenceNumber)
+	}
+	checkChain(t, reopened, 4)
+}
+
+func TestLogResealingReturnsOriginal(t *testing.T) {
+	l, err := OpenLog(filepath.Join(t.TempDir(), "ledger.log"))
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer l.Close()
+
+	first, created := appendEvent(t, l, "event-1")
+	if !created {
+		t.Fatal("first Append reported an existing event")
+	}
+	appendEvent(t, l, "event-2")
+
+	again, created := appendEvent(t, l, "event-1")
+	if created || again.SequenceNumber != first.SequenceNumber {
+		t.Errorf("resealing event-1 = sequence %d (created %v), want %d", again.SequenceNumber, created, first.SequenceNumber)
+	}
+	checkChain(t, l, 2)
+}
+
+func TestLogTruncatesTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.log")
+
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	appendEvent(t, l, "event-1")
+	appendEvent(t, l, "event-2")
+	l.Close()
+
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash in the middle of a write leaves a record without its newline
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"sequenceNumber":"3","eventId":"eve`)
+	f.Close()
+
+	reopened, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog after torn write: %v", err)
+	}
+	checkChain(t, reopened, 2)
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() != before.Size() {
+		t.Errorf("log size after recovery = %d, want %d", after.Size(), before.Size())
+	}
+
+	// The next record starts on a clean line and survives another restart
+	appendEvent(t, reopened, "event-3")
+	reopened.Close()
+
+	again, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer again.Close()
+	checkChain(t, again, 3)
+}
+
+func TestLogRejectsCorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.log")
+
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	appendEvent(t, l, "event-1")
+	l.Close()
+
+	// A complete but unreadable line is corruption, not a torn write
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not a record\n")
+	f.Close()
+
+	if _, err := OpenLog(path); err == nil {
+		t.Error("OpenLog with a corrupt record succeeded, want an error")
+	}
+}
+
+func TestLogRange(t *testing.T) {
+	l, err := OpenLog(filepath.Join(t.TempDir(), "ledger.log"))
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer l.Close()
+	for i := 1; i <= 5; i++ {
+		appendEvent(t, l, fmt.Sprintf("event-%d", i))
+	}
+
+	events, next, hasMore := l.Range(1, 2, "")
+	if len(events) != 2 || events[0].SequenceNumber != 2 || !hasMore || next != 3 {
+		t.Errorf("Range(1, 2) = %d events, next %d, more %v; want 2 from sequence 2, next 3, more true",
+			len(events), next, hasMore)
+	}
+
+	events, next, hasMore = l.Range(3, 10, "")
+	if len(events) != 2 || hasMore || next != 5 {
+		t.Errorf("Range(3, 10) = %d events, next %d, more %v; want 2, next 5, more false", len(events), next, hasMore)
+	}
+
+	if events, _, _ := l.Range(0, 10, "other-tenant"); len(events) != 0 {
+		t.Errorf("Range for another tenant = %d events, want 0", len(events))
+	}
+}
@@ -0,0 +1,192 @@
+// Package ledgerserver is a single-node reference implementation of the
+// ImmutableLedger gRPC service
+// It seals events onto a SHA-256 hash chain kept in a file-backed log, so the
+// Monolith Submitter and downstream consumers can run end-to-end without the
+// GKE ledger cluster. There is no replication: the node is always the leader
+package ledgerserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/veps-service-480701/monolith-submitter/pkg/ledger"
+)
+
+const (
+	defaultBatchSize = 100
+	maxBatchSize     = 1000
+)
+
+// Server implements pb.ImmutableLedgerServer on top of a Log
+type Server struct {
+	pb.UnimplementedImmutableLedgerServer
+
+	log       *Log
+	shardID   string
+	nodeID    string
+	startedAt time.Time
+}
+
+// New creates a ledger server for one shard
+func New(l *Log, shardID string, nodeID string) *Server {
+	return &Server{
+		log:       l,
+		shardID:   shardID,
+		nodeID:    nodeID,
+		startedAt: time.Now(),
+	}
+}
+
+// SubmitEvent seals a certified event with the next sequence number
+// Resubmitting an already sealed event ID returns the original seal
+func (s *Server) SubmitEvent(ctx context.Context, req *pb.CertifiedEvent) (*pb.SealedEvent, error) {
+	startTime := time.Now()
+
+	if req.GetEventId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id is required")
+	}
+	if req.GetVepsSignature() == "" {
+		return nil, status.Error(codes.InvalidArgument, "veps_signature is required")
+	}
+
+	sealed, created, err := s.log.Append(req.GetEventId(), func(sequence uint64, previousHash string) *pb.SealedEvent {
+		se := &pb.SealedEvent{
+			SequenceNumber:  sequence,
+			ShardId:         s.shardID,
+			SealedTimestamp: time.Now().UnixMilli(),
+			PreviousHash:    previousHash,
+			EventId:         req.GetEventId(),
+			TenantId:        req.GetTenantId(),
+			Type:            req.GetType(),
+			Source:          req.GetSource(),
+			Timestamp:       req.GetTimestamp(),
+			Actor:           req.GetActor(),
+			EvidenceJson:    req.GetEvidenceJson(),
+			VectorClockJson: req.GetVectorClockJson(),
+			BoundaryNode:    req.GetBoundaryNode(),
+			CorrelationId:   req.GetCorrelationId(),
+			Metadata:        req.GetMetadata(),
+			VepsSignature:   req.GetVepsSignature(),
+			VepsTimestamp:   req.GetVepsTimestamp(),
+			HashVersion:     pb.CurrentHashVersion,
+		}
+		se.EventHash = pb.EventHash(se)
+		se.CommitLatencyMs = time.Since(startTime).Milliseconds()
+		return se
+	})
+	if err != nil {
+		log.Printf("[Ledger] Failed to seal event %s: %v", req.GetEventId(), err)
+		return nil, status.Errorf(codes.Internal, "failed to seal event: %v", err)
+	}
+
+	if created {
+		log.Printf("[Ledger] Sealed event %s at sequence %d", sealed.EventId, sealed.SequenceNumber)
+	} else {
+		log.Printf("[Ledger] Event %s already sealed at sequence %d", sealed.EventId, sealed.SequenceNumber)
+	}
+
+	return sealed, nil
+}
+
+// StreamEvents sends sealed events after start_sequence in batches
+// With follow set the stream stays open and delivers new events as they are sealed
+func (s *Server) StreamEvents(req *pb.StreamEventsRequest, stream pb.ImmutableLedger_StreamEventsServer) error {
+	ctx := stream.Context()
+	batchSize := clampBatch(req.GetBatchSize())
+	cursor := req.GetStartSequence()
+
+	for {
+		events, next, hasMore := s.log.Range(cursor, batchSize, req.GetTenantId())
+		for _, se := range events {
+			if err := stream.Send(se); err != nil {
+				return err
+			}
+		}
+		cursor = next
+
+		if hasMore {
+			continue
+		}
+		if !req.GetFollow() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.log.Wait(cursor):
+		}
+	}
+}
+
+// GetEvents returns one batch of sealed events after start_sequence
+func (s *Server) GetEvents(ctx context.Context, req *pb.GetEventsRequest) (*pb.GetEventsResponse, error) {
+	events, _, hasMore := s.log.Range(req.GetStartSequence(), clampBatch(req.GetLimit()), req.GetTenantId())
+
+	return &pb.GetEventsResponse{
+		Events:         events,
+		LatestSequence: s.log.Latest(),
+		HasMore:        hasMore,
+	}, nil
+}
+
+// GetEvent returns a single sealed event
+func (s *Server) GetEvent(ctx context.Context, req *pb.GetEventRequest) (*pb.SealedEvent, error) {
+	se, ok := s.log.Get(req.GetSequenceNumber())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no event with sequence %d", req.GetSequenceNumber())
+	}
+	return se, nil
+}
+
+// GetEventHash returns the chain hash of a single sealed event
+func (s *Server) GetEventHash(ctx context.Context, req *pb.GetEventHashRequest) (*pb.GetEventHashResponse, error) {
+	se, ok := s.log.Get(req.GetSequenceNumber())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no event with sequence %d", req.GetSequenceNumber())
+	}
+	return &pb.GetEventHashResponse{EventHash: se.EventHash}, nil
+}
+
+// GetShardInfo describes this shard
+func (s *Server) GetShardInfo(ctx context.Context, req *pb.GetShardInfoRequest) (*pb.ShardInfo, error) {
+	latest := s.log.Latest()
+
+	return &pb.ShardInfo{
+		ShardId:        s.shardID,
+		LatestSequence: latest,
+		EventCount:     int64(latest),
+		TenantCount:    int64(s.log.TenantCount()),
+		LeaderNode:     s.nodeID,
+		UptimeSeconds:  int64(time.Since(s.startedAt).Seconds()),
+	}, nil
+}
+
+// HealthCheck reports the shard as healthy whenever the server is serving
+func (s *Server) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	startTime := time.Now()
+
+	return &pb.HealthCheckResponse{
+		Status:         "healthy",
+		ShardId:        s.shardID,
+		IsLeader:       true,
+		LatestSequence: s.log.Latest(),
+		ResponseTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// clampBatch applies the default and maximum batch sizes from ledger.proto
+func clampBatch(n uint32) int {
+	switch {
+	case n == 0:
+		return defaultBatchSize
+	case n > maxBatchSize:
+		return maxBatchSize
+	default:
+		return int(n)
+	}
+}
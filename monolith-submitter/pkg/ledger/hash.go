@@ -0,0 +1,96 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// GenesisHash is the previous_hash of the first event on a shard
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Hash versions, recorded in SealedEvent.hash_version so events sealed before
+// a layout change still verify
+const (
+	// HashVersionOriginal covers the event without its VEPS certification
+	HashVersionOriginal uint32 = 0
+	// HashVersionCertified adds veps_signature, veps_timestamp and hash_version
+	HashVersionCertified uint32 = 1
+
+	// CurrentHashVersion is the layout newly sealed events are hashed with
+	CurrentHashVersion = HashVersionCertified
+)
+
+// hashedFields are the SealedEvent fields covered by event_hash
+// commit_latency_ms is measured after the hash is taken and is left out
+type hashedFields struct {
+	SequenceNumber  uint64            `json:"sequence_number"`
+	ShardID         string            `json:"shard_id"`
+	SealedTimestamp int64             `json:"sealed_timestamp"`
+	PreviousHash    string            `json:"previous_hash"`
+	EventID         string            `json:"event_id"`
+	TenantID        string            `json:"tenant_id"`
+	Type            string            `json:"type"`
+	Source          string            `json:"source"`
+	Timestamp       int64             `json:"timestamp"`
+	ActorID         string            `json:"actor_id"`
+	ActorName       string            `json:"actor_name"`
+	ActorType       string            `json:"actor_type"`
+	EvidenceJSON    []byte            `json:"evidence_json"`
+	VectorClockJSON []byte            `json:"vector_clock_json"`
+	BoundaryNode    string            `json:"boundary_node"`
+	CorrelationID   string            `json:"correlation_id"`
+	Metadata        map[string]string `json:"metadata"`
+}
+
+// certifiedHashedFields is the HashVersionCertified layout; the embedded
+// fields are encoded first, in the same order as the original layout
+type certifiedHashedFields struct {
+	hashedFields
+	VepsSignature string `json:"veps_signature"`
+	VepsTimestamp int64  `json:"veps_timestamp"`
+	HashVersion   uint32 `json:"hash_version"`
+}
+
+// EventHash computes the SHA-256 chain hash of a sealed event using the
+// layout of its hash_version
+// The hash covers the previous event's hash, so any change to an earlier
+// event breaks every link after it
+func EventHash(se *SealedEvent) string {
+	fields := hashedFields{
+		SequenceNumber:  se.GetSequenceNumber(),
+		ShardID:         se.GetShardId(),
+		SealedTimestamp: se.GetSealedTimestamp(),
+		PreviousHash:    se.GetPreviousHash(),
+		EventID:         se.GetEventId(),
+		TenantID:        se.GetTenantId(),
+		Type:            se.GetType(),
+		Source:          se.GetSource(),
+		Timestamp:       se.GetTimestamp(),
+		ActorID:         se.GetActor().GetId(),
+		ActorName:       se.GetActor().GetName(),
+		ActorType:       se.GetActor().GetType(),
+		EvidenceJSON:    se.GetEvidenceJson(),
+		VectorClockJSON: se.GetVectorClockJson(),
+		BoundaryNode:    se.GetBoundaryNode(),
+		CorrelationID:   se.GetCorrelationId(),
+		Metadata:        se.GetMetadata(),
+	}
+
+	// Struct fields marshal in declaration order and map keys are sorted,
+	// so the encoding is deterministic
+	var data []byte
+	if se.GetHashVersion() == HashVersionOriginal {
+		data, _ = json.Marshal(fields)
+	} else {
+		data, _ = json.Marshal(certifiedHashedFields{
+			hashedFields:  fields,
+			VepsSignature: se.GetVepsSignature(),
+			VepsTimestamp: se.GetVepsTimestamp(),
+			HashVersion:   se.GetHashVersion(),
+		})
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: ledger.proto
+
+package ledger
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ImmutableLedger_SubmitEvent_FullMethodName  = "/ledger.ImmutableLedger/SubmitEvent"
+	ImmutableLedger_StreamEvents_FullMethodName = "/ledger.ImmutableLedger/StreamEvents"
+	ImmutableLedger_GetEvents_FullMethodName    = "/ledger.ImmutableLedger/GetEvents"
+	ImmutableLedger_GetEvent_FullMethodName     = "/ledger.ImmutableLedger/GetEvent"
+	ImmutableLedger_GetEventHash_FullMethodName = "/ledger.ImmutableLedger/GetEventHash"
+	ImmutableLedger_GetShardInfo_FullMethodName = "/ledger.ImmutableLedger/GetShardInfo"
+	ImmutableLedger_HealthCheck_FullMethodName  = "/ledger.ImmutableLedger/HealthCheck"
+)
+
+// ImmutableLedgerClient is the client API for ImmutableLedger service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ImmutableLedger service - accepts certified events from VEPS and streams to SRS Workers
+type ImmutableLedgerClient interface {
+	SubmitEvent(ctx context.Context, in *CertifiedEvent, opts ...grpc.CallOption) (*SealedEvent, error)
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SealedEvent], error)
+	GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error)
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*SealedEvent, error)
+	GetEventHash(ctx context.Context, in *GetEventHashRequest, opts ...grpc.CallOption) (*GetEventHashResponse, error)
+	GetShardInfo(ctx context.Context, in *GetShardInfoRequest, opts ...grpc.CallOption) (*ShardInfo, error)
+	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+}
+
+type immutableLedgerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImmutableLedgerClient(cc grpc.ClientConnInterface) ImmutableLedgerClient {
+	return &immutableLedgerClient{cc}
+}
+
+func (c *immutableLedgerClient) SubmitEvent(ctx context.Context, in *CertifiedEvent, opts ...grpc.CallOption) (*SealedEvent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SealedEvent)
+	err := c.cc.Invoke(ctx, ImmutableLedger_SubmitEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immutableLedgerClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SealedEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ImmutableLedger_ServiceDesc.Streams[0], ImmutableLedger_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, SealedEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ImmutableLedger_StreamEventsClient = grpc.ServerStreamingClient[SealedEvent]
+
+func (c *immutableLedgerClient) GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventsResponse)
+	err := c.cc.Invoke(ctx, ImmutableLedger_GetEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immutableLedgerClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*SealedEvent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SealedEvent)
+	err := c.cc.Invoke(ctx, ImmutableLedger_GetEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immutableLedgerClient) GetEventHash(ctx context.Context, in *GetEventHashRequest, opts ...grpc.CallOption) (*GetEventHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventHashResponse)
+	err := c.cc.Invoke(ctx, ImmutableLedger_GetEventHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immutableLedgerClient) GetShardInfo(ctx context.Context, in *GetShardInfoRequest, opts ...grpc.CallOption) (*ShardInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShardInfo)
+	err := c.cc.Invoke(ctx, ImmutableLedger_GetShardInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immutableLedgerClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthCheckResponse)
+	err := c.cc.Invoke(ctx, ImmutableLedger_HealthCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImmutableLedgerServer is the server API for ImmutableLedger service.
+// All implementations must embed UnimplementedImmutableLedgerServer
+// for forward compatibility.
+//
+// ImmutableLedger service - accepts certified events from VEPS and streams to SRS Workers
+type ImmutableLedgerServer interface {
+	SubmitEvent(context.Context, *CertifiedEvent) (*SealedEvent, error)
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[SealedEvent]) error
+	GetEvents(context.Context, *GetEventsRequest) (*GetEventsResponse, error)
+	GetEvent(context.Context, *GetEventRequest) (*SealedEvent, error)
+	GetEventHash(context.Context, *GetEventHashRequest) (*GetEventHashResponse, error)
+	GetShardInfo(context.Context, *GetShardInfoRequest) (*ShardInfo, error)
+	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	mustEmbedUnimplementedImmutableLedgerServer()
+}
+
+// UnimplementedImmutableLedgerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedImmutableLedgerServer struct{}
+
+func (UnimplementedImmutableLedgerServer) SubmitEvent(context.Context, *CertifiedEvent) (*SealedEvent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitEvent not implemented")
+}
+func (UnimplementedImmutableLedgerServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[SealedEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedImmutableLedgerServer) GetEvents(context.Context, *GetEventsRequest) (*GetEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvents not implemented")
+}
+func (UnimplementedImmutableLedgerServer) GetEvent(context.Context, *GetEventRequest) (*SealedEvent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
+func (UnimplementedImmutableLedgerServer) GetEventHash(context.Context, *GetEventHashRequest) (*GetEventHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHash not implemented")
+}
+func (UnimplementedImmutableLedgerServer) GetShardInfo(context.Context, *GetShardInfoRequest) (*ShardInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShardInfo not implemented")
+}
+func (UnimplementedImmutableLedgerServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+func (UnimplementedImmutableLedgerServer) mustEmbedUnimplementedImmutableLedgerServer() {}
+func (UnimplementedImmutableLedgerServer) testEmbeddedByValue()                         {}
+
+// UnsafeImmutableLedgerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImmutableLedgerServer will
+// result in compilation errors.
+type UnsafeImmutableLedgerServer interface {
+	mustEmbedUnimplementedImmutableLedgerServer()
+}
+
+func RegisterImmutableLedgerServer(s grpc.ServiceRegistrar, srv ImmutableLedgerServer) {
+	// If the following call pancis, it indicates UnimplementedImmutableLedgerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ImmutableLedger_ServiceDesc, srv)
+}
+
+func _ImmutableLedger_SubmitEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CertifiedEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).SubmitEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_SubmitEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).SubmitEvent(ctx, req.(*CertifiedEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmutableLedger_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImmutableLedgerServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, SealedEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ImmutableLedger_StreamEventsServer = grpc.ServerStreamingServer[SealedEvent]
+
+func _ImmutableLedger_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_GetEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).GetEvents(ctx, req.(*GetEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmutableLedger_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmutableLedger_GetEventHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).GetEventHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_GetEventHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).GetEventHash(ctx, req.(*GetEventHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmutableLedger_GetShardInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShardInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).GetShardInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_GetShardInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).GetShardInfo(ctx, req.(*GetShardInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmutableLedger_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmutableLedgerServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImmutableLedger_HealthCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmutableLedgerServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ImmutableLedger_ServiceDesc is the grpc.ServiceDesc for ImmutableLedger service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ImmutableLedger_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ledger.ImmutableLedger",
+	HandlerType: (*ImmutableLedgerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitEvent",
+			Handler:    _ImmutableLedger_SubmitEvent_Handler,
+		},
+		{
+			MethodName: "GetEvents",
+			Handler:    _ImmutableLedger_GetEvents_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _ImmutableLedger_GetEvent_Handler,
+		},
+		{
+			MethodName: "GetEventHash",
+			Handler:    _ImmutableLedger_GetEventHash_Handler,
+		},
+		{
+			MethodName: "GetShardInfo",
+			Handler:    _ImmutableLedger_GetShardInfo_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _ImmutableLedger_HealthCheck_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _ImmutableLedger_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ledger.proto",
+}
@@ -3,37 +3,16 @@ package models
 import (
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
-// Event represents a normalized event from VEPS
-type Event struct {
-	ID          uuid.UUID              `json:"id"`
-	Type        string                 `json:"type"`
-	Source      string                 `json:"source"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Actor       Actor                  `json:"actor"`
-	Evidence    map[string]interface{} `json:"evidence"`
-	VectorClock map[string]int64       `json:"vector_clock"`
-	Metadata    EventMetadata          `json:"metadata"`
-}
-
-// Actor represents the entity performing the action
-type Actor struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
-}
-
-// EventMetadata contains system-level metadata
-type EventMetadata struct {
-	ReceivedAt    time.Time `json:"received_at"`
-	ProcessedAt   time.Time `json:"processed_at"`
-	BoundaryNode  string    `json:"boundary_node"`
-	CorrelationID string    `json:"correlation_id"`
-	RetryCount    int       `json:"retry_count"`
-	SchemaVersion string    `json:"schema_version"`
-}
+// Event and its parts are the canonical types from veps-common
+type (
+	Event         = model.Event
+	Actor         = model.Actor
+	VectorClock   = model.VectorClock
+	EventMetadata = model.EventMetadata
+)
 
 // SubmitRequest represents a request to submit a certified event
 type SubmitRequest struct {
@@ -52,3 +31,33 @@ type SubmitResponse struct {
 	Message         string    `json:"message,omitempty"`
 	Error           string    `json:"error,omitempty"`
 }
+
+// SealedEvent is an event read back from ImmutableLedger with its sealing metadata
+type SealedEvent struct {
+	SequenceNumber  uint64    `json:"sequence_number"`
+	ShardID         string    `json:"shard_id"`
+	TenantID        string    `json:"tenant_id"`
+	EventHash       string    `json:"event_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	SealedTimestamp time.Time `json:"sealed_timestamp"`
+	CommitLatencyMS int64     `json:"commit_latency_ms"`
+	Event           Event     `json:"event"`
+}
+
+// VerifyReport is the result of checking a range of the ledger's hash chain
+type VerifyReport struct {
+	From         uint64      `json:"from"`
+	To           uint64      `json:"to"`
+	Checked      uint64      `json:"checked"`
+	LastVerified uint64      `json:"last_verified"`       // highest sequence number checked and valid; from-1 if none
+	NextFrom     uint64      `json:"next_from,omitempty"` // set when the range was cut at the page limit
+	Valid        bool        `json:"valid"`
+	BrokenLink   *BrokenLink `json:"broken_link,omitempty"`
+}
+
+// BrokenLink is the first event that failed verification
+type BrokenLink struct {
+	SequenceNumber uint64 `json:"sequence_number"`
+	EventID        string `json:"event_id,omitempty"`
+	Reason         string `json:"reason"`
+}
@@ -38,6 +38,9 @@ func main() {
 
 	log.Println("[Main] Database store initialized successfully")
 
+	// Expired idempotency keys of the Boundary Adapter are purged in the background
+	st.StartIngestKeyPurge()
+
 	// Initialize HTTP handler
 	h := handler.New(st)
 
@@ -3,6 +3,12 @@ module github.com/veps-service-480701/rdb-updater
 go 1.22.2
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	github.com/veps-service-480701/veps-common v0.0.0
 )
+
+require google.golang.org/protobuf v1.33.0 // indirect
+
+replace github.com/veps-service-480701/veps-common => ../veps-common
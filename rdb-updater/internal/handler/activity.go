@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultActivityBucket is the bucket width of an activity query
+	defaultActivityBucket = time.Minute
+	// maxActivityRange caps how far back an activity query may reach
+	maxActivityRange = 31 * 24 * time.Hour
+)
+
+// GetActivity handles GET /activity (used by Veto Service for velocity limits)
+//
+// It counts an actor's events since a point in time, optionally of one type,
+// and sums an evidence field over them, per bucket and in total
+func (h *Handler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	actorID := query.Get("actor_id")
+	if actorID == "" {
+		h.writeError(w, http.StatusBadRequest, "actor_id parameter is required")
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339, query.Get("since"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "since parameter must be an RFC3339 timestamp")
+		return
+	}
+	if time.Since(since) > maxActivityRange {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("since must be within %s", maxActivityRange))
+		return
+	}
+
+	bucket := defaultActivityBucket
+	if raw := query.Get("bucket"); raw != "" {
+		bucket, err = time.ParseDuration(raw)
+		if err != nil || bucket < time.Second || bucket%time.Second != 0 {
+			h.writeError(w, http.StatusBadRequest, "bucket must be a whole number of seconds, e.g. 60s or 1m")
+			return
+		}
+	}
+
+	activity, err := h.store.GetActivity(r.Context(), actorID, query.Get("type"), query.Get("field"), since, bucket)
+	if err != nil {
+		log.Printf("[Handler] Failed to get activity for actor %s: %v", actorID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get activity: %v", err))
+		return
+	}
+
+	duration := time.Since(startTime)
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d events", activity.Count),
+		Timestamp: time.Now().UTC(),
+		Duration:  duration.String(),
+		Data:      activity,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+const (
+	// defaultActorLimit is the page size of an actor listing
+	defaultActorLimit = 100
+	// maxActorLimit caps the page size of an actor listing
+	maxActorLimit = 1000
+)
+
+// Actors handles /actors
+//
+// GET with id looks up one actor (also matching type, if given); without id
+// it lists actors by type and status. POST registers an actor; registering
+// an actor that already exists with the same type returns it unchanged
+func (h *Handler) Actors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Get("id") != "" {
+			h.getActor(w, r)
+		} else {
+			h.listActors(w, r)
+		}
+	case http.MethodPost:
+		h.registerActor(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET and POST methods are allowed")
+	}
+}
+
+// getActor looks up one actor by ID and optionally type
+func (h *Handler) getActor(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	actorType := r.URL.Query().Get("type")
+
+	actor, err := h.store.GetActor(r.Context(), id)
+	if errors.Is(err, eventdb.ErrActorNotFound) {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("actor %s is not registered", id))
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] Failed to look up actor %s: %v", id, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to look up actor: %v", err))
+		return
+	}
+	if actorType != "" && actor.Type != actorType {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("actor %s is not registered as type %s", id, actorType))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   "Actor retrieved successfully",
+		Timestamp: time.Now().UTC(),
+		Data:      actor,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// listActors lists actors by type and status
+func (h *Handler) listActors(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	if status != "" && status != eventdb.ActorActive && status != eventdb.ActorSuspended {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown status %q (expected: active or suspended)", status))
+		return
+	}
+
+	limit := defaultActorLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			h.writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, maxActorLimit)
+	}
+
+	actors, err := h.store.ListActors(r.Context(), query.Get("type"), status, limit)
+	if err != nil {
+		log.Printf("[Handler] Failed to list actors: %v", err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list actors: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d actors", len(actors)),
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"count":  len(actors),
+			"actors": actors,
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// registerActor adds an actor to the registry
+func (h *Handler) registerActor(w http.ResponseWriter, r *http.Request) {
+	var req models.RegisterActorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	if req.ID == "" {
+		h.writeError(w, http.StatusBadRequest, "actor id is required")
+		return
+	}
+	if req.RegisteredBy == "" {
+		req.RegisteredBy = "api"
+	}
+
+	actor, created, err := h.store.RegisterActor(r.Context(), req)
+	if err != nil {
+		log.Printf("[Handler] Failed to register actor %s: %v", req.ID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to register actor: %v", err))
+		return
+	}
+
+	if !created && actor.Type != req.Type {
+		h.writeError(w, http.StatusConflict, fmt.Sprintf("actor %s is already registered as type %q", req.ID, actor.Type))
+		return
+	}
+
+	status := http.StatusCreated
+	message := "Actor registered"
+	if !created {
+		status = http.StatusOK
+		message = "Actor already registered"
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+		Data:      actor,
+	}
+	h.writeJSON(w, status, response)
+}
+
+// SuspendActor handles POST /actors/suspend
+// The Veto Service rejects events from a suspended actor
+func (h *Handler) SuspendActor(w http.ResponseWriter, r *http.Request) {
+	h.setActorStatus(w, r, true)
+}
+
+// ReinstateActor handles POST /actors/reinstate
+func (h *Handler) ReinstateActor(w http.ResponseWriter, r *http.Request) {
+	h.setActorStatus(w, r, false)
+}
+
+// setActorStatus suspends or reinstates an actor
+func (h *Handler) setActorStatus(w http.ResponseWriter, r *http.Request, suspend bool) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	var req models.ActorStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	if req.ID == "" {
+		h.writeError(w, http.StatusBadRequest, "actor id is required")
+		return
+	}
+
+	var (
+		actor   *models.ActorRecord
+		err     error
+		message string
+	)
+	if suspend {
+		actor, err = h.store.SuspendActor(r.Context(), req.ID, req.Reason)
+		message = "Actor suspended"
+	} else {
+		actor, err = h.store.ReinstateActor(r.Context(), req.ID)
+		message = "Actor reinstated"
+	}
+
+	if errors.Is(err, eventdb.ErrActorNotFound) {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("actor %s is not registered", req.ID))
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] Failed to update actor %s: %v", req.ID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update actor: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+		Data:      actor,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
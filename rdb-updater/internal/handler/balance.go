@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+// GetBalance handles GET /balance?actor_id=
+// With event_id, the response also says whether that event is already
+// reflected in the balance
+func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	actorID := r.URL.Query().Get("actor_id")
+	if actorID == "" {
+		h.writeError(w, http.StatusBadRequest, "actor_id parameter is required")
+		return
+	}
+
+	balance, err := h.store.GetBalance(r.Context(), actorID, r.URL.Query().Get("event_id"))
+	if err != nil {
+		log.Printf("[Handler] Failed to get balance for actor %s: %v", actorID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get balance: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   "Balance retrieved successfully",
+		Timestamp: time.Now().UTC(),
+		Data:      balance,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// Bounds of a hold's lifetime
+const (
+	defaultHoldTTL = 15 * time.Minute
+	maxHoldTTL     = time.Hour
+)
+
+// HoldBalance handles POST /balance/hold (used by Veto Service)
+//
+// It reserves an accepted withdrawal's amount until the withdrawal is sealed,
+// which moves the balance and drops the hold. A withdrawal that is not sealed
+// has its hold released by the Boundary Adapter, or left to expire. 422 means
+// the amount is not available
+func (h *Handler) HoldBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	var req models.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if req.TTLSeconds == 0 {
+		ttl = defaultHoldTTL
+	}
+
+	switch {
+	case req.EventID == "":
+		h.writeError(w, http.StatusBadRequest, "event_id is required")
+		return
+	case req.ActorID == "":
+		h.writeError(w, http.StatusBadRequest, "actor_id is required")
+		return
+	case req.Amount <= 0:
+		h.writeError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	case ttl <= 0 || ttl > maxHoldTTL:
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("ttl_seconds must be between 1 and %d", int(maxHoldTTL.Seconds())))
+		return
+	}
+
+	balance, placed, err := h.store.PlaceHold(r.Context(), req, ttl)
+
+	status := http.StatusOK
+	response := Response{
+		Success:   err == nil,
+		EventID:   req.EventID,
+		Timestamp: time.Now().UTC(),
+		Data:      balance,
+	}
+	switch {
+	case errors.Is(err, eventdb.ErrInsufficientFunds):
+		status = http.StatusUnprocessableEntity
+		response.Error = fmt.Sprintf("insufficient funds: available %.2f, withdrawal %.2f", balance.Available, req.Amount)
+	case err != nil:
+		log.Printf("[Handler] Failed to hold balance of %s for event %s: %v", req.ActorID, req.EventID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to hold balance: %v", err))
+		return
+	case placed:
+		response.Message = "Funds held"
+	default:
+		response.Message = "Event already holds or has moved the balance"
+	}
+
+	h.writeJSON(w, status, response)
+}
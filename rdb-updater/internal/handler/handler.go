@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/veps-service-480701/rdb-updater/internal/store"
 	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
 )
 
 // Handler manages HTTP requests for the RDB Updater
@@ -80,6 +82,11 @@ func (h *Handler) UpdateContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := contextUpdate.Event.UpgradeSchema(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Set processed timestamp
 	contextUpdate.Event.Metadata.ProcessedAt = time.Now().UTC()
 
@@ -91,6 +98,14 @@ func (h *Handler) UpdateContext(w http.ResponseWriter, r *http.Request) {
 	switch contextUpdate.Operation {
 	case "upsert", "": // Default to upsert
 		err = h.store.UpsertEvent(r.Context(), contextUpdate.Event)
+	case "seal":
+		if contextUpdate.Seal == nil || contextUpdate.Seal.SequenceNumber == 0 {
+			h.writeError(w, http.StatusBadRequest, "seal with a sequence number is required")
+			return
+		}
+		err = h.store.AssignSequence(r.Context(), contextUpdate.Event.ID.String(), *contextUpdate.Seal)
+	case "release":
+		err = h.store.ReleaseHold(r.Context(), contextUpdate.Event.ID.String())
 	default:
 		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported operation: %s", contextUpdate.Operation))
 		return
@@ -98,7 +113,15 @@ func (h *Handler) UpdateContext(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Printf("[Handler] Failed to update context for event %s: %v", contextUpdate.Event.ID, err)
-		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update context: %v", err))
+
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, eventdb.ErrNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, eventdb.ErrSequenceConflict):
+			statusCode = http.StatusConflict
+		}
+		h.writeError(w, statusCode, fmt.Sprintf("failed to update context: %v", err))
 		return
 	}
 
@@ -172,8 +195,10 @@ func (h *Handler) CheckCausality(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse vector clock from request
+	// origin_node is the boundary node that ingested the event; its own entry is not checked
 	var request struct {
 		VectorClock models.VectorClock `json:"vector_clock"`
+		OriginNode  string             `json:"origin_node"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -183,7 +208,7 @@ func (h *Handler) CheckCausality(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	// Check causality
-	satisfied, missing, err := h.store.CheckVectorClockCausality(r.Context(), request.VectorClock)
+	satisfied, missing, err := h.store.CheckVectorClockCausality(r.Context(), request.VectorClock, request.OriginNode)
 	if err != nil {
 		log.Printf("[Handler] Failed to check causality: %v", err)
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("causality check failed: %v", err))
@@ -192,14 +217,21 @@ func (h *Handler) CheckCausality(w http.ResponseWriter, r *http.Request) {
 
 	duration := time.Since(startTime)
 
+	// missing_nodes is kept for callers that predate per-dependency reporting
+	missingNodes := make([]string, 0, len(missing))
+	for _, dep := range missing {
+		missingNodes = append(missingNodes, dep.Node)
+	}
+
 	response := Response{
 		Success:   satisfied,
 		Message:   fmt.Sprintf("Causality check complete: satisfied=%v", satisfied),
 		Timestamp: time.Now().UTC(),
 		Duration:  duration.String(),
 		Data: map[string]interface{}{
-			"satisfied":    satisfied,
-			"missing_nodes": missing,
+			"satisfied":     satisfied,
+			"missing":       missing,
+			"missing_nodes": missingNodes,
 		},
 	}
 
@@ -212,6 +244,39 @@ func (h *Handler) CheckCausality(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, statusCode, response)
 }
 
+// GetClock returns the highest vector clock counter stored for a boundary node
+func (h *Handler) GetClock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	nodeID := r.URL.Query().Get("node")
+	if nodeID == "" {
+		h.writeError(w, http.StatusBadRequest, "node parameter is required")
+		return
+	}
+
+	counter, err := h.store.GetNodeCounter(r.Context(), nodeID)
+	if err != nil {
+		log.Printf("[Handler] Failed to get clock for node %s: %v", nodeID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get clock: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   "Clock retrieved successfully",
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"node":    nodeID,
+			"counter": counter,
+		},
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
 // writeJSON writes a JSON response
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -237,4 +302,13 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/update", h.UpdateContext)
 	mux.HandleFunc("/event", h.GetEvent)
 	mux.HandleFunc("/causality", h.CheckCausality)
+	mux.HandleFunc("/clock", h.GetClock)
+	mux.HandleFunc("/activity", h.GetActivity)
+	mux.HandleFunc("/balance", h.GetBalance)
+	mux.HandleFunc("/balance/hold", h.HoldBalance)
+	mux.HandleFunc("/actors", h.Actors)
+	mux.HandleFunc("/actors/suspend", h.SuspendActor)
+	mux.HandleFunc("/actors/reinstate", h.ReinstateActor)
+	mux.HandleFunc("/ingest-keys/claim", h.ClaimIngestKey)
+	mux.HandleFunc("/ingest-keys/update", h.UpdateIngestKey)
 }
\ No newline at end of file
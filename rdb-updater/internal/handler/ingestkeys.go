@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+// ClaimIngestKey handles POST /ingest-keys/claim (used by Boundary Adapter)
+//
+// The Boundary Adapter claims a request's idempotency key before processing
+// it; the outcome says whether the request is new, a retry, or a duplicate
+// to answer from the stored result. Keys are shared by every instance
+func (h *Handler) ClaimIngestKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	var claim models.IngestKeyClaim
+	if err := json.NewDecoder(r.Body).Decode(&claim); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	switch {
+	case claim.Key == "":
+		h.writeError(w, http.StatusBadRequest, "key is required")
+		return
+	case claim.Fingerprint == "":
+		h.writeError(w, http.StatusBadRequest, "fingerprint is required")
+		return
+	case claim.WindowSeconds <= 0:
+		h.writeError(w, http.StatusBadRequest, "window_seconds must be positive")
+		return
+	case claim.StaleAfterSeconds <= 0:
+		h.writeError(w, http.StatusBadRequest, "stale_after_seconds must be positive")
+		return
+	}
+
+	result, err := h.store.ClaimIngestKey(r.Context(), claim)
+	if err != nil {
+		log.Printf("[Handler] Failed to claim ingest key %s: %v", claim.Key, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to claim ingest key: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   "Ingest key claimed",
+		Timestamp: time.Now().UTC(),
+		Data:      result,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// UpdateIngestKey handles POST /ingest-keys/update (used by Boundary Adapter)
+// 404 means the key is not in a state the operation applies to
+func (h *Handler) UpdateIngestKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	var update models.IngestKeyUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	switch {
+	case update.Key == "":
+		h.writeError(w, http.StatusBadRequest, "key is required")
+		return
+	case update.Operation == "event" && len(update.Event) == 0:
+		h.writeError(w, http.StatusBadRequest, "event is required")
+		return
+	case update.Operation == "complete" && (update.Status == 0 || len(update.Response) == 0):
+		h.writeError(w, http.StatusBadRequest, "status and response are required")
+		return
+	case update.Operation != "event" && update.Operation != "complete" && update.Operation != "fail" && update.Operation != "release":
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown operation %q (expected: event, complete, fail or release)", update.Operation))
+		return
+	}
+
+	err := h.store.UpdateIngestKey(r.Context(), update)
+	if errors.Is(err, eventdb.ErrIngestKeyNotFound) {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("ingest key %s is unknown or no longer in flight", update.Key))
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] Failed to %s ingest key %s: %v", update.Operation, update.Key, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update ingest key: %v", err))
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Ingest key updated (%s)", update.Operation),
+		Timestamp: time.Now().UTC(),
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
@@ -8,13 +8,17 @@ import (
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
 )
 
 // Store handles PostgreSQL database operations
+// Reads and writes of the events table go through the shared eventdb package
 type Store struct {
-	db *sql.DB
+	db     *sql.DB
+	events *eventdb.Store
 }
 
 // Config holds database configuration
@@ -59,218 +63,438 @@ func New(config Config) (*Store, error) {
 
 	log.Println("[Store] Successfully connected to PostgreSQL")
 
-	store := &Store{db: db}
+	// Bring the shared events schema up to date
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer migrateCancel()
 
-	// Initialize schema
-	if err := store.InitSchema(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	if err := eventdb.Migrate(migrateCtx, db); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
-	return store, nil
+	log.Printf("[Store] Schema at version %d", eventdb.LatestVersion())
+
+	return &Store{db: db, events: eventdb.New(db)}, nil
 }
 
-// InitSchema creates the necessary tables if they don't exist
-func (s *Store) InitSchema(ctx context.Context) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS events (
-		id UUID PRIMARY KEY,
-		type VARCHAR(255) NOT NULL,
-		source VARCHAR(255) NOT NULL,
-		timestamp TIMESTAMPTZ NOT NULL,
-		actor_id VARCHAR(255) NOT NULL,
-		actor_name VARCHAR(255) NOT NULL,
-		actor_type VARCHAR(100),
-		evidence JSONB NOT NULL,
-		vector_clock JSONB NOT NULL,
-		boundary_node VARCHAR(255),
-		correlation_id UUID,
-		received_at TIMESTAMPTZ NOT NULL,
-		processed_at TIMESTAMPTZ,
-		schema_version VARCHAR(50),
-		created_at TIMESTAMPTZ DEFAULT NOW()
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp DESC);
-	CREATE INDEX IF NOT EXISTS idx_events_actor_id ON events(actor_id);
-	CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
-	CREATE INDEX IF NOT EXISTS idx_events_source ON events(source);
-	CREATE INDEX IF NOT EXISTS idx_events_correlation_id ON events(correlation_id);
-	CREATE INDEX IF NOT EXISTS idx_events_vector_clock ON events USING GIN(vector_clock);
-	`
+// UpsertEvent inserts or updates an event in the database
+func (s *Store) UpsertEvent(ctx context.Context, event models.Event) error {
+	processedAt := time.Now().UTC()
+	if !event.Metadata.ProcessedAt.IsZero() {
+		processedAt = event.Metadata.ProcessedAt
+	}
+
+	rec := eventdb.Record{
+		ID:            event.ID.String(),
+		Type:          event.Type,
+		Source:        event.Source,
+		Timestamp:     event.Timestamp,
+		ActorID:       event.Actor.ID,
+		ActorName:     event.Actor.Name,
+		ActorType:     event.Actor.Type,
+		Evidence:      event.Evidence,
+		VectorClock:   event.VectorClock,
+		BoundaryNode:  event.Metadata.BoundaryNode,
+		CorrelationID: event.Metadata.CorrelationID,
+		ReceivedAt:    event.Metadata.ReceivedAt,
+		ProcessedAt:   processedAt,
+		SchemaVersion: event.Metadata.SchemaVersion,
+	}
 
-	if _, err := s.db.ExecContext(ctx, schema); err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
+	if err := s.events.Upsert(ctx, rec); err != nil {
+		return err
 	}
 
-	log.Println("[Store] Schema initialized successfully")
+	log.Printf("[Store] Event %s upserted successfully", event.ID)
 	return nil
 }
 
-// UpsertEvent inserts or updates an event in the database
-func (s *Store) UpsertEvent(ctx context.Context, event models.Event) error {
-	// Marshal JSONB fields
-	evidenceJSON, err := json.Marshal(event.Evidence)
+// AssignSequence records the ledger seal for a stored event
+// A sealed payment or withdrawal moves its actor's balance, and the
+// withdrawal's hold is dropped. Returns eventdb.ErrNotFound if the event has
+// not been upserted yet and eventdb.ErrSequenceConflict if it was sealed with
+// another sequence number
+func (s *Store) AssignSequence(ctx context.Context, eventID string, seal models.Seal) error {
+	balance, err := s.events.AssignSequence(ctx, eventID, eventdb.Seal{
+		SequenceNumber: seal.SequenceNumber,
+		EventHash:      seal.EventHash,
+		PreviousHash:   seal.PreviousHash,
+		SealedAt:       seal.SealedTimestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[Store] Event %s sealed with sequence %d", eventID, seal.SequenceNumber)
+	if balance != nil {
+		log.Printf("[Store] Balance of %s is %.2f after event %s (version %d)", balance.ActorID, balance.Balance, eventID, balance.Version)
+	}
+	return nil
+}
+
+// ReleaseHold drops the hold of an event that was not sealed
+// Releasing an event without a hold is a no-op
+func (s *Store) ReleaseHold(ctx context.Context, eventID string) error {
+	released, err := s.events.ReleaseHold(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if released {
+		log.Printf("[Store] Released the balance hold of event %s", eventID)
+	}
+	return nil
+}
+
+// GetEventByID retrieves an event by its ID
+func (s *Store) GetEventByID(ctx context.Context, id string) (*models.StoredEvent, error) {
+	rec, err := s.events.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to marshal evidence: %w", err)
+		return nil, err
 	}
 
-	vectorClockJSON, err := json.Marshal(event.VectorClock)
+	eventID, err := uuid.Parse(rec.ID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal vector clock: %w", err)
+		return nil, fmt.Errorf("invalid stored event ID %q: %w", rec.ID, err)
+	}
+
+	event := &models.StoredEvent{}
+	event.Event = models.Event{
+		ID:        eventID,
+		Type:      rec.Type,
+		Source:    rec.Source,
+		Timestamp: rec.Timestamp,
+		Actor: models.Actor{
+			ID:   rec.ActorID,
+			Name: rec.ActorName,
+			Type: rec.ActorType,
+		},
+		Evidence:    rec.Evidence,
+		VectorClock: rec.VectorClock,
+		Metadata: models.EventMetadata{
+			ReceivedAt:    rec.ReceivedAt,
+			ProcessedAt:   rec.ProcessedAt,
+			BoundaryNode:  rec.BoundaryNode,
+			CorrelationID: rec.CorrelationID,
+			SchemaVersion: rec.SchemaVersion,
+		},
+	}
+
+	if rec.Seal != nil {
+		event.Seal = &models.Seal{
+			SequenceNumber:  rec.Seal.SequenceNumber,
+			EventHash:       rec.Seal.EventHash,
+			PreviousHash:    rec.Seal.PreviousHash,
+			SealedTimestamp: rec.Seal.SealedAt,
+		}
+	}
+
+	return event, nil
+}
+
+// CheckVectorClockCausality checks that the direct predecessor of every vector clock entry exists
+// For each entry (node, counter) with counter > 1, an event from that node carrying
+// counter-1 must already be stored. The entry of originNode, the node that ingested
+// the event, is skipped: that node orders its own events, and stores them
+// asynchronously, so its predecessor may not have arrived yet. Returns the
+// dependencies that are missing.
+func (s *Store) CheckVectorClockCausality(ctx context.Context, vc models.VectorClock, originNode string) (bool, []models.MissingDependency, error) {
+	missing := []models.MissingDependency{}
+	if len(vc) == 0 {
+		return true, missing, nil
+	}
+
+	vcJSON, err := json.Marshal(vc)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal vector clock: %w", err)
 	}
 
 	query := `
-		INSERT INTO events (
-			id, type, source, timestamp, 
-			actor_id, actor_name, actor_type,
-			evidence, vector_clock,
-			boundary_node, correlation_id, 
-			received_at, processed_at, schema_version
-		) VALUES (
-			$1, $2, $3, $4, 
-			$5, $6, $7,
-			$8, $9,
-			$10, $11, 
-			$12, $13, $14
+		SELECT deps.node, deps.counter - 1
+		FROM (
+			SELECT key AS node, value::bigint AS counter
+			FROM jsonb_each_text($1::jsonb)
+		) AS deps
+		WHERE deps.counter > 1
+		AND deps.node <> $2
+		AND NOT EXISTS (
+			SELECT 1
+			FROM events e
+			WHERE e.boundary_node = deps.node
+			AND (e.vector_clock->>deps.node)::bigint = deps.counter - 1
 		)
-		ON CONFLICT (id) DO UPDATE SET
-			type = EXCLUDED.type,
-			source = EXCLUDED.source,
-			timestamp = EXCLUDED.timestamp,
-			actor_id = EXCLUDED.actor_id,
-			actor_name = EXCLUDED.actor_name,
-			actor_type = EXCLUDED.actor_type,
-			evidence = EXCLUDED.evidence,
-			vector_clock = EXCLUDED.vector_clock,
-			boundary_node = EXCLUDED.boundary_node,
-			correlation_id = EXCLUDED.correlation_id,
-			processed_at = EXCLUDED.processed_at
+		ORDER BY deps.node
 	`
 
-	processedAt := time.Now().UTC()
-	if !event.Metadata.ProcessedAt.IsZero() {
-		processedAt = event.Metadata.ProcessedAt
+	rows, err := s.db.QueryContext(ctx, query, vcJSON, originNode)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to check causality: %w", err)
 	}
+	defer rows.Close()
 
-	_, err = s.db.ExecContext(
-		ctx,
-		query,
-		event.ID,
-		event.Type,
-		event.Source,
-		event.Timestamp,
-		event.Actor.ID,
-		event.Actor.Name,
-		event.Actor.Type,
-		evidenceJSON,
-		vectorClockJSON,
-		event.Metadata.BoundaryNode,
-		event.Metadata.CorrelationID,
-		event.Metadata.ReceivedAt,
-		processedAt,
-		event.Metadata.SchemaVersion,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to upsert event: %w", err)
+	for rows.Next() {
+		var dep models.MissingDependency
+		if err := rows.Scan(&dep.Node, &dep.ExpectedCounter); err != nil {
+			return false, nil, fmt.Errorf("failed to scan missing dependency: %w", err)
+		}
+		missing = append(missing, dep)
+	}
+	if err := rows.Err(); err != nil {
+		return false, nil, fmt.Errorf("failed to check causality: %w", err)
 	}
 
-	log.Printf("[Store] Event %s upserted successfully", event.ID)
-	return nil
+	return len(missing) == 0, missing, nil
 }
 
-// GetEventByID retrieves an event by its ID
-func (s *Store) GetEventByID(ctx context.Context, id string) (*models.Event, error) {
+// GetNodeCounter returns the highest vector clock counter stored for a boundary node
+// Boundary adapters use it to recover their logical clock at startup
+func (s *Store) GetNodeCounter(ctx context.Context, nodeID string) (int64, error) {
 	query := `
-		SELECT id, type, source, timestamp,
-			actor_id, actor_name, actor_type,
-			evidence, vector_clock,
-			boundary_node, correlation_id,
-			received_at, processed_at, schema_version
+		SELECT COALESCE(MAX((vector_clock->>$1)::bigint), 0)
 		FROM events
-		WHERE id = $1
+		WHERE boundary_node = $1
 	`
 
-	var event models.Event
-	var evidenceJSON, vectorClockJSON []byte
-	var processedAt sql.NullTime
-	var actorType, boundaryNode, schemaVersion sql.NullString
-
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&event.ID,
-		&event.Type,
-		&event.Source,
-		&event.Timestamp,
-		&event.Actor.ID,
-		&event.Actor.Name,
-		&actorType,
-		&evidenceJSON,
-		&vectorClockJSON,
-		&boundaryNode,
-		&event.Metadata.CorrelationID,
-		&event.Metadata.ReceivedAt,
-		&processedAt,
-		&schemaVersion,
-	)
+	var counter int64
+	if err := s.db.QueryRowContext(ctx, query, nodeID).Scan(&counter); err != nil {
+		return 0, fmt.Errorf("failed to query node counter: %w", err)
+	}
+
+	return counter, nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("event not found")
+// GetActivity counts an actor's sealed events of a type since a point in time and
+// sums an evidence field over them, bucket by bucket
+func (s *Store) GetActivity(ctx context.Context, actorID, eventType, field string, since time.Time, bucket time.Duration) (*models.Activity, error) {
+	activity, err := s.events.Aggregate(ctx, eventdb.ActivityFilter{
+		ActorID: actorID,
+		Type:    eventType,
+		Since:   since,
+		Field:   field,
+		Bucket:  bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.Activity{
+		ActorID: actorID,
+		Type:    eventType,
+		Field:   field,
+		Since:   since,
+		Bucket:  bucket.String(),
+		Count:   activity.Count,
+		Sum:     activity.Sum,
+		Buckets: make([]models.ActivityBucket, 0, len(activity.Buckets)),
+	}
+	if !activity.LastEventAt.IsZero() {
+		lastEventAt := activity.LastEventAt
+		result.LastEventAt = &lastEventAt
 	}
+	for _, b := range activity.Buckets {
+		result.Buckets = append(result.Buckets, models.ActivityBucket{Start: b.Start, Count: b.Count, Sum: b.Sum})
+	}
+	return result, nil
+}
+
+// GetBalance retrieves an actor's balance
+// If eventID is set, the result also reports whether that event was applied
+func (s *Store) GetBalance(ctx context.Context, actorID, eventID string) (*models.Balance, error) {
+	rec, err := s.events.GetBalance(ctx, actorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query event: %w", err)
+		return nil, err
 	}
 
-	// Unmarshal JSONB fields
-	if err := json.Unmarshal(evidenceJSON, &event.Evidence); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal evidence: %w", err)
+	balance := toBalance(rec)
+	if eventID != "" {
+		applied, err := s.events.HasBalanceEntry(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		balance.EventApplied = &applied
 	}
+	return balance, nil
+}
 
-	if err := json.Unmarshal(vectorClockJSON, &event.VectorClock); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal vector clock: %w", err)
+// PlaceHold reserves a withdrawal's amount against an actor's balance
+// Returns eventdb.ErrInsufficientFunds, with the current balance, if the
+// amount is not available. Holding an event twice, or holding a sealed one,
+// succeeds without changing the balance
+func (s *Store) PlaceHold(ctx context.Context, req models.HoldRequest, ttl time.Duration) (*models.Balance, bool, error) {
+	rec, placed, err := s.events.PlaceHold(ctx, eventdb.BalanceHold{
+		EventID: req.EventID,
+		ActorID: req.ActorID,
+		Amount:  req.Amount,
+		TTL:     ttl,
+	})
+	if rec == nil {
+		return nil, false, err
+	}
+	if placed {
+		log.Printf("[Store] Held %.2f of %s for event %s, %.2f available", req.Amount, req.ActorID, req.EventID, rec.Available())
 	}
+	return toBalance(rec), placed, err
+}
 
-	// Handle nullable fields
-	if actorType.Valid {
-		event.Actor.Type = actorType.String
+// toBalance converts a projection row to its API form
+func toBalance(rec *eventdb.Balance) *models.Balance {
+	balance := &models.Balance{
+		ActorID:   rec.ActorID,
+		Balance:   rec.Balance,
+		Held:      rec.Held,
+		Available: rec.Available(),
+		Version:   rec.Version,
 	}
-	if boundaryNode.Valid {
-		event.Metadata.BoundaryNode = boundaryNode.String
+	if !rec.UpdatedAt.IsZero() {
+		updatedAt := rec.UpdatedAt
+		balance.UpdatedAt = &updatedAt
 	}
-	if processedAt.Valid {
-		event.Metadata.ProcessedAt = processedAt.Time
+	return balance
+}
+
+// RegisterActor adds an actor to the registry
+// Returns the stored actor and whether this call created it
+func (s *Store) RegisterActor(ctx context.Context, req models.RegisterActorRequest) (*models.ActorRecord, bool, error) {
+	rec, created, err := s.events.RegisterActor(ctx, eventdb.ActorRecord{
+		ID:           req.ID,
+		Type:         req.Type,
+		Name:         req.Name,
+		RegisteredBy: req.RegisteredBy,
+	})
+	if err != nil {
+		return nil, false, err
 	}
-	if schemaVersion.Valid {
-		event.Metadata.SchemaVersion = schemaVersion.String
+
+	if created {
+		log.Printf("[Store] Actor %s registered (type: %s, by: %s)", rec.ID, rec.Type, rec.RegisteredBy)
 	}
+	return toActor(rec), created, nil
+}
 
-	return &event, nil
+// GetActor retrieves an actor from the registry
+// Returns eventdb.ErrActorNotFound if the actor is not registered
+func (s *Store) GetActor(ctx context.Context, id string) (*models.ActorRecord, error) {
+	rec, err := s.events.GetActor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toActor(rec), nil
 }
 
-// CheckVectorClockCausality checks if all events in the vector clock exist
-// Returns true if all causal dependencies are satisfied
-func (s *Store) CheckVectorClockCausality(ctx context.Context, vc models.VectorClock) (bool, []string, error) {
-	missing := []string{}
-
-	for nodeID, timestamp := range vc {
-		query := `
-			SELECT COUNT(*) 
-			FROM events 
-			WHERE boundary_node = $1 
-			AND (vector_clock->>$1)::bigint <= $2
-		`
-
-		var count int
-		err := s.db.QueryRowContext(ctx, query, nodeID, timestamp).Scan(&count)
-		if err != nil {
-			return false, nil, fmt.Errorf("failed to check causality: %w", err)
-		}
+// ListActors returns registered actors, optionally by type and status
+func (s *Store) ListActors(ctx context.Context, actorType, status string, limit int) ([]models.ActorRecord, error) {
+	recs, err := s.events.ListActors(ctx, eventdb.ActorFilter{Type: actorType, Status: status, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
 
-		if count == 0 {
-			missing = append(missing, nodeID)
-		}
+	actors := make([]models.ActorRecord, 0, len(recs))
+	for i := range recs {
+		actors = append(actors, *toActor(&recs[i]))
 	}
+	return actors, nil
+}
 
-	return len(missing) == 0, missing, nil
+// SuspendActor suspends an actor so the Veto Service rejects its events
+func (s *Store) SuspendActor(ctx context.Context, id, reason string) (*models.ActorRecord, error) {
+	rec, err := s.events.SuspendActor(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Store] Actor %s suspended: %s", id, reason)
+	return toActor(rec), nil
+}
+
+// ReinstateActor makes a suspended actor active again
+func (s *Store) ReinstateActor(ctx context.Context, id string) (*models.ActorRecord, error) {
+	rec, err := s.events.ReinstateActor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Store] Actor %s reinstated", id)
+	return toActor(rec), nil
+}
+
+// toActor converts a registry row to its API form
+func toActor(rec *eventdb.ActorRecord) *models.ActorRecord {
+	actor := &models.ActorRecord{
+		ID:              rec.ID,
+		Type:            rec.Type,
+		Name:            rec.Name,
+		Status:          rec.Status,
+		RegisteredBy:    rec.RegisteredBy,
+		RegisteredAt:    rec.RegisteredAt,
+		SuspendedReason: rec.SuspendedReason,
+		UpdatedAt:       rec.UpdatedAt,
+	}
+	if !rec.SuspendedAt.IsZero() {
+		suspendedAt := rec.SuspendedAt
+		actor.SuspendedAt = &suspendedAt
+	}
+	return actor
+}
+
+// ClaimIngestKey claims an idempotency key of the Boundary Adapter
+func (s *Store) ClaimIngestKey(ctx context.Context, claim models.IngestKeyClaim) (*models.IngestKeyClaimResult, error) {
+	outcome, rec, err := s.events.ClaimIngestKey(ctx, claim.Key, claim.Fingerprint,
+		time.Duration(claim.WindowSeconds)*time.Second, time.Duration(claim.StaleAfterSeconds)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == eventdb.ClaimRetry {
+		log.Printf("[Store] Ingest key %s handed out again after a failed or abandoned attempt", claim.Key)
+	}
+
+	return &models.IngestKeyClaimResult{
+		Outcome: outcome,
+		Key: &models.IngestKey{
+			Key:         rec.Key,
+			Fingerprint: rec.Fingerprint,
+			State:       rec.State,
+			Event:       rec.Event,
+			Status:      rec.Status,
+			Response:    rec.Response,
+			CreatedAt:   rec.CreatedAt,
+			ExpiresAt:   rec.ExpiresAt,
+		},
+	}, nil
+}
+
+// UpdateIngestKey records the progress of a claimed idempotency key
+// Returns eventdb.ErrIngestKeyNotFound if the key is not in a state the
+// operation applies to
+func (s *Store) UpdateIngestKey(ctx context.Context, update models.IngestKeyUpdate) error {
+	switch update.Operation {
+	case "event":
+		return s.events.SetIngestEvent(ctx, update.Key, update.Event)
+	case "complete":
+		return s.events.CompleteIngestKey(ctx, update.Key, update.Status, update.Response)
+	case "fail":
+		return s.events.FailIngestKey(ctx, update.Key)
+	case "release":
+		return s.events.ReleaseIngestKey(ctx, update.Key)
+	}
+	return fmt.Errorf("unknown ingest key operation %q", update.Operation)
+}
+
+// StartIngestKeyPurge deletes expired idempotency keys every ten minutes
+func (s *Store) StartIngestKeyPurge() {
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			deleted, err := s.events.DeleteExpiredIngestKeys(ctx)
+			cancel()
+
+			if err != nil {
+				log.Printf("[Store] Warning: failed to purge ingest keys: %v", err)
+			} else if deleted > 0 {
+				log.Printf("[Store] Purged %d expired ingest keys", deleted)
+			}
+		}
+	}()
 }
 
 // Close closes the database connection
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/veps-service-480701/rdb-updater/pkg/models"
+	"github.com/veps-service-480701/veps-common/eventdb"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Store{db: db, events: eventdb.New(db)}, mock
+}
+
+func TestCheckVectorClockCausalityReportsMissingPredecessors(t *testing.T) {
+	s, mock := newMockStore(t)
+
+	vc := models.VectorClock{"node-a": 7, "node-b": 3, "node-c": 1}
+	vcJSON, _ := json.Marshal(vc)
+
+	// The whole clock is checked in one query, which skips the origin node
+	mock.ExpectQuery(`FROM jsonb_each_text\(\$1::jsonb\)`).
+		WithArgs(vcJSON, "node-a").
+		WillReturnRows(sqlmock.NewRows([]string{"node", "counter"}).AddRow("node-b", 2))
+
+	ok, missing, err := s.CheckVectorClockCausality(context.Background(), vc, "node-a")
+	if err != nil {
+		t.Fatalf("CheckVectorClockCausality: %v", err)
+	}
+	if ok {
+		t.Error("ok = true, want false with a missing predecessor")
+	}
+	want := []models.MissingDependency{{Node: "node-b", ExpectedCounter: 2}}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %+v, want %+v", missing, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckVectorClockCausalityPassesWithAllPredecessors(t *testing.T) {
+	s, mock := newMockStore(t)
+
+	mock.ExpectQuery(`FROM jsonb_each_text`).
+		WillReturnRows(sqlmock.NewRows([]string{"node", "counter"}))
+
+	ok, missing, err := s.CheckVectorClockCausality(context.Background(), models.VectorClock{"node-b": 3}, "node-a")
+	if err != nil {
+		t.Fatalf("CheckVectorClockCausality: %v", err)
+	}
+	if !ok || len(missing) != 0 {
+		t.Errorf("CheckVectorClockCausality = %v, %+v, want true with nothing missing", ok, missing)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckVectorClockCausalityEmptyClock(t *testing.T) {
+	s, mock := newMockStore(t)
+
+	// No query is expected: an empty clock has no predecessors
+	ok, missing, err := s.CheckVectorClockCausality(context.Background(), models.VectorClock{}, "node-a")
+	if err != nil || !ok || len(missing) != 0 {
+		t.Errorf("CheckVectorClockCausality(empty) = %v, %+v, %v, want true", ok, missing, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,47 +1,152 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
-// Event represents a normalized event from VEPS Boundary Adapter
-type Event struct {
-	ID          uuid.UUID              `json:"id"`
-	Type        string                 `json:"type"`
-	Source      string                 `json:"source"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Actor       Actor                  `json:"actor"`
-	Evidence    map[string]interface{} `json:"evidence"`
-	VectorClock VectorClock            `json:"vector_clock"`
-	Metadata    EventMetadata          `json:"metadata,omitempty"`
-}
+// The event types are defined once in veps-common so every service reads
+// and writes the same format
+type (
+	Event         = model.Event
+	Actor         = model.Actor
+	VectorClock   = model.VectorClock
+	EventMetadata = model.EventMetadata
+)
 
-// Actor represents the entity that triggered the event
-type Actor struct {
-	ID       string            `json:"id"`
-	Name     string            `json:"name"`
-	Type     string            `json:"type,omitempty"`
-	Metadata map[string]string `json:"metadata,omitempty"`
+// StoredEvent is an event as read back from the events table
+type StoredEvent struct {
+	Event
+	Seal *Seal `json:"seal,omitempty"` // set once the ledger has sealed the event
 }
 
-// VectorClock tracks causality for distributed event ordering
-type VectorClock map[string]int64
-
-// EventMetadata contains additional context
-type EventMetadata struct {
-	ReceivedAt    time.Time `json:"received_at"`
-	ProcessedAt   time.Time `json:"processed_at,omitempty"`
-	BoundaryNode  string    `json:"boundary_node"`
-	CorrelationID string    `json:"correlation_id"`
-	RetryCount    int       `json:"retry_count"`
-	SchemaVersion string    `json:"schema_version"`
+// Seal is the ImmutableLedger receipt recorded against a stored event
+type Seal struct {
+	SequenceNumber  uint64    `json:"sequence_number"`
+	EventHash       string    `json:"event_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	SealedTimestamp time.Time `json:"sealed_timestamp"`
 }
 
 // ContextUpdate represents the request from Boundary Adapter
 type ContextUpdate struct {
 	Event     Event  `json:"event"`
-	Operation string `json:"operation"` // "upsert", "append", etc.
-	Route     string `json:"route"`     // "rdb_updater"
-}
\ No newline at end of file
+	Operation string `json:"operation"`      // "upsert", "seal" or "release"
+	Route     string `json:"route"`          // "rdb_updater"
+	Seal      *Seal  `json:"seal,omitempty"` // required for "seal"
+}
+
+// MissingDependency identifies a causal predecessor that has not been stored yet
+type MissingDependency struct {
+	Node            string `json:"node"`
+	ExpectedCounter int64  `json:"expected_counter"`
+}
+
+// ActorRecord is an entry of the actor registry
+type ActorRecord struct {
+	ID              string     `json:"id"`
+	Type            string     `json:"type"`
+	Name            string     `json:"name,omitempty"`
+	Status          string     `json:"status"` // "active" or "suspended"
+	RegisteredBy    string     `json:"registered_by"`
+	RegisteredAt    time.Time  `json:"registered_at"`
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty"`
+	SuspendedReason string     `json:"suspended_reason,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// RegisterActorRequest registers an actor
+type RegisterActorRequest struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Name         string `json:"name,omitempty"`
+	RegisteredBy string `json:"registered_by,omitempty"` // defaults to "api"
+}
+
+// ActorStatusRequest suspends or reinstates an actor
+type ActorStatusRequest struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Activity is an actor's event count and total over a time range, used by
+// the Veto Service's velocity limits
+type Activity struct {
+	ActorID     string           `json:"actor_id"`
+	Type        string           `json:"type,omitempty"`  // empty when every event type is counted
+	Field       string           `json:"field,omitempty"` // summed evidence field
+	Since       time.Time        `json:"since"`
+	Bucket      string           `json:"bucket"`
+	Count       int64            `json:"count"`
+	Sum         float64          `json:"sum"`
+	LastEventAt *time.Time       `json:"last_event_at,omitempty"`
+	Buckets     []ActivityBucket `json:"buckets"`
+}
+
+// ActivityBucket is the part of an Activity that falls in one bucket
+type ActivityBucket struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+	Sum   float64   `json:"sum"`
+}
+
+// Balance is an actor's balance in the balance projection
+// Balance only moves when an event is sealed; Held is reserved by accepted
+// withdrawals awaiting their seal, and Available is what can still be withdrawn
+type Balance struct {
+	ActorID   string     `json:"actor_id"`
+	Balance   float64    `json:"balance"`
+	Held      float64    `json:"held"`
+	Available float64    `json:"available"`
+	Version   int64      `json:"version"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// EventApplied is set when the lookup names an event, and reports
+	// whether that event is already reflected in the balance
+	EventApplied *bool `json:"event_applied,omitempty"`
+}
+
+// HoldRequest reserves a withdrawal's amount against a balance until the
+// withdrawal is sealed, released, or the hold expires
+type HoldRequest struct {
+	EventID    string  `json:"event_id"`
+	ActorID    string  `json:"actor_id"`
+	Amount     float64 `json:"amount"`
+	TTLSeconds int     `json:"ttl_seconds,omitempty"` // 0 uses the default
+}
+
+// IngestKeyClaim claims an idempotency key of the Boundary Adapter
+type IngestKeyClaim struct {
+	Key               string `json:"key"`
+	Fingerprint       string `json:"fingerprint"`
+	WindowSeconds     int    `json:"window_seconds"`      // how long the key is kept
+	StaleAfterSeconds int    `json:"stale_after_seconds"` // when an in-flight claim counts as abandoned
+}
+
+// IngestKey is the record kept for one idempotency key
+type IngestKey struct {
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	State       string          `json:"state"`              // "in_flight", "done" or "failed"
+	Event       json.RawMessage `json:"event,omitempty"`    // set once the request is normalized
+	Status      int             `json:"status,omitempty"`   // HTTP status of the stored result
+	Response    json.RawMessage `json:"response,omitempty"` // body of the stored result
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// IngestKeyClaimResult tells the Boundary Adapter what to do with a request
+type IngestKeyClaimResult struct {
+	Outcome string     `json:"outcome"` // "new", "retry", "replay", "in_flight" or "mismatch"
+	Key     *IngestKey `json:"key"`
+}
+
+// IngestKeyUpdate records the progress of a claimed idempotency key
+type IngestKeyUpdate struct {
+	Key       string          `json:"key"`
+	Operation string          `json:"operation"`          // "event", "complete", "fail" or "release"
+	Event     json.RawMessage `json:"event,omitempty"`    // required for "event"
+	Status    int             `json:"status,omitempty"`   // required for "complete"
+	Response  json.RawMessage `json:"response,omitempty"` // required for "complete"
+}
@@ -0,0 +1,308 @@
+// Package durablelog is the append-only record log behind the services' local
+// write-ahead queues: the Boundary Adapter's outbox and the Data Fracture
+// Handler's write queue. Records are JSON lines fsynced on append;
+// acknowledgements go to a separate ack file, and the log is compacted down
+// to its pending records from time to time
+package durablelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// MaxRecordSize bounds a single log line, newline included
+const MaxRecordSize = 64 * 1024 * 1024
+
+// ErrRecordTooLarge is returned by Append for a record recovery could not read back
+var ErrRecordTooLarge = fmt.Errorf("record exceeds %d bytes", MaxRecordSize)
+
+// Log is a durable log of records of type R, kept in <name>.log and
+// <name>.ack. It is not safe for concurrent use; callers serialize access
+type Log[R any] struct {
+	logPath string
+	ackPath string
+	seq     func(*R) *uint64 // returns the record's sequence number field
+
+	logFile logFile
+	ackFile *os.File
+	size    int64 // offset of the end of the last complete record
+	broken  error // set when a failed append could not be rolled back
+	nextSeq uint64
+	acked   int // acknowledgements since the last compaction
+}
+
+// logFile is the open log file; tests substitute one whose writes fail
+type logFile interface {
+	io.WriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// Open opens (or creates) the log called name in dir and returns it with the
+// records that were not acknowledged, in append order. seq returns a pointer
+// to a record's sequence number, which Append assigns. The log starts compacted
+func Open[R any](dir, name string, seq func(*R) *uint64) (*Log[R], []*R, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	l := &Log[R]{
+		logPath: filepath.Join(dir, name+".log"),
+		ackPath: filepath.Join(dir, name+".ack"),
+		seq:     seq,
+		nextSeq: 1,
+	}
+
+	pending, err := l.recover()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := l.Compact(pending); err != nil {
+		return nil, nil, err
+	}
+
+	return l, pending, nil
+}
+
+// recover replays the log, skipping acknowledged records
+func (l *Log[R]) recover() ([]*R, error) {
+	acked := make(map[uint64]bool)
+
+	ackFile, err := os.Open(l.ackPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open ack file: %w", err)
+	}
+	if ackFile != nil {
+		scanner := bufio.NewScanner(ackFile)
+		for scanner.Scan() {
+			if seq, err := strconv.ParseUint(scanner.Text(), 10, 64); err == nil {
+				acked[seq] = true
+			}
+		}
+		ackFile.Close()
+	}
+
+	logFile, err := os.Open(l.logPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer logFile.Close()
+
+	var pending []*R
+	scanner := bufio.NewScanner(logFile)
+	scanner.Buffer(make([]byte, 64*1024), MaxRecordSize)
+	for scanner.Scan() {
+		record := new(R)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			// A torn write from a crash can only affect the last line
+			log.Printf("[DurableLog] Warning: skipping unreadable record in %s: %v", l.logPath, err)
+			continue
+		}
+
+		seq := *l.seq(record)
+		if seq >= l.nextSeq {
+			l.nextSeq = seq + 1
+		}
+		if acked[seq] {
+			continue
+		}
+		pending = append(pending, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return *l.seq(pending[i]) < *l.seq(pending[j])
+	})
+	return pending, nil
+}
+
+// Append assigns the record the next sequence number and writes it to the
+// log; it returns once the record is on disk
+// A record that fails to write is truncated away, so the next one starts on
+// a clean line
+func (l *Log[R]) Append(record *R) error {
+	if l.broken != nil {
+		return fmt.Errorf("log is unwritable after a failed append: %w", l.broken)
+	}
+
+	*l.seq(record) = l.nextSeq
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	line = append(line, '\n')
+	if len(line) > MaxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	if _, err := l.logFile.Write(line); err != nil {
+		return l.rollback(fmt.Errorf("failed to append to log: %w", err))
+	}
+	if err := l.logFile.Sync(); err != nil {
+		return l.rollback(fmt.Errorf("failed to sync log: %w", err))
+	}
+
+	l.size += int64(len(line))
+	l.nextSeq++
+	return nil
+}
+
+// rollback truncates a partly written record; if that fails too, further
+// appends are refused, since they would be glued onto the torn line
+func (l *Log[R]) rollback(cause error) error {
+	if err := l.logFile.Truncate(l.size); err != nil {
+		l.broken = errors.Join(cause, fmt.Errorf("rollback failed: %w", err))
+		log.Printf("[DurableLog] ERROR: %s: %v", l.logPath, l.broken)
+		return l.broken
+	}
+	return cause
+}
+
+// Ack records that a record is done with
+// Acks are not synced: losing one only causes the record to be redelivered
+func (l *Log[R]) Ack(seq uint64) error {
+	if _, err := fmt.Fprintf(l.ackFile, "%d\n", seq); err != nil {
+		return fmt.Errorf("failed to record ack for record %d: %w", seq, err)
+	}
+	l.acked++
+	return nil
+}
+
+// Acked returns how many records were acknowledged since the last compaction
+func (l *Log[R]) Acked() int {
+	return l.acked
+}
+
+// Compact rewrites the log with only the pending records and clears the ack file
+// The new files are written beside the current ones and renamed into place, so
+// on any error the log keeps appending to the files it already has open
+func (l *Log[R]) Compact(pending []*R) error {
+	// Opened for appending, so the handles keep working once renamed into place
+	newLog, err := os.OpenFile(l.logPath+".tmp", os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted log: %w", err)
+	}
+	newAck, err := os.OpenFile(l.ackPath+".tmp", os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		newLog.Close()
+		return fmt.Errorf("failed to create ack file: %w", err)
+	}
+
+	size, err := writeRecords(newLog, pending)
+	if err != nil {
+		newLog.Close()
+		newAck.Close()
+		return err
+	}
+
+	if err := os.Rename(l.logPath+".tmp", l.logPath); err != nil {
+		newLog.Close()
+		newAck.Close()
+		return fmt.Errorf("failed to replace log: %w", err)
+	}
+	if l.logFile != nil {
+		l.logFile.Close()
+	}
+	l.logFile = newLog
+	l.size = size
+	l.broken = nil
+
+	// The new log holds no acknowledged records, so the old acks are still
+	// correct for it if the ack file cannot be replaced
+	if err := os.Rename(l.ackPath+".tmp", l.ackPath); err != nil {
+		newAck.Close()
+		if l.ackFile != nil {
+			return fmt.Errorf("failed to replace ack file: %w", err)
+		}
+		if l.ackFile, err = os.OpenFile(l.ackPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644); err != nil {
+			l.logFile.Close()
+			return fmt.Errorf("failed to open ack file: %w", err)
+		}
+		return nil
+	}
+	if l.ackFile != nil {
+		l.ackFile.Close()
+	}
+	l.ackFile = newAck
+
+	l.acked = 0
+	return nil
+}
+
+// writeRecords writes records to a new log file, syncs it and returns its size
+func writeRecords[R any](file *os.File, records []*R) (int64, error) {
+	writer := bufio.NewWriter(file)
+	var size int64
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal record: %w", err)
+		}
+		n, _ := writer.Write(append(line, '\n'))
+		size += int64(n)
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write compacted log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync compacted log: %w", err)
+	}
+	return size, nil
+}
+
+// Close closes the log files; pending records stay on disk
+func (l *Log[R]) Close() error {
+	ackErr := l.ackFile.Close()
+	if err := l.logFile.Close(); err != nil {
+		return fmt.Errorf("failed to close log: %w", err)
+	}
+	return ackErr
+}
+
+// DeadLetters is an append-only JSON lines file for records that were given up on
+// It is not safe for concurrent use; callers serialize access
+type DeadLetters struct {
+	file *os.File
+}
+
+// OpenDeadLetters opens (or creates) a dead-letter file
+func OpenDeadLetters(path string) (*DeadLetters, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead-letter log: %w", err)
+	}
+	return &DeadLetters{file: file}, nil
+}
+
+// Write appends a record and syncs it
+func (d *DeadLetters) Write(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append to dead-letter log: %w", err)
+	}
+	return d.file.Sync()
+}
+
+// Close closes the dead-letter file
+func (d *DeadLetters) Close() error {
+	return d.file.Close()
+}
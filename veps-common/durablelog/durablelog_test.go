@@ -0,0 +1,245 @@
+package durablelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testRecord struct {
+	Seq  uint64 `json:"seq"`
+	Body string `json:"body"`
+}
+
+func openTestLog(t *testing.T, dir string) (*Log[testRecord], []*testRecord) {
+	t.Helper()
+	l, pending, err := Open(dir, "test", func(r *testRecord) *uint64 { return &r.Seq })
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return l, pending
+}
+
+func appendBody(t *testing.T, l *Log[testRecord], body string) *testRecord {
+	t.Helper()
+	record := &testRecord{Body: body}
+	if err := l.Append(record); err != nil {
+		t.Fatalf("Append(%s): %v", body, err)
+	}
+	return record
+}
+
+func bodies(records []*testRecord) []string {
+	out := []string{}
+	for _, r := range records {
+		out = append(out, r.Body)
+	}
+	return out
+}
+
+// tornFile writes only part of the next record, then fails, as a full disk would
+type tornFile struct {
+	*os.File
+	truncateErr error
+}
+
+func (f *tornFile) Write(p []byte) (int, error) {
+	n, _ := f.File.Write(p[:len(p)/2])
+	return n, errors.New("no space left on device")
+}
+
+func (f *tornFile) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return f.truncateErr
+	}
+	return f.File.Truncate(size)
+}
+
+func TestRecoverSkipsAcknowledgedRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	l, pending := openTestLog(t, dir)
+	if len(pending) != 0 {
+		t.Fatalf("new log has %d pending records", len(pending))
+	}
+	a := appendBody(t, l, "a")
+	appendBody(t, l, "b")
+	c := appendBody(t, l, "c")
+	if a.Seq != 1 || c.Seq != 3 {
+		t.Errorf("sequence numbers %d..%d, want 1..3", a.Seq, c.Seq)
+	}
+	if err := l.Ack(a.Seq); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := l.Ack(c.Seq); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	l.Close()
+
+	reopened, pending := openTestLog(t, dir)
+	defer reopened.Close()
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("pending after reopen = %v, want [b]", got)
+	}
+
+	// Sequence numbers continue past every record, acknowledged or not
+	if d := appendBody(t, reopened, "d"); d.Seq != 4 {
+		t.Errorf("next sequence = %d, want 4", d.Seq)
+	}
+}
+
+func TestCompactKeepsPendingRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	l, _ := openTestLog(t, dir)
+	a := appendBody(t, l, "a")
+	b := appendBody(t, l, "b")
+	l.Ack(a.Seq)
+	if l.Acked() != 1 {
+		t.Errorf("Acked = %d, want 1", l.Acked())
+	}
+
+	if err := l.Compact([]*testRecord{b}); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if l.Acked() != 0 {
+		t.Errorf("Acked after compaction = %d, want 0", l.Acked())
+	}
+	appendBody(t, l, "c")
+	l.Close()
+
+	reopened, pending := openTestLog(t, dir)
+	defer reopened.Close()
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("pending after compaction = %v, want [b c]", got)
+	}
+}
+
+func TestRecoverSkipsTornLastLine(t *testing.T) {
+	dir := t.TempDir()
+
+	l, _ := openTestLog(t, dir)
+	appendBody(t, l, "a")
+	l.Close()
+
+	// A crash mid-append leaves half a line at the end of the log
+	f, err := os.OpenFile(filepath.Join(dir, "test.log"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":2,"bo`)
+	f.Close()
+
+	reopened, pending := openTestLog(t, dir)
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("pending after torn write = %v, want [a]", got)
+	}
+
+	// The log is rewritten on open, so the next record is readable
+	appendBody(t, reopened, "b")
+	reopened.Close()
+
+	again, pending := openTestLog(t, dir)
+	defer again.Close()
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("pending = %v, want [a b]", got)
+	}
+}
+
+func TestAppendRollsBackTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	l, _ := openTestLog(t, dir)
+	appendBody(t, l, "a")
+
+	file := l.logFile.(*os.File)
+	l.logFile = &tornFile{File: file}
+	if err := l.Append(&testRecord{Body: "lost"}); err == nil {
+		t.Fatal("Append with a failing write succeeded")
+	}
+	l.logFile = file
+
+	// Reported durable, so it must survive a restart
+	c := appendBody(t, l, "c")
+	if c.Seq != 2 {
+		t.Errorf("sequence after a failed append = %d, want 2", c.Seq)
+	}
+	l.Close()
+
+	reopened, pending := openTestLog(t, dir)
+	defer reopened.Close()
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("pending after a torn append = %v, want [a c]", got)
+	}
+}
+
+func TestAppendRefusedAfterFailedRollback(t *testing.T) {
+	dir := t.TempDir()
+
+	l, _ := openTestLog(t, dir)
+	appendBody(t, l, "a")
+
+	file := l.logFile.(*os.File)
+	l.logFile = &tornFile{File: file, truncateErr: errors.New("read-only file system")}
+	if err := l.Append(&testRecord{Body: "lost"}); err == nil {
+		t.Fatal("Append with a failing write succeeded")
+	}
+	l.logFile = file
+
+	// The torn line is still there, so nothing may be appended after it
+	if err := l.Append(&testRecord{Body: "c"}); err == nil || !strings.Contains(err.Error(), "unwritable") {
+		t.Errorf("Append after a failed rollback = %v, want an unwritable log error", err)
+	}
+
+	// Compaction rewrites the log from the pending records and clears the error
+	if err := l.Compact(nil); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	appendBody(t, l, "d")
+	l.Close()
+}
+
+func TestAppendRejectsOversizeRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	l, _ := openTestLog(t, dir)
+	appendBody(t, l, "a")
+
+	err := l.Append(&testRecord{Body: strings.Repeat("x", MaxRecordSize)})
+	if !errors.Is(err, ErrRecordTooLarge) {
+		t.Fatalf("Append of an oversize record = %v, want %v", err, ErrRecordTooLarge)
+	}
+	appendBody(t, l, "b")
+	l.Close()
+
+	// Recovery reads everything that was accepted
+	reopened, pending := openTestLog(t, dir)
+	defer reopened.Close()
+	if got := bodies(pending); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("pending = %v, want [a b]", got)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+
+	dead, err := OpenDeadLetters(path)
+	if err != nil {
+		t.Fatalf("OpenDeadLetters: %v", err)
+	}
+	if err := dead.Write(testRecord{Seq: 7, Body: "gave up"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	dead.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "{\"seq\":7,\"body\":\"gave up\"}\n" {
+		t.Errorf("dead-letter file = %q", got)
+	}
+}
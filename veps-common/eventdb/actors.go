@@ -0,0 +1,188 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Actor statuses
+const (
+	ActorActive    = "active"
+	ActorSuspended = "suspended"
+)
+
+// ErrActorNotFound is returned when no actor matches the lookup
+var ErrActorNotFound = errors.New("actor not found")
+
+// ActorRecord is one row of the actors table
+type ActorRecord struct {
+	ID              string
+	Type            string
+	Name            string
+	Status          string
+	RegisteredBy    string // who registered the actor, e.g. "api", "auto" or "backfill"
+	RegisteredAt    time.Time
+	SuspendedAt     time.Time // zero unless suspended
+	SuspendedReason string
+	UpdatedAt       time.Time
+}
+
+// ActorFilter selects actors for ListActors
+// Zero-valued fields are ignored
+type ActorFilter struct {
+	Type   string
+	Status string
+	Limit  int
+}
+
+// actorColumns is the column list scanned by scanActor
+const actorColumns = `
+	id, type, name, status, registered_by, registered_at,
+	suspended_at, suspended_reason, updated_at
+`
+
+// RegisterActor inserts an actor unless one with the same ID exists
+// It returns the stored actor and whether it was created by this call, so
+// registration can be retried safely
+func (s *Store) RegisterActor(ctx context.Context, rec ActorRecord) (*ActorRecord, bool, error) {
+	query := `
+		INSERT INTO actors (id, type, name, status, registered_by)
+		VALUES ($1, $2, $3, '` + ActorActive + `', $4)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING ` + actorColumns
+
+	stored, err := scanActor(s.db.QueryRowContext(ctx, query, rec.ID, rec.Type, rec.Name, rec.RegisteredBy))
+	if err == nil {
+		return stored, true, nil
+	}
+	if !errors.Is(err, ErrActorNotFound) {
+		return nil, false, fmt.Errorf("failed to register actor: %w", err)
+	}
+
+	// The insert did nothing: the actor was already registered
+	stored, err = s.GetActor(ctx, rec.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, false, nil
+}
+
+// GetActor retrieves an actor by ID
+func (s *Store) GetActor(ctx context.Context, id string) (*ActorRecord, error) {
+	query := `SELECT ` + actorColumns + ` FROM actors WHERE id = $1`
+	return scanActor(s.db.QueryRowContext(ctx, query, id))
+}
+
+// ListActors returns actors matching the filter, most recently registered first
+func (s *Store) ListActors(ctx context.Context, f ActorFilter) ([]ActorRecord, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Type != "" {
+		conditions = append(conditions, "type = "+arg(f.Type))
+	}
+	if f.Status != "" {
+		conditions = append(conditions, "status = "+arg(f.Status))
+	}
+
+	query := `SELECT ` + actorColumns + ` FROM actors`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY registered_at DESC, id"
+	if f.Limit > 0 {
+		query += " LIMIT " + arg(f.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actors: %w", err)
+	}
+	defer rows.Close()
+
+	actors := []ActorRecord{}
+	for rows.Next() {
+		rec, err := scanActor(rows)
+		if err != nil {
+			return nil, err
+		}
+		actors = append(actors, *rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return actors, nil
+}
+
+// SuspendActor suspends an actor; suspending a suspended actor updates the reason
+func (s *Store) SuspendActor(ctx context.Context, id, reason string) (*ActorRecord, error) {
+	query := `
+		UPDATE actors SET
+			status = '` + ActorSuspended + `',
+			suspended_at = COALESCE(suspended_at, NOW()),
+			suspended_reason = $2,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + actorColumns
+
+	return scanActor(s.db.QueryRowContext(ctx, query, id, nullString(reason)))
+}
+
+// ReinstateActor makes a suspended actor active again
+func (s *Store) ReinstateActor(ctx context.Context, id string) (*ActorRecord, error) {
+	query := `
+		UPDATE actors SET
+			status = '` + ActorActive + `',
+			suspended_at = NULL,
+			suspended_reason = NULL,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + actorColumns
+
+	return scanActor(s.db.QueryRowContext(ctx, query, id))
+}
+
+// scanActor reads one row selected with actorColumns
+func scanActor(row scanner) (*ActorRecord, error) {
+	var (
+		rec             ActorRecord
+		suspendedAt     sql.NullTime
+		suspendedReason sql.NullString
+	)
+
+	err := row.Scan(
+		&rec.ID,
+		&rec.Type,
+		&rec.Name,
+		&rec.Status,
+		&rec.RegisteredBy,
+		&rec.RegisteredAt,
+		&suspendedAt,
+		&suspendedReason,
+		&rec.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrActorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan actor: %w", err)
+	}
+
+	if suspendedAt.Valid {
+		rec.SuspendedAt = suspendedAt.Time
+	}
+	rec.SuspendedReason = suspendedReason.String
+
+	return &rec, nil
+}
@@ -0,0 +1,89 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ActivityFilter selects an actor's events for Aggregate
+type ActivityFilter struct {
+	ActorID string
+	Type    string        // empty counts every event type
+	Since   time.Time     // inclusive
+	Field   string        // evidence field to sum; empty only counts
+	Bucket  time.Duration // bucket width; at least one second
+}
+
+// Activity is an actor's event count and total over a time range
+type Activity struct {
+	Count       int64
+	Sum         float64
+	LastEventAt time.Time // timestamp of the newest event; zero if there are none
+	Buckets     []ActivityBucket
+}
+
+// ActivityBucket is the part of an Activity that falls in one bucket
+type ActivityBucket struct {
+	Start time.Time
+	Count int64
+	Sum   float64
+}
+
+// Aggregate counts an actor's events since a point in time, and sums an
+// evidence field over them, in fixed-width buckets aligned to the Unix epoch
+// Only sealed events count: every event is stored while its veto is still
+// pending, and a vetoed one is never sealed. Evidence values that are not
+// numbers add nothing to the sum
+func (s *Store) Aggregate(ctx context.Context, f ActivityFilter) (*Activity, error) {
+	bucket := int64(f.Bucket / time.Second)
+	if bucket < 1 {
+		return nil, fmt.Errorf("bucket must be at least one second, got %s", f.Bucket)
+	}
+
+	query := `
+		SELECT
+			(floor(extract(epoch FROM timestamp) / $1::bigint) * $1::bigint)::bigint AS bucket_start,
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN jsonb_typeof(evidence->$2) = 'number'
+				THEN (evidence->>$2)::double precision END), 0),
+			MAX(timestamp)
+		FROM events
+		WHERE actor_id = $3 AND timestamp >= $4 AND ($5 = '' OR type = $5)
+			AND sequence_number IS NOT NULL
+		GROUP BY bucket_start
+		ORDER BY bucket_start
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, bucket, f.Field, f.ActorID, f.Since, f.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate events: %w", err)
+	}
+	defer rows.Close()
+
+	activity := &Activity{Buckets: []ActivityBucket{}}
+	for rows.Next() {
+		var (
+			start int64
+			b     ActivityBucket
+			last  sql.NullTime
+		)
+		if err := rows.Scan(&start, &b.Count, &b.Sum, &last); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket: %w", err)
+		}
+		b.Start = time.Unix(start, 0).UTC()
+
+		activity.Count += b.Count
+		activity.Sum += b.Sum
+		if last.Valid && last.Time.After(activity.LastEventAt) {
+			activity.LastEventAt = last.Time
+		}
+		activity.Buckets = append(activity.Buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return activity, nil
+}
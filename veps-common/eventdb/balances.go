@@ -0,0 +1,261 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Balance-moving event types
+const (
+	EventTypePayment    = "payment_processed" // credits the actor with evidence.amount
+	EventTypeWithdrawal = "withdrawal"        // debits the actor by evidence.amount
+)
+
+// ErrInsufficientFunds is returned when a hold would overdraw a balance
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Balance is one row of the balances table with the actor's active holds
+// Balance only moves when an event is sealed; Version counts the entries
+// applied. Held is reserved by accepted withdrawals that are not sealed yet
+type Balance struct {
+	ActorID   string
+	Balance   float64
+	Held      float64
+	Version   int64
+	UpdatedAt time.Time // zero for an actor without entries
+}
+
+// Available returns what the actor can still withdraw
+func (b *Balance) Available() float64 {
+	return b.Balance - b.Held
+}
+
+// BalanceEntry is one sealed event's effect on a balance
+type BalanceEntry struct {
+	EventID string
+	ActorID string
+	Amount  float64 // positive credits, negative debits
+}
+
+// BalanceHold reserves part of a balance for an accepted withdrawal until it
+// is sealed, released, or expires
+type BalanceHold struct {
+	EventID string
+	ActorID string
+	Amount  float64
+	TTL     time.Duration
+}
+
+// BalanceDelta returns how an event moves its actor's balance
+// ok is false for events that do not move balances, or carry no positive
+// numeric amount: a negative payment must not debit, nor a negative
+// withdrawal credit, an actor
+func BalanceDelta(eventType string, evidence map[string]interface{}) (delta float64, ok bool) {
+	amount, isNumber := evidence["amount"].(float64)
+	if !isNumber || amount <= 0 {
+		return 0, false
+	}
+	switch eventType {
+	case EventTypePayment:
+		return amount, true
+	case EventTypeWithdrawal:
+		return -amount, true
+	}
+	return 0, false
+}
+
+// GetBalance retrieves an actor's balance and active holds
+// An actor without entries has a zero balance at version 0
+func (s *Store) GetBalance(ctx context.Context, actorID string) (*Balance, error) {
+	balance, err := getBalance(ctx, s.db, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Held, err = heldAmount(ctx, s.db, actorID); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// HasBalanceEntry reports whether an event was already applied to a balance
+func (s *Store) HasBalanceEntry(ctx context.Context, eventID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM balance_entries WHERE event_id = $1)`
+	if err := s.db.QueryRowContext(ctx, query, eventID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up balance entry: %w", err)
+	}
+	return exists, nil
+}
+
+// PlaceHold reserves a withdrawal's amount against its actor's balance
+//
+// Holds of one actor are placed one at a time under a lock on the balance
+// row, so two withdrawals checked at once cannot both spend the same funds.
+// ErrInsufficientFunds is returned, with the balance, if the amount exceeds
+// what is available. Placing a hold again, or for an event that is already
+// sealed, changes nothing and returns placed == false
+func (s *Store) PlaceHold(ctx context.Context, hold BalanceHold) (*Balance, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO balances (actor_id) VALUES ($1)
+		ON CONFLICT (actor_id) DO NOTHING
+	`, hold.ActorID); err != nil {
+		return nil, false, fmt.Errorf("failed to create balance: %w", err)
+	}
+
+	balance, err := scanBalance(tx.QueryRowContext(ctx, `
+		SELECT actor_id, balance, version, updated_at FROM balances WHERE actor_id = $1
+		FOR UPDATE
+	`, hold.ActorID))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock balance: %w", err)
+	}
+
+	// Expired holds are dropped while the balance is locked
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM balance_holds WHERE actor_id = $1 AND expires_at <= NOW()
+	`, hold.ActorID); err != nil {
+		return nil, false, fmt.Errorf("failed to expire holds: %w", err)
+	}
+
+	var held, sealed bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM balance_holds WHERE event_id = $1),
+			EXISTS (SELECT 1 FROM balance_entries WHERE event_id = $1)
+	`, hold.EventID).Scan(&held, &sealed); err != nil {
+		return nil, false, fmt.Errorf("failed to look up hold: %w", err)
+	}
+
+	if balance.Held, err = heldAmount(ctx, tx, hold.ActorID); err != nil {
+		return nil, false, err
+	}
+	if held || sealed {
+		return balance, false, tx.Commit()
+	}
+
+	if hold.Amount > balance.Available() {
+		return balance, false, ErrInsufficientFunds
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO balance_holds (event_id, actor_id, amount, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+	`, hold.EventID, hold.ActorID, hold.Amount, hold.TTL.Seconds()); err != nil {
+		return nil, false, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit hold: %w", err)
+	}
+	balance.Held += hold.Amount
+	return balance, true, nil
+}
+
+// ReleaseHold drops an event's hold, e.g. when the event was not sealed
+// Returns false if the event held nothing
+func (s *Store) ReleaseHold(ctx context.Context, eventID string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM balance_holds WHERE event_id = $1`, eventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to release hold: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to release hold: %w", err)
+	}
+	return n > 0, nil
+}
+
+// applySeal moves a balance by a newly sealed event and drops the event's
+// hold, within the seal's transaction
+// An event is applied once, so a retried seal changes nothing and returns
+// applied == false
+func applySeal(ctx context.Context, tx *sql.Tx, entry BalanceEntry) (*Balance, bool, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM balance_holds WHERE event_id = $1`, entry.EventID); err != nil {
+		return nil, false, fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO balance_entries (event_id, actor_id, amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id) DO NOTHING
+	`, entry.EventID, entry.ActorID, entry.Amount)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record balance entry: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, false, fmt.Errorf("failed to record balance entry: %w", err)
+	} else if n == 0 {
+		return nil, false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO balances (actor_id) VALUES ($1)
+		ON CONFLICT (actor_id) DO NOTHING
+	`, entry.ActorID); err != nil {
+		return nil, false, fmt.Errorf("failed to create balance: %w", err)
+	}
+
+	// A sealed event is in the ledger, so it is applied even if the balance
+	// no longer covers it, e.g. after its hold expired
+	balance, err := scanBalance(tx.QueryRowContext(ctx, `
+		UPDATE balances SET
+			balance = balance + $2,
+			version = version + 1,
+			updated_at = NOW()
+		WHERE actor_id = $1
+		RETURNING actor_id, balance, version, updated_at
+	`, entry.ActorID, entry.Amount))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update balance: %w", err)
+	}
+	return balance, true, nil
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getBalance reads a balance, defaulting to zero for an unknown actor
+func getBalance(ctx context.Context, q querier, actorID string) (*Balance, error) {
+	balance, err := scanBalance(q.QueryRowContext(ctx, `
+		SELECT actor_id, balance, version, updated_at FROM balances WHERE actor_id = $1
+	`, actorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Balance{ActorID: actorID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return balance, nil
+}
+
+// heldAmount sums an actor's holds that have not expired
+func heldAmount(ctx context.Context, q querier, actorID string) (float64, error) {
+	var held float64
+	if err := q.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM balance_holds
+		WHERE actor_id = $1 AND expires_at > NOW()
+	`, actorID).Scan(&held); err != nil {
+		return 0, fmt.Errorf("failed to sum holds: %w", err)
+	}
+	return held, nil
+}
+
+// scanBalance reads one balances row; sql.ErrNoRows is passed through
+func scanBalance(row scanner) (*Balance, error) {
+	var b Balance
+	if err := row.Scan(&b.ActorID, &b.Balance, &b.Version, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
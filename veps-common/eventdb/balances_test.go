@@ -0,0 +1,31 @@
+package eventdb
+
+import "testing"
+
+func TestBalanceDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		evidence  map[string]interface{}
+		delta     float64
+		ok        bool
+	}{
+		{name: "payment credits", eventType: EventTypePayment, evidence: map[string]interface{}{"amount": 25.0}, delta: 25, ok: true},
+		{name: "withdrawal debits", eventType: EventTypeWithdrawal, evidence: map[string]interface{}{"amount": 25.0}, delta: -25, ok: true},
+		{name: "negative payment", eventType: EventTypePayment, evidence: map[string]interface{}{"amount": -25.0}},
+		{name: "negative withdrawal", eventType: EventTypeWithdrawal, evidence: map[string]interface{}{"amount": -25.0}},
+		{name: "zero withdrawal", eventType: EventTypeWithdrawal, evidence: map[string]interface{}{"amount": 0.0}},
+		{name: "missing amount", eventType: EventTypeWithdrawal, evidence: map[string]interface{}{}},
+		{name: "non-numeric amount", eventType: EventTypePayment, evidence: map[string]interface{}{"amount": "25"}},
+		{name: "other event type", eventType: "note_played", evidence: map[string]interface{}{"amount": 25.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, ok := BalanceDelta(tt.eventType, tt.evidence)
+			if delta != tt.delta || ok != tt.ok {
+				t.Errorf("BalanceDelta = (%v, %v), want (%v, %v)", delta, ok, tt.delta, tt.ok)
+			}
+		})
+	}
+}
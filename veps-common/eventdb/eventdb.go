@@ -0,0 +1,385 @@
+// Package eventdb is the shared data access layer for the VEPS events read model,
+// the actor registry and the balance projection. The RDB Updater writes them and
+// the API Gateway reads them. The API Gateway also tracks its asynchronous
+// submissions here
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no event matches the lookup
+	ErrNotFound = errors.New("event not found")
+	// ErrSequenceConflict is returned when an event is already sealed with a different sequence number
+	ErrSequenceConflict = errors.New("event already sealed with a different sequence number")
+)
+
+// Record is one row of the events table
+type Record struct {
+	ID            string
+	Type          string
+	Source        string
+	Timestamp     time.Time
+	ActorID       string
+	ActorName     string
+	ActorType     string
+	Evidence      map[string]interface{}
+	VectorClock   map[string]int64
+	BoundaryNode  string
+	CorrelationID string
+	ReceivedAt    time.Time
+	ProcessedAt   time.Time
+	SchemaVersion string
+	Seal          *Seal // nil until the ledger has sealed the event
+}
+
+// Seal is the ledger receipt recorded against an event
+type Seal struct {
+	SequenceNumber uint64
+	EventHash      string
+	PreviousHash   string
+	SealedAt       time.Time
+}
+
+// Filter selects events for Query
+// Zero-valued fields are ignored
+type Filter struct {
+	ActorID    string
+	Evidence   map[string]string // matched as evidence->>key = value
+	Start      time.Time
+	End        time.Time
+	StartSeq   uint64 // inclusive; implies sealed events only
+	EndSeq     uint64 // inclusive; implies sealed events only
+	SealedOnly bool
+	Limit      int
+}
+
+// Store reads and writes the events table
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a Store on an open database handle
+// The caller owns the handle and is responsible for migrating it
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// recordColumns is the column list scanned by scanRecord
+const recordColumns = `
+	id, type, source, timestamp,
+	actor_id, actor_name, actor_type,
+	evidence, vector_clock,
+	boundary_node, correlation_id,
+	received_at, processed_at, schema_version,
+	sequence_number, event_hash, previous_hash, sealed_at
+`
+
+// Upsert inserts or updates an event
+// The seal columns are never touched here; use AssignSequence for those
+func (s *Store) Upsert(ctx context.Context, rec Record) error {
+	evidenceJSON, err := json.Marshal(rec.Evidence)
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence: %w", err)
+	}
+
+	vectorClockJSON, err := json.Marshal(rec.VectorClock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vector clock: %w", err)
+	}
+
+	query := `
+		INSERT INTO events (
+			id, type, source, timestamp,
+			actor_id, actor_name, actor_type,
+			evidence, vector_clock,
+			boundary_node, correlation_id,
+			received_at, processed_at, schema_version
+		) VALUES (
+			$1, $2, $3, $4,
+			$5, $6, $7,
+			$8, $9,
+			$10, $11,
+			$12, $13, $14
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			type = EXCLUDED.type,
+			source = EXCLUDED.source,
+			timestamp = EXCLUDED.timestamp,
+			actor_id = EXCLUDED.actor_id,
+			actor_name = EXCLUDED.actor_name,
+			actor_type = EXCLUDED.actor_type,
+			evidence = EXCLUDED.evidence,
+			vector_clock = EXCLUDED.vector_clock,
+			boundary_node = EXCLUDED.boundary_node,
+			correlation_id = EXCLUDED.correlation_id,
+			processed_at = EXCLUDED.processed_at
+	`
+
+	_, err = s.db.ExecContext(
+		ctx,
+		query,
+		rec.ID,
+		rec.Type,
+		rec.Source,
+		rec.Timestamp,
+		rec.ActorID,
+		rec.ActorName,
+		nullString(rec.ActorType),
+		evidenceJSON,
+		vectorClockJSON,
+		nullString(rec.BoundaryNode),
+		nullString(rec.CorrelationID),
+		rec.ReceivedAt,
+		nullTime(rec.ProcessedAt),
+		nullString(rec.SchemaVersion),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert event: %w", err)
+	}
+
+	return nil
+}
+
+// AssignSequence records the ledger seal for an event
+// Re-assigning the same sequence number is a no-op, so seal updates can be retried
+// A balance-moving event is applied to the balance projection in the same
+// transaction, replacing its hold; the new balance is returned if it moved
+func (s *Store) AssignSequence(ctx context.Context, eventID string, seal Seal) (*Balance, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE events SET
+			sequence_number = $2,
+			event_hash = $3,
+			previous_hash = $4,
+			sealed_at = $5
+		WHERE id = $1
+		AND (sequence_number IS NULL OR sequence_number = $2)
+		RETURNING type, actor_id, evidence
+	`
+
+	var (
+		eventType    string
+		actorID      string
+		evidenceJSON []byte
+	)
+	err = tx.QueryRowContext(ctx, query,
+		eventID,
+		int64(seal.SequenceNumber),
+		seal.EventHash,
+		seal.PreviousHash,
+		seal.SealedAt,
+	).Scan(&eventType, &actorID, &evidenceJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing updated: either the event is unknown or it carries another sequence number
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)`, eventID,
+		).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to assign sequence: %w", err)
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+		return nil, ErrSequenceConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign sequence: %w", err)
+	}
+
+	var evidence map[string]interface{}
+	if err := json.Unmarshal(evidenceJSON, &evidence); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal evidence: %w", err)
+	}
+
+	var balance *Balance
+	if delta, ok := BalanceDelta(eventType, evidence); ok {
+		entry := BalanceEntry{EventID: eventID, ActorID: actorID, Amount: delta}
+		if balance, _, err = applySeal(ctx, tx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit seal: %w", err)
+	}
+	return balance, nil
+}
+
+// GetByID retrieves an event by its UUID
+func (s *Store) GetByID(ctx context.Context, id string) (*Record, error) {
+	query := `SELECT ` + recordColumns + ` FROM events WHERE id = $1`
+	return scanRecord(s.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySequence retrieves a sealed event by its ledger sequence number
+func (s *Store) GetBySequence(ctx context.Context, sequenceNumber uint64) (*Record, error) {
+	query := `SELECT ` + recordColumns + ` FROM events WHERE sequence_number = $1`
+	return scanRecord(s.db.QueryRowContext(ctx, query, int64(sequenceNumber)))
+}
+
+// Query returns events matching the filter, newest first
+func (s *Store) Query(ctx context.Context, f Filter) ([]Record, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.ActorID != "" {
+		conditions = append(conditions, "actor_id = "+arg(f.ActorID))
+	}
+
+	// Sort keys so the generated SQL is stable
+	keys := make([]string, 0, len(f.Evidence))
+	for k := range f.Evidence {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		conditions = append(conditions, fmt.Sprintf("evidence->>%s = %s", arg(k), arg(f.Evidence[k])))
+	}
+
+	if !f.Start.IsZero() {
+		conditions = append(conditions, "timestamp >= "+arg(f.Start))
+	}
+	if !f.End.IsZero() {
+		conditions = append(conditions, "timestamp <= "+arg(f.End))
+	}
+	if f.StartSeq > 0 {
+		conditions = append(conditions, "sequence_number >= "+arg(int64(f.StartSeq)))
+	}
+	if f.EndSeq > 0 {
+		conditions = append(conditions, "sequence_number <= "+arg(int64(f.EndSeq)))
+	}
+	if f.SealedOnly {
+		conditions = append(conditions, "sequence_number IS NOT NULL")
+	}
+
+	query := `SELECT ` + recordColumns + ` FROM events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY timestamp DESC"
+	if f.Limit > 0 {
+		query += " LIMIT " + arg(f.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	records := []Record{}
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return records, nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRecord reads one row selected with recordColumns
+func scanRecord(row scanner) (*Record, error) {
+	var (
+		rec                                    Record
+		evidenceJSON, vectorClockJSON          []byte
+		actorType, boundaryNode, correlationID sql.NullString
+		schemaVersion, eventHash, previousHash sql.NullString
+		processedAt, sealedAt                  sql.NullTime
+		sequenceNumber                         sql.NullInt64
+	)
+
+	err := row.Scan(
+		&rec.ID,
+		&rec.Type,
+		&rec.Source,
+		&rec.Timestamp,
+		&rec.ActorID,
+		&rec.ActorName,
+		&actorType,
+		&evidenceJSON,
+		&vectorClockJSON,
+		&boundaryNode,
+		&correlationID,
+		&rec.ReceivedAt,
+		&processedAt,
+		&schemaVersion,
+		&sequenceNumber,
+		&eventHash,
+		&previousHash,
+		&sealedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan event: %w", err)
+	}
+
+	if err := json.Unmarshal(evidenceJSON, &rec.Evidence); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal evidence: %w", err)
+	}
+	if err := json.Unmarshal(vectorClockJSON, &rec.VectorClock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vector clock: %w", err)
+	}
+
+	rec.ActorType = actorType.String
+	rec.BoundaryNode = boundaryNode.String
+	rec.CorrelationID = correlationID.String
+	rec.SchemaVersion = schemaVersion.String
+	if processedAt.Valid {
+		rec.ProcessedAt = processedAt.Time
+	}
+
+	if sequenceNumber.Valid {
+		rec.Seal = &Seal{
+			SequenceNumber: uint64(sequenceNumber.Int64),
+			EventHash:      eventHash.String,
+			PreviousHash:   previousHash.String,
+		}
+		if sealedAt.Valid {
+			rec.Seal.SealedAt = sealedAt.Time
+		}
+	}
+
+	return &rec, nil
+}
+
+// nullString maps an empty string to SQL NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime maps the zero time to SQL NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,197 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKey is one row of the idempotency_keys table
+// A key answers either a synchronous submission, with its Response, or an
+// asynchronous one, with its SubmissionID
+type IdempotencyKey struct {
+	ClientID     string
+	Key          string
+	Fingerprint  string
+	SubmissionID string          // set for an asynchronous submission
+	Response     json.RawMessage // set once a synchronous submission succeeded
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// idempotencyKeyColumns is the column list scanned by scanIdempotencyKey
+const idempotencyKeyColumns = `client_id, key, fingerprint, submission_id, response, created_at, expires_at`
+
+// ClaimIdempotencyKey claims a client's key for a synchronous submission
+// with the given request fingerprint
+//
+// Returns ClaimNew, ClaimReplay with the stored response, ClaimInFlight or
+// ClaimMismatch. A claim still without a response after staleAfter is taken
+// to be abandoned, e.g. by an instance that crashed, and is claimed again
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, clientID, key, fingerprint string, ttl, staleAfter time.Duration) (string, *IdempotencyKey, error) {
+	claimed, err := scanIdempotencyKey(s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (client_id, key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT (client_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			submission_id = NULL,
+			response = NULL,
+			created_at = NOW(),
+			updated_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.fingerprint = EXCLUDED.fingerprint
+				AND idempotency_keys.submission_id IS NULL AND idempotency_keys.response IS NULL
+				AND idempotency_keys.updated_at <= NOW() - make_interval(secs => $5))
+		RETURNING `+idempotencyKeyColumns,
+		clientID, key, fingerprint, ttl.Seconds(), staleAfter.Seconds()))
+	if err == nil {
+		return ClaimNew, claimed, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	existing, err := scanIdempotencyKey(s.db.QueryRowContext(ctx, `
+		SELECT `+idempotencyKeyColumns+` FROM idempotency_keys WHERE client_id = $1 AND key = $2
+	`, clientID, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the two statements; the client may retry
+		return ClaimInFlight, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+
+	switch {
+	case existing.Fingerprint != fingerprint || existing.SubmissionID != "":
+		return ClaimMismatch, existing, nil
+	case existing.Response == nil:
+		return ClaimInFlight, existing, nil
+	}
+	return ClaimReplay, existing, nil
+}
+
+// CompleteIdempotencyKey stores the response of a synchronous submission;
+// retries get it until the key expires
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, clientID, key string, response json.RawMessage) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET response = $3, updated_at = NOW()
+		WHERE client_id = $1 AND key = $2 AND submission_id IS NULL
+	`, clientID, key, []byte(response)); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets a synchronous submission that failed, so a
+// retry is submitted again. A key with a response is kept
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, clientID, key string) error {
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE client_id = $1 AND key = $2 AND submission_id IS NULL AND response IS NULL
+	`, clientID, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// CreateKeyedSubmission records a pending asynchronous submission under a
+// client's idempotency key, in one transaction
+//
+// If the key is already taken, nothing is created: the submission the key
+// names is returned with ClaimReplay, or ClaimMismatch if the key was used
+// for a different request. Otherwise, or if the key's submission failed and
+// may be retried, the new submission is returned with ClaimNew
+func (s *Store) CreateKeyedSubmission(ctx context.Context, rec SubmissionRecord, key, fingerprint string, ttl time.Duration) (string, *SubmissionRecord, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := scanSubmission(tx.QueryRowContext(ctx, `
+		INSERT INTO submissions (id, client_id, state, callback_url)
+		VALUES ($1, $2, '`+SubmissionPending+`', $3)
+		RETURNING `+submissionColumns,
+		rec.ID, rec.ClientID, nullString(rec.CallbackURL)))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create submission: %w", err)
+	}
+
+	var submissionID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (client_id, key, fingerprint, submission_id, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		ON CONFLICT (client_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			submission_id = EXCLUDED.submission_id,
+			response = NULL,
+			created_at = NOW(),
+			updated_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.fingerprint = EXCLUDED.fingerprint AND EXISTS (
+				SELECT 1 FROM submissions
+				WHERE id = idempotency_keys.submission_id AND state = '`+SubmissionFailed+`'))
+		RETURNING submission_id
+	`, rec.ClientID, key, fingerprint, rec.ID, ttl.Seconds()).Scan(&submissionID)
+	if err == nil {
+		if err := tx.Commit(); err != nil {
+			return "", nil, fmt.Errorf("failed to commit submission: %w", err)
+		}
+		return ClaimNew, created, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	// The key is taken; the rollback drops the submission created above
+	existing, err := scanIdempotencyKey(tx.QueryRowContext(ctx, `
+		SELECT `+idempotencyKeyColumns+` FROM idempotency_keys WHERE client_id = $1 AND key = $2
+	`, rec.ClientID, key))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if existing.Fingerprint != fingerprint || existing.SubmissionID == "" {
+		return ClaimMismatch, nil, nil
+	}
+
+	stored, err := scanSubmission(tx.QueryRowContext(ctx,
+		`SELECT `+submissionColumns+` FROM submissions WHERE id = $1`, existing.SubmissionID))
+	if err != nil {
+		return "", nil, err
+	}
+	return ClaimReplay, stored, nil
+}
+
+// DeleteExpiredIdempotencyKeys deletes expired keys and returns how many were
+// deleted
+func (s *Store) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanIdempotencyKey reads one row selected with idempotencyKeyColumns;
+// sql.ErrNoRows is passed through
+func scanIdempotencyKey(row scanner) (*IdempotencyKey, error) {
+	var (
+		k            IdempotencyKey
+		submissionID sql.NullString
+		response     []byte
+	)
+	if err := row.Scan(&k.ClientID, &k.Key, &k.Fingerprint, &submissionID, &response, &k.CreatedAt, &k.ExpiresAt); err != nil {
+		return nil, err
+	}
+	k.SubmissionID = submissionID.String
+	if len(response) > 0 {
+		k.Response = response
+	}
+	return &k, nil
+}
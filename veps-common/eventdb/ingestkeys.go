@@ -0,0 +1,209 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ingest key states
+const (
+	IngestKeyInFlight = "in_flight" // the request is being processed
+	IngestKeyDone     = "done"      // the result is stored and replayed to duplicates
+	IngestKeyFailed   = "failed"    // routing failed; a retry re-routes the same event
+)
+
+// Outcomes of ClaimIngestKey
+const (
+	ClaimNew      = "new"       // the key is unseen and now claimed
+	ClaimRetry    = "retry"     // an earlier attempt failed or was abandoned; the key is claimed again
+	ClaimReplay   = "replay"    // the request was answered; the key holds the result
+	ClaimInFlight = "in_flight" // a request with the key is still being processed
+	ClaimMismatch = "mismatch"  // the key was used for different content
+)
+
+// ErrIngestKeyNotFound is returned when no ingest key matches an update
+var ErrIngestKeyNotFound = errors.New("ingest key not found")
+
+// IngestKey is one row of the ingest_keys table
+type IngestKey struct {
+	Key         string
+	Fingerprint string
+	State       string
+	Event       json.RawMessage // the normalized event, nil until recorded
+	Status      int             // HTTP status of the stored result
+	Response    json.RawMessage // body of the stored result
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// ingestKeyColumns is the column list scanned by scanIngestKey
+const ingestKeyColumns = `key, fingerprint, state, event, status, response, created_at, updated_at, expires_at`
+
+// ClaimIngestKey claims an idempotency key for a request with the given
+// content fingerprint
+//
+// A key is kept for window once claimed. A claim that stays in flight for
+// staleAfter is taken to be abandoned, e.g. by an instance that crashed, and
+// is handed out again as a retry. The key is returned for every outcome
+func (s *Store) ClaimIngestKey(ctx context.Context, key, fingerprint string, window, staleAfter time.Duration) (string, *IngestKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// An unseen key, or one expired with its request finished, is claimed as new
+	claimed, err := scanIngestKey(tx.QueryRowContext(ctx, `
+		INSERT INTO ingest_keys (key, fingerprint, state, expires_at)
+		VALUES ($1, $2, '`+IngestKeyInFlight+`', NOW() + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			state = EXCLUDED.state,
+			event = NULL,
+			status = NULL,
+			response = NULL,
+			created_at = NOW(),
+			updated_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE ingest_keys.expires_at <= NOW() AND ingest_keys.state <> '`+IngestKeyInFlight+`'
+		RETURNING `+ingestKeyColumns,
+		key, fingerprint, window.Seconds()))
+	if err == nil {
+		if err := tx.Commit(); err != nil {
+			return "", nil, fmt.Errorf("failed to commit ingest key: %w", err)
+		}
+		return ClaimNew, claimed, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", nil, fmt.Errorf("failed to claim ingest key: %w", err)
+	}
+
+	var abandoned bool
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+ingestKeyColumns+`, updated_at <= NOW() - make_interval(secs => $2)
+		FROM ingest_keys WHERE key = $1
+		FOR UPDATE
+	`, key, staleAfter.Seconds())
+	existing, err := scanIngestKey(row, &abandoned)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to look up ingest key: %w", err)
+	}
+
+	switch {
+	case existing.Fingerprint != fingerprint:
+		return ClaimMismatch, existing, tx.Commit()
+	case existing.State == IngestKeyDone:
+		return ClaimReplay, existing, tx.Commit()
+	case existing.State == IngestKeyInFlight && !abandoned:
+		return ClaimInFlight, existing, tx.Commit()
+	}
+
+	// Failed or abandoned: hand the key out again
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE ingest_keys SET state = '`+IngestKeyInFlight+`', updated_at = NOW() WHERE key = $1
+	`, key); err != nil {
+		return "", nil, fmt.Errorf("failed to reclaim ingest key: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", nil, fmt.Errorf("failed to commit ingest key: %w", err)
+	}
+	existing.State = IngestKeyInFlight
+	return ClaimRetry, existing, nil
+}
+
+// SetIngestEvent records the normalized event of an in-flight request, so a
+// retry after a failure routes the same event
+func (s *Store) SetIngestEvent(ctx context.Context, key string, event json.RawMessage) error {
+	return s.updateIngestKey(ctx, `
+		UPDATE ingest_keys SET event = $2, updated_at = NOW()
+		WHERE key = $1 AND state = '`+IngestKeyInFlight+`'
+	`, key, []byte(event))
+}
+
+// CompleteIngestKey stores the result of a request; duplicates get it
+// replayed until the key expires
+func (s *Store) CompleteIngestKey(ctx context.Context, key string, status int, response json.RawMessage) error {
+	return s.updateIngestKey(ctx, `
+		UPDATE ingest_keys SET state = '`+IngestKeyDone+`', status = $2, response = $3, updated_at = NOW()
+		WHERE key = $1
+	`, key, status, []byte(response))
+}
+
+// FailIngestKey marks a request whose routing failed without a result; a
+// retry routes the recorded event again. A stored result is kept
+func (s *Store) FailIngestKey(ctx context.Context, key string) error {
+	return s.updateIngestKey(ctx, `
+		UPDATE ingest_keys SET state = '`+IngestKeyFailed+`', updated_at = NOW()
+		WHERE key = $1 AND state = '`+IngestKeyInFlight+`'
+	`, key)
+}
+
+// ReleaseIngestKey forgets a request that never got as far as routing, so a
+// retry is processed as new
+func (s *Store) ReleaseIngestKey(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM ingest_keys WHERE key = $1 AND state = '`+IngestKeyInFlight+`'
+	`, key); err != nil {
+		return fmt.Errorf("failed to release ingest key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIngestKeys deletes expired keys and returns how many were
+// deleted. Keys still in flight are kept for a day past their expiry
+func (s *Store) DeleteExpiredIngestKeys(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM ingest_keys
+		WHERE expires_at <= NOW() AND (state <> '`+IngestKeyInFlight+`' OR expires_at <= NOW() - INTERVAL '1 day')
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired ingest keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// updateIngestKey runs an update of one key
+func (s *Store) updateIngestKey(ctx context.Context, query string, args ...interface{}) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update ingest key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrIngestKeyNotFound
+	}
+	return nil
+}
+
+// scanIngestKey reads one row selected with ingestKeyColumns, followed by any
+// extra columns; sql.ErrNoRows is passed through
+func scanIngestKey(row scanner, extra ...interface{}) (*IngestKey, error) {
+	var (
+		k      IngestKey
+		status sql.NullInt64
+		event  []byte
+		body   []byte
+	)
+	dest := append([]interface{}{
+		&k.Key, &k.Fingerprint, &k.State, &event, &status, &body, &k.CreatedAt, &k.UpdatedAt, &k.ExpiresAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	k.Status = int(status.Int64)
+	if len(event) > 0 {
+		k.Event = event
+	}
+	if len(body) > 0 {
+		k.Response = body
+	}
+	return &k, nil
+}
@@ -0,0 +1,141 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newMockStore(t *testing.T) (*Store, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return New(db), mock
+}
+
+var ingestKeyRow = []string{"key", "fingerprint", "state", "event", "status", "response", "created_at", "updated_at", "expires_at"}
+
+func TestClaimIngestKeyOutcomes(t *testing.T) {
+	now := time.Now()
+	existing := func(fingerprint, state string, abandoned bool) *sqlmock.Rows {
+		return sqlmock.NewRows(append(ingestKeyRow, "abandoned")).
+			AddRow("key-1", fingerprint, state, nil, nil, nil, now, now, now.Add(time.Hour), abandoned)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		outcome string
+		state   string
+	}{
+		{
+			name: "unseen key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).
+					WithArgs("key-1", "fp-1", float64(3600)).
+					WillReturnRows(sqlmock.NewRows(ingestKeyRow).
+						AddRow("key-1", "fp-1", IngestKeyInFlight, nil, nil, nil, now, now, now.Add(time.Hour)))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimNew,
+			state:   IngestKeyInFlight,
+		},
+		{
+			name: "answered key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`FOR UPDATE`).WithArgs("key-1", float64(60)).
+					WillReturnRows(existing("fp-1", IngestKeyDone, false))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimReplay,
+			state:   IngestKeyDone,
+		},
+		{
+			name: "key in flight",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(existing("fp-1", IngestKeyInFlight, false))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimInFlight,
+			state:   IngestKeyInFlight,
+		},
+		{
+			name: "key used for other content",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(existing("fp-2", IngestKeyInFlight, true))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimMismatch,
+			state:   IngestKeyInFlight,
+		},
+		{
+			name: "failed key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(existing("fp-1", IngestKeyFailed, false))
+				mock.ExpectExec(`UPDATE ingest_keys SET state = 'in_flight'`).WithArgs("key-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimRetry,
+			state:   IngestKeyInFlight,
+		},
+		{
+			name: "abandoned key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(existing("fp-1", IngestKeyInFlight, true))
+				mock.ExpectExec(`UPDATE ingest_keys SET state = 'in_flight'`).WithArgs("key-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			outcome: ClaimRetry,
+			state:   IngestKeyInFlight,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockStore(t)
+			mock.ExpectBegin()
+			tt.setup(mock)
+
+			outcome, key, err := s.ClaimIngestKey(context.Background(), "key-1", "fp-1", time.Hour, time.Minute)
+			if err != nil {
+				t.Fatalf("ClaimIngestKey: %v", err)
+			}
+			if outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q", outcome, tt.outcome)
+			}
+			if key == nil || key.State != tt.state {
+				t.Errorf("key = %+v, want state %q", key, tt.state)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestClaimIngestKeyFailsOnDatabaseErrors(t *testing.T) {
+	s, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO ingest_keys`).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	if _, _, err := s.ClaimIngestKey(context.Background(), "key-1", "fp-1", time.Hour, time.Minute); err == nil {
+		t.Error("ClaimIngestKey succeeded without a database")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
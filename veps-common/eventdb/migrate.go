@@ -0,0 +1,337 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// migration is one versioned step of the events schema
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrationLockID is the advisory lock key that serializes concurrent migrators
+const migrationLockID = 480701
+
+// migrations lists every schema change in order. Applied steps must never be edited;
+// add a new version instead.
+var migrations = []migration{
+	{
+		// Version 1 is the original RDB Updater schema. IF NOT EXISTS lets
+		// databases created before versioning adopt it without changes.
+		version: 1,
+		name:    "create_events",
+		sql: `
+		CREATE TABLE IF NOT EXISTS events (
+			id UUID PRIMARY KEY,
+			type VARCHAR(255) NOT NULL,
+			source VARCHAR(255) NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			actor_id VARCHAR(255) NOT NULL,
+			actor_name VARCHAR(255) NOT NULL,
+			actor_type VARCHAR(100),
+			evidence JSONB NOT NULL,
+			vector_clock JSONB NOT NULL,
+			boundary_node VARCHAR(255),
+			correlation_id UUID,
+			received_at TIMESTAMPTZ NOT NULL,
+			processed_at TIMESTAMPTZ,
+			schema_version VARCHAR(50),
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp DESC);
+		CREATE INDEX IF NOT EXISTS idx_events_actor_id ON events(actor_id);
+		CREATE INDEX IF NOT EXISTS idx_events_type ON events(type);
+		CREATE INDEX IF NOT EXISTS idx_events_source ON events(source);
+		CREATE INDEX IF NOT EXISTS idx_events_correlation_id ON events(correlation_id);
+		CREATE INDEX IF NOT EXISTS idx_events_vector_clock ON events USING GIN(vector_clock);
+		`,
+	},
+	{
+		// Version 2 records the ledger seal. Rows written before it stay
+		// unsealed until the boundary adapter replays their seal update.
+		version: 2,
+		name:    "add_ledger_seal",
+		sql: `
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS sequence_number BIGINT;
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS event_hash VARCHAR(128);
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS previous_hash VARCHAR(128);
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS sealed_at TIMESTAMPTZ;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_events_sequence_number ON events(sequence_number);
+		CREATE INDEX IF NOT EXISTS idx_events_boundary_node ON events(boundary_node);
+		CREATE INDEX IF NOT EXISTS idx_events_note_id ON events((evidence->>'note_id'));
+		`,
+	},
+	{
+		// Version 3 adds the actor registry. Every actor already seen in
+		// events is registered so existing actors are not rejected.
+		version: 3,
+		name:    "create_actors",
+		sql: `
+		CREATE TABLE IF NOT EXISTS actors (
+			id VARCHAR(255) PRIMARY KEY,
+			type VARCHAR(100) NOT NULL DEFAULT '',
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			registered_by VARCHAR(50) NOT NULL,
+			registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			suspended_at TIMESTAMPTZ,
+			suspended_reason TEXT,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_actors_type ON actors(type);
+		CREATE INDEX IF NOT EXISTS idx_actors_status ON actors(status);
+
+		INSERT INTO actors (id, type, name, registered_by, registered_at)
+		SELECT DISTINCT ON (actor_id) actor_id, COALESCE(actor_type, ''), actor_name, 'backfill', timestamp
+		FROM events
+		ORDER BY actor_id, timestamp
+		ON CONFLICT (id) DO NOTHING;
+		`,
+	},
+	{
+		// Version 4 serves the Veto Service's per-actor velocity limits
+		version: 4,
+		name:    "index_events_actor_activity",
+		sql: `
+		CREATE INDEX IF NOT EXISTS idx_events_actor_type_timestamp ON events(actor_id, type, timestamp);
+		`,
+	},
+	{
+		// Version 5 adds the balance projection. Every balance-moving event
+		// already stored is applied, so balances start out consistent.
+		version: 5,
+		name:    "create_balances",
+		sql: `
+		CREATE TABLE IF NOT EXISTS balances (
+			actor_id VARCHAR(255) PRIMARY KEY,
+			balance NUMERIC(20, 4) NOT NULL DEFAULT 0,
+			version BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS balance_entries (
+			event_id VARCHAR(255) PRIMARY KEY,
+			actor_id VARCHAR(255) NOT NULL,
+			amount NUMERIC(20, 4) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_balance_entries_actor_id ON balance_entries(actor_id, applied_at DESC);
+
+		INSERT INTO balance_entries (event_id, actor_id, amount, applied_at)
+		SELECT id::text, actor_id,
+			CASE type WHEN 'withdrawal' THEN -(evidence->>'amount')::numeric ELSE (evidence->>'amount')::numeric END,
+			timestamp
+		FROM events
+		WHERE type IN ('payment_processed', 'withdrawal') AND jsonb_typeof(evidence->'amount') = 'number'
+		ON CONFLICT (event_id) DO NOTHING;
+
+		INSERT INTO balances (actor_id, balance, version)
+		SELECT actor_id, SUM(amount), COUNT(*)
+		FROM balance_entries
+		GROUP BY actor_id
+		ON CONFLICT (actor_id) DO NOTHING;
+		`,
+	},
+	{
+		// Version 6 tracks the API Gateway's asynchronous submissions
+		version: 6,
+		name:    "create_submissions",
+		sql: `
+		CREATE TABLE IF NOT EXISTS submissions (
+			id UUID PRIMARY KEY,
+			client_id VARCHAR(255) NOT NULL,
+			state VARCHAR(20) NOT NULL DEFAULT 'pending',
+			event_id VARCHAR(255),
+			sequence_number BIGINT,
+			event_hash VARCHAR(128),
+			previous_hash VARCHAR(128),
+			vector_clock JSONB,
+			veto_reasons JSONB,
+			failed_checks JSONB,
+			error TEXT,
+			callback_url TEXT,
+			callback_attempts INT NOT NULL DEFAULT 0,
+			callback_delivered_at TIMESTAMPTZ,
+			callback_error TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			completed_at TIMESTAMPTZ
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_submissions_created_at ON submissions(created_at);
+		`,
+	},
+	{
+		// Version 7 only moves balances for sealed events. Version 5 applied
+		// every stored event, vetoed ones included, so the projection is
+		// rebuilt from sealed rows; accepted withdrawals awaiting their seal
+		// hold funds in balance_holds instead.
+		version: 7,
+		name:    "seal_balances",
+		sql: `
+		CREATE TABLE IF NOT EXISTS balance_holds (
+			event_id VARCHAR(255) PRIMARY KEY,
+			actor_id VARCHAR(255) NOT NULL,
+			amount NUMERIC(20, 4) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_balance_holds_actor_id ON balance_holds(actor_id, expires_at);
+
+		DELETE FROM balance_entries;
+		DELETE FROM balances;
+
+		INSERT INTO balance_entries (event_id, actor_id, amount, applied_at)
+		SELECT id::text, actor_id,
+			CASE type WHEN 'withdrawal' THEN -(evidence->>'amount')::numeric ELSE (evidence->>'amount')::numeric END,
+			COALESCE(sealed_at, timestamp)
+		FROM events
+		WHERE sequence_number IS NOT NULL
+			AND type IN ('payment_processed', 'withdrawal') AND jsonb_typeof(evidence->'amount') = 'number';
+
+		INSERT INTO balances (actor_id, balance, version)
+		SELECT actor_id, SUM(amount), COUNT(*)
+		FROM balance_entries
+		GROUP BY actor_id;
+		`,
+	},
+	{
+		// Version 8 keeps the Boundary Adapter's idempotency keys, so every
+		// instance recognizes a retry, not just the one that took the request
+		version: 8,
+		name:    "ingest_keys",
+		sql: `
+		CREATE TABLE IF NOT EXISTS ingest_keys (
+			key TEXT PRIMARY KEY,
+			fingerprint VARCHAR(64) NOT NULL,
+			state VARCHAR(20) NOT NULL,
+			event JSONB,
+			status INT,
+			response JSONB,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_ingest_keys_expires_at ON ingest_keys(expires_at);
+		`,
+	},
+	{
+		// Version 9 keeps the API Gateway's idempotency keys next to the
+		// submissions they answer, for every gateway instance to see
+		version: 9,
+		name:    "idempotency_keys",
+		sql: `
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			client_id VARCHAR(255) NOT NULL,
+			key VARCHAR(255) NOT NULL,
+			fingerprint VARCHAR(64) NOT NULL,
+			submission_id UUID REFERENCES submissions(id) ON DELETE CASCADE,
+			response JSONB,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (client_id, key)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+		`,
+	},
+}
+
+// LatestVersion returns the schema version this package reads and writes
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// Migrate brings the events schema up to LatestVersion
+// Each step runs in its own transaction under an advisory lock, so several
+// instances can start at once.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// apply runs a single migration unless it has already been recorded
+func apply(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	var applied bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)`, m.version,
+	).Scan(&applied); err != nil {
+		return err
+	}
+	if applied {
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name,
+	); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("[EventDB] Applied migration %d (%s)", m.version, m.name)
+	return nil
+}
+
+// CurrentVersion returns the highest applied schema version, or 0 if none
+func CurrentVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		`SELECT to_regclass('schema_migrations') IS NOT NULL`,
+	).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`,
+	).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,285 @@
+package eventdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Submission states
+const (
+	SubmissionPending = "pending"
+	SubmissionSealed  = "sealed"
+	SubmissionVetoed  = "vetoed"
+	SubmissionFailed  = "failed"
+)
+
+// ErrSubmissionNotFound is returned when no submission matches the lookup
+var ErrSubmissionNotFound = errors.New("submission not found")
+
+// SubmissionRecord is one row of the submissions table
+type SubmissionRecord struct {
+	ID                  string
+	ClientID            string
+	State               string
+	EventID             string
+	Seal                *Seal // set once sealed; SealedAt is not stored
+	VectorClock         map[string]int64
+	VetoReasons         []string
+	FailedChecks        []string
+	Error               string
+	CallbackURL         string
+	CallbackAttempts    int
+	CallbackDeliveredAt time.Time // zero until delivered
+	CallbackError       string
+	CreatedAt           time.Time
+	CompletedAt         time.Time // zero while pending
+}
+
+// submissionColumns is the column list scanned by scanSubmission
+const submissionColumns = `
+	id, client_id, state, event_id,
+	sequence_number, event_hash, previous_hash, vector_clock,
+	veto_reasons, failed_checks, error,
+	callback_url, callback_attempts, callback_delivered_at, callback_error,
+	created_at, completed_at
+`
+
+// CreateSubmission records a pending submission
+func (s *Store) CreateSubmission(ctx context.Context, rec SubmissionRecord) (*SubmissionRecord, error) {
+	query := `
+		INSERT INTO submissions (id, client_id, state, callback_url)
+		VALUES ($1, $2, '` + SubmissionPending + `', $3)
+		RETURNING ` + submissionColumns
+
+	stored, err := scanSubmission(s.db.QueryRowContext(ctx, query, rec.ID, rec.ClientID, nullString(rec.CallbackURL)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create submission: %w", err)
+	}
+	return stored, nil
+}
+
+// CompleteSubmission records the outcome of a pending submission
+// A submission is completed once; completing it again returns ErrSubmissionNotFound
+func (s *Store) CompleteSubmission(ctx context.Context, rec SubmissionRecord) (*SubmissionRecord, error) {
+	var (
+		sequenceNumber sql.NullInt64
+		eventHash      sql.NullString
+		previousHash   sql.NullString
+	)
+	if rec.Seal != nil {
+		sequenceNumber = sql.NullInt64{Int64: int64(rec.Seal.SequenceNumber), Valid: true}
+		eventHash = nullString(rec.Seal.EventHash)
+		previousHash = nullString(rec.Seal.PreviousHash)
+	}
+
+	vectorClockJSON, err := nullJSON(rec.VectorClock, len(rec.VectorClock) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal vector clock: %w", err)
+	}
+	vetoReasonsJSON, err := nullJSON(rec.VetoReasons, len(rec.VetoReasons) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal veto reasons: %w", err)
+	}
+	failedChecksJSON, err := nullJSON(rec.FailedChecks, len(rec.FailedChecks) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal failed checks: %w", err)
+	}
+
+	query := `
+		UPDATE submissions SET
+			state = $2,
+			event_id = $3,
+			sequence_number = $4,
+			event_hash = $5,
+			previous_hash = $6,
+			vector_clock = $7,
+			veto_reasons = $8,
+			failed_checks = $9,
+			error = $10,
+			completed_at = NOW()
+		WHERE id = $1 AND state = '` + SubmissionPending + `'
+		RETURNING ` + submissionColumns
+
+	return scanSubmission(s.db.QueryRowContext(ctx, query,
+		rec.ID,
+		rec.State,
+		nullString(rec.EventID),
+		sequenceNumber,
+		eventHash,
+		previousHash,
+		vectorClockJSON,
+		vetoReasonsJSON,
+		failedChecksJSON,
+		nullString(rec.Error),
+	))
+}
+
+// FailStaleSubmissions completes up to limit submissions still pending since
+// before a time as failed, with the given error, and returns them
+// Each is completed once, however many instances sweep at the same time
+func (s *Store) FailStaleSubmissions(ctx context.Context, before time.Time, submissionErr string, limit int) ([]SubmissionRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE submissions SET
+			state = '`+SubmissionFailed+`',
+			error = $2,
+			completed_at = NOW()
+		WHERE id IN (
+			SELECT id FROM submissions
+			WHERE state = '`+SubmissionPending+`' AND created_at < $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		) AND state = '`+SubmissionPending+`'
+		RETURNING `+submissionColumns,
+		before, submissionErr, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail stale submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var failed []SubmissionRecord
+	for rows.Next() {
+		rec, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		failed = append(failed, *rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stale submissions: %w", err)
+	}
+	return failed, nil
+}
+
+// RecordCallback records a callback delivery; a zero deliveredAt means it failed
+func (s *Store) RecordCallback(ctx context.Context, id string, attempts int, deliveredAt time.Time, callbackErr string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE submissions SET
+			callback_attempts = $2,
+			callback_delivered_at = $3,
+			callback_error = $4
+		WHERE id = $1
+	`, id, attempts, nullTime(deliveredAt), nullString(callbackErr))
+	if err != nil {
+		return fmt.Errorf("failed to record callback: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrSubmissionNotFound
+	}
+	return nil
+}
+
+// GetSubmission retrieves a submission by ID
+func (s *Store) GetSubmission(ctx context.Context, id string) (*SubmissionRecord, error) {
+	query := `SELECT ` + submissionColumns + ` FROM submissions WHERE id = $1`
+	return scanSubmission(s.db.QueryRowContext(ctx, query, id))
+}
+
+// DeleteSubmissionsBefore deletes submissions created before a time and
+// returns how many were deleted
+func (s *Store) DeleteSubmissionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM submissions WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete submissions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanSubmission reads one row selected with submissionColumns
+func scanSubmission(row scanner) (*SubmissionRecord, error) {
+	var (
+		rec                 SubmissionRecord
+		eventID             sql.NullString
+		sequenceNumber      sql.NullInt64
+		eventHash           sql.NullString
+		previousHash        sql.NullString
+		vectorClockJSON     []byte
+		vetoReasonsJSON     []byte
+		failedChecksJSON    []byte
+		submissionErr       sql.NullString
+		callbackURL         sql.NullString
+		callbackDeliveredAt sql.NullTime
+		callbackErr         sql.NullString
+		completedAt         sql.NullTime
+	)
+
+	err := row.Scan(
+		&rec.ID,
+		&rec.ClientID,
+		&rec.State,
+		&eventID,
+		&sequenceNumber,
+		&eventHash,
+		&previousHash,
+		&vectorClockJSON,
+		&vetoReasonsJSON,
+		&failedChecksJSON,
+		&submissionErr,
+		&callbackURL,
+		&rec.CallbackAttempts,
+		&callbackDeliveredAt,
+		&callbackErr,
+		&rec.CreatedAt,
+		&completedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrSubmissionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan submission: %w", err)
+	}
+
+	rec.EventID = eventID.String
+	rec.Error = submissionErr.String
+	rec.CallbackURL = callbackURL.String
+	rec.CallbackError = callbackErr.String
+
+	if sequenceNumber.Valid {
+		rec.Seal = &Seal{
+			SequenceNumber: uint64(sequenceNumber.Int64),
+			EventHash:      eventHash.String,
+			PreviousHash:   previousHash.String,
+		}
+	}
+	if callbackDeliveredAt.Valid {
+		rec.CallbackDeliveredAt = callbackDeliveredAt.Time
+	}
+	if completedAt.Valid {
+		rec.CompletedAt = completedAt.Time
+	}
+
+	if len(vectorClockJSON) > 0 {
+		if err := json.Unmarshal(vectorClockJSON, &rec.VectorClock); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vector clock: %w", err)
+		}
+	}
+	if len(vetoReasonsJSON) > 0 {
+		if err := json.Unmarshal(vetoReasonsJSON, &rec.VetoReasons); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal veto reasons: %w", err)
+		}
+	}
+	if len(failedChecksJSON) > 0 {
+		if err := json.Unmarshal(failedChecksJSON, &rec.FailedChecks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal failed checks: %w", err)
+		}
+	}
+
+	return &rec, nil
+}
+
+// nullJSON marshals a value for a nullable JSONB column; empty values are NULL
+func nullJSON(v interface{}, empty bool) (interface{}, error) {
+	if empty {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
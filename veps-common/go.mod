@@ -0,0 +1,9 @@
+module github.com/veps-service-480701/veps-common
+
+go 1.22.2
+
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/google/uuid v1.6.0
+	google.golang.org/protobuf v1.33.0
+)
@@ -0,0 +1,156 @@
+package model
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=github.com/veps-service-480701/veps-common ../proto/event.proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/veps-service-480701/veps-common/eventpb"
+)
+
+// EncodeJSON serializes an event, stamping unversioned events with SchemaVersion
+func EncodeJSON(event Event) ([]byte, error) {
+	if err := event.UpgradeSchema(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeJSON parses an event and checks its schema version
+func DecodeJSON(data []byte) (Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return Event{}, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	if err := event.UpgradeSchema(); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// ToProto converts an event to its protobuf form
+func ToProto(event Event) (*eventpb.Event, error) {
+	if err := event.UpgradeSchema(); err != nil {
+		return nil, err
+	}
+
+	evidenceJSON, err := json.Marshal(event.Evidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evidence: %w", err)
+	}
+
+	return &eventpb.Event{
+		Id:           event.ID.String(),
+		Type:         event.Type,
+		Source:       event.Source,
+		Timestamp:    timestamp(event.Timestamp),
+		EvidenceJson: evidenceJSON,
+		VectorClock:  event.VectorClock,
+		Actor: &eventpb.Actor{
+			Id:       event.Actor.ID,
+			Name:     event.Actor.Name,
+			Type:     event.Actor.Type,
+			Metadata: event.Actor.Metadata,
+		},
+		Metadata: &eventpb.EventMetadata{
+			ReceivedAt:    timestamp(event.Metadata.ReceivedAt),
+			ProcessedAt:   timestamp(event.Metadata.ProcessedAt),
+			BoundaryNode:  event.Metadata.BoundaryNode,
+			CorrelationId: event.Metadata.CorrelationID,
+			RetryCount:    int32(event.Metadata.RetryCount),
+			SchemaVersion: event.Metadata.SchemaVersion,
+		},
+	}, nil
+}
+
+// FromProto converts a protobuf event back to the canonical form
+func FromProto(pb *eventpb.Event) (Event, error) {
+	id, err := uuid.Parse(pb.GetId())
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event ID %q: %w", pb.GetId(), err)
+	}
+
+	event := Event{
+		ID:          id,
+		Type:        pb.GetType(),
+		Source:      pb.GetSource(),
+		Timestamp:   fromTimestamp(pb.GetTimestamp()),
+		VectorClock: VectorClock(pb.GetVectorClock()),
+		Actor: Actor{
+			ID:       pb.GetActor().GetId(),
+			Name:     pb.GetActor().GetName(),
+			Type:     pb.GetActor().GetType(),
+			Metadata: pb.GetActor().GetMetadata(),
+		},
+		Metadata: EventMetadata{
+			ReceivedAt:    fromTimestamp(pb.GetMetadata().GetReceivedAt()),
+			ProcessedAt:   fromTimestamp(pb.GetMetadata().GetProcessedAt()),
+			BoundaryNode:  pb.GetMetadata().GetBoundaryNode(),
+			CorrelationID: pb.GetMetadata().GetCorrelationId(),
+			RetryCount:    int(pb.GetMetadata().GetRetryCount()),
+			SchemaVersion: pb.GetMetadata().GetSchemaVersion(),
+		},
+	}
+
+	if len(pb.GetEvidenceJson()) > 0 {
+		if err := json.Unmarshal(pb.GetEvidenceJson(), &event.Evidence); err != nil {
+			return Event{}, fmt.Errorf("failed to unmarshal evidence: %w", err)
+		}
+	}
+
+	if err := event.UpgradeSchema(); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// EncodeProto serializes an event in protobuf wire format
+func EncodeProto(event Event) ([]byte, error) {
+	pb, err := ToProto(event)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeProto parses an event from protobuf wire format
+func DecodeProto(data []byte) (Event, error) {
+	var pb eventpb.Event
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return Event{}, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	return FromProto(&pb)
+}
+
+// timestamp maps the zero time to an unset protobuf timestamp
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp maps an unset protobuf timestamp to the zero time
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,41 @@
+// Package model holds the canonical VEPS event types shared by every service
+// The Boundary Adapter produces these events; the Veto Service, RDB Updater,
+// Monolith Submitter and Data Fracture Handler consume them
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event represents a normalized event in the VEPS system
+// This is the canonical format after boundary adapter normalization
+type Event struct {
+	ID          uuid.UUID      `json:"id"`
+	Type        string         `json:"type"`
+	Source      string         `json:"source"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Actor       Actor          `json:"actor"`
+	Evidence    map[string]any `json:"evidence"`
+	VectorClock VectorClock    `json:"vector_clock"`
+	Metadata    EventMetadata  `json:"metadata,omitempty"`
+}
+
+// Actor represents the entity that triggered the event
+type Actor struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type,omitempty"` // e.g., "user", "service", "system"
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// EventMetadata contains additional context for tracking and debugging
+type EventMetadata struct {
+	ReceivedAt    time.Time `json:"received_at"`
+	ProcessedAt   time.Time `json:"processed_at,omitempty"`
+	BoundaryNode  string    `json:"boundary_node"`  // Which VEPS instance processed this
+	CorrelationID string    `json:"correlation_id"` // For distributed tracing
+	RetryCount    int       `json:"retry_count"`
+	SchemaVersion string    `json:"schema_version"` // See SchemaVersion
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the event format version produced by this module
+// Bump the minor version for additive changes and the major version for
+// changes older consumers cannot read
+const SchemaVersion = "1.0"
+
+// ErrUnsupportedSchema is returned for events written with an incompatible schema version
+var ErrUnsupportedSchema = errors.New("unsupported event schema version")
+
+// CheckSchemaVersion verifies that an event written with version v can be read
+// An empty version predates versioning and is treated as 1.0
+func CheckSchemaVersion(v string) error {
+	if v == "" {
+		return nil
+	}
+
+	major, _, err := parseSchemaVersion(v)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedSchema, v)
+	}
+
+	supported, _, _ := parseSchemaVersion(SchemaVersion)
+	if major != supported {
+		return fmt.Errorf("%w: %s (supported: %d.x)", ErrUnsupportedSchema, v, supported)
+	}
+
+	return nil
+}
+
+// UpgradeSchema checks the event's schema version and stamps unversioned events
+// with the current SchemaVersion
+func (e *Event) UpgradeSchema() error {
+	if err := CheckSchemaVersion(e.Metadata.SchemaVersion); err != nil {
+		return err
+	}
+
+	if e.Metadata.SchemaVersion == "" {
+		e.Metadata.SchemaVersion = SchemaVersion
+	}
+	return nil
+}
+
+// parseSchemaVersion splits a "major.minor" version string
+func parseSchemaVersion(v string) (int, int, error) {
+	majorStr, minorStr, _ := strings.Cut(v, ".")
+
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	minor := 0
+	if minorStr != "" {
+		if minor, err = strconv.Atoi(minorStr); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return major, minor, nil
+}
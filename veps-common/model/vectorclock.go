@@ -0,0 +1,100 @@
+package model
+
+// VectorClock tracks causality for distributed event ordering
+// Maps node/service ID to logical clock value (a per-node monotonic counter).
+// Missing entries count as zero.
+type VectorClock map[string]int64
+
+// Ordering is the causal relationship between two vector clocks
+type Ordering int
+
+const (
+	// Equal means both clocks carry the same counters
+	Equal Ordering = iota
+	// Before means the receiver happened before the other clock
+	Before
+	// After means the receiver happened after the other clock
+	After
+	// Concurrent means neither clock happened before the other
+	Concurrent
+)
+
+// String returns the relationship name used in API responses
+func (o Ordering) String() string {
+	switch o {
+	case Equal:
+		return "equal"
+	case Before:
+		return "happened-before"
+	case After:
+		return "happened-after"
+	default:
+		return "concurrent"
+	}
+}
+
+// Increment increments the vector clock for the given node
+func (vc VectorClock) Increment(nodeID string) {
+	vc[nodeID]++
+}
+
+// Merge merges another vector clock into this one (takes max of each entry)
+func (vc VectorClock) Merge(other VectorClock) {
+	for nodeID, counter := range other {
+		if vc[nodeID] < counter {
+			vc[nodeID] = counter
+		}
+	}
+}
+
+// Copy returns an independent copy of the vector clock
+func (vc VectorClock) Copy() VectorClock {
+	out := make(VectorClock, len(vc))
+	for nodeID, counter := range vc {
+		out[nodeID] = counter
+	}
+	return out
+}
+
+// Compare returns the causal relationship of this vector clock to another
+func (vc VectorClock) Compare(other VectorClock) Ordering {
+	less, greater := false, false
+
+	for nodeID, counter := range vc {
+		otherCounter := other[nodeID]
+		if counter < otherCounter {
+			less = true
+		} else if counter > otherCounter {
+			greater = true
+		}
+	}
+
+	for nodeID, otherCounter := range other {
+		if _, exists := vc[nodeID]; !exists && otherCounter > 0 {
+			less = true
+		}
+	}
+
+	switch {
+	case less && greater:
+		return Concurrent
+	case less:
+		return Before
+	case greater:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// HappensBefore checks if this vector clock happens before another
+func (vc VectorClock) HappensBefore(other VectorClock) bool {
+	return vc.Compare(other) == Before
+}
+
+// IsConcurrent checks if two vector clocks are concurrent (neither happens before the other)
+// Identical clocks count as concurrent
+func (vc VectorClock) IsConcurrent(other VectorClock) bool {
+	ordering := vc.Compare(other)
+	return ordering == Concurrent || ordering == Equal
+}
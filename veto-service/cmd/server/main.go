@@ -12,7 +12,9 @@ import (
 
 	"github.com/veps-service-480701/veto-service/internal/client"
 	"github.com/veps-service-480701/veto-service/internal/handler"
+	"github.com/veps-service-480701/veto-service/internal/rules"
 	"github.com/veps-service-480701/veto-service/internal/validator"
+	"github.com/veps-service-480701/veto-service/internal/velocity"
 )
 
 func main() {
@@ -25,12 +27,27 @@ func main() {
 	rdbClient := client.NewRDBClient(config.RDBUpdaterURL, 5*time.Second)
 	log.Printf("[Main] RDB Client initialized (URL: %s)", config.RDBUpdaterURL)
 
+	// Load business rules; a broken rules file at startup is fatal
+	engine, err := rules.NewEngine(config.RulesPath)
+	if err != nil {
+		log.Fatalf("[Main] Failed to load rules: %v", err)
+	}
+
+	// Pick up edits to the rules file without a restart
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go engine.Watch(watchCtx, config.RulesPollInterval)
+
+	// Cache actor activity for the ruleset's limits
+	tracker := velocity.NewTracker(rdbClient, config.VelocityCacheTTL)
+	log.Printf("[Main] Velocity tracker initialized (cache TTL: %s)", config.VelocityCacheTTL)
+
 	// Initialize validator
-	v := validator.New(rdbClient)
+	v := validator.New(rdbClient, engine, tracker)
 	log.Println("[Main] Validator initialized")
 
 	// Initialize HTTP handler
-	h := handler.New(v)
+	h := handler.New(v, engine)
 
 	// Set up HTTP server
 	mux := http.NewServeMux()
@@ -75,8 +92,11 @@ func main() {
 
 // Config holds application configuration
 type Config struct {
-	Port          string
-	RDBUpdaterURL string
+	Port              string
+	RDBUpdaterURL     string
+	RulesPath         string        // empty uses the built-in rules
+	RulesPollInterval time.Duration // how often the rules file is checked for changes
+	VelocityCacheTTL  time.Duration // how long actor activity is served from the cache
 }
 
 // loadConfig loads configuration from environment variables
@@ -91,9 +111,30 @@ func loadConfig() Config {
 		rdbUpdaterURL = "http://localhost:8081" // Default for local dev
 	}
 
+	rulesPollInterval := 5 * time.Second
+	if raw := os.Getenv("VETO_RULES_POLL_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			rulesPollInterval = d
+		} else {
+			log.Printf("[Main] Warning: invalid VETO_RULES_POLL_INTERVAL %q, using %s", raw, rulesPollInterval)
+		}
+	}
+
+	velocityCacheTTL := 30 * time.Second
+	if raw := os.Getenv("VETO_VELOCITY_CACHE_TTL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+			velocityCacheTTL = d
+		} else {
+			log.Printf("[Main] Warning: invalid VETO_VELOCITY_CACHE_TTL %q, using %s", raw, velocityCacheTTL)
+		}
+	}
+
 	return Config{
-		Port:          port,
-		RDBUpdaterURL: rdbUpdaterURL,
+		Port:              port,
+		RDBUpdaterURL:     rdbUpdaterURL,
+		RulesPath:         os.Getenv("VETO_RULES_PATH"),
+		RulesPollInterval: rulesPollInterval,
+		VelocityCacheTTL:  velocityCacheTTL,
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -12,6 +13,9 @@ import (
 	"github.com/veps-service-480701/veto-service/pkg/models"
 )
 
+// ErrInsufficientFunds is returned when a hold would overdraw a balance
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
 // RDBClient handles communication with the RDB Updater service
 type RDBClient struct {
 	baseURL    string
@@ -100,13 +104,16 @@ func (c *RDBClient) GetEvent(ctx context.Context, eventID string) (*models.Event
 	return &response.Data, nil
 }
 
-// CheckCausality verifies vector clock causality
-func (c *RDBClient) CheckCausality(ctx context.Context, vc models.VectorClock) (bool, []string, error) {
+// CheckCausality verifies vector clock causality, skipping the entry of originNode
+// Returns the causal predecessors that are not yet stored
+func (c *RDBClient) CheckCausality(ctx context.Context, vc models.VectorClock, originNode string) (bool, []models.MissingDependency, error) {
 	// Prepare request body
 	reqBody := struct {
 		VectorClock models.VectorClock `json:"vector_clock"`
+		OriginNode  string             `json:"origin_node,omitempty"`
 	}{
 		VectorClock: vc,
+		OriginNode:  originNode,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -146,8 +153,8 @@ func (c *RDBClient) CheckCausality(ctx context.Context, vc models.VectorClock) (
 	var response struct {
 		Success bool   `json:"success"`
 		Data    struct {
-			Satisfied    bool     `json:"satisfied"`
-			MissingNodes []string `json:"missing_nodes"`
+			Satisfied bool                       `json:"satisfied"`
+			Missing   []models.MissingDependency `json:"missing"`
 		} `json:"data"`
 		Error string `json:"error"`
 	}
@@ -161,12 +168,240 @@ func (c *RDBClient) CheckCausality(ctx context.Context, vc models.VectorClock) (
 		return false, nil, fmt.Errorf("RDB Updater returned unexpected status %d", resp.StatusCode)
 	}
 
-	return response.Data.Satisfied, response.Data.MissingNodes, nil
+	return response.Data.Satisfied, response.Data.Missing, nil
+}
+
+// GetActor looks up an actor in the registry
+// Returns nil without an error if the actor is not registered
+func (c *RDBClient) GetActor(ctx context.Context, actorID string) (*models.ActorRecord, error) {
+	reqURL := fmt.Sprintf("%s/actors?id=%s", c.baseURL, url.QueryEscape(actorID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication token
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDB Updater returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data models.ActorRecord `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// RegisterActor adds an actor to the registry on its first accepted event
+// Registering an actor that already exists returns the stored actor
+func (c *RDBClient) RegisterActor(ctx context.Context, actor models.Actor) (*models.ActorRecord, error) {
+	jsonData, err := json.Marshal(map[string]string{
+		"id":            actor.ID,
+		"type":          actor.Type,
+		"name":          actor.Name,
+		"registered_by": "auto",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/actors", c.baseURL), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication token
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  models.ActorRecord `json:"data"`
+		Error string             `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("RDB Updater returned status %d: %s", resp.StatusCode, response.Error)
+	}
+
+	return &response.Data, nil
 }
 
-// CountEventsByActor counts events for a specific actor (for rate limiting checks)
-func (c *RDBClient) CountEventsByActor(ctx context.Context, actorID string, since time.Time) (int, error) {
-	// TODO: Implement this endpoint in RDB Updater if needed for rate limiting
-	// For now, return a placeholder
-	return 0, nil
-}
\ No newline at end of file
+// GetActivity counts an actor's events of a type since a point in time, and
+// sums an evidence field over them, in buckets of the given width
+func (c *RDBClient) GetActivity(ctx context.Context, actorID, eventType, field string, since time.Time, bucket time.Duration) (*models.Activity, error) {
+	params := url.Values{}
+	params.Set("actor_id", actorID)
+	params.Set("type", eventType)
+	params.Set("since", since.UTC().Format(time.RFC3339))
+	params.Set("bucket", bucket.String())
+	if field != "" {
+		params.Set("field", field)
+	}
+	reqURL := fmt.Sprintf("%s/activity?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication token
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  models.Activity `json:"data"`
+		Error string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDB Updater returned status %d: %s", resp.StatusCode, response.Error)
+	}
+
+	return &response.Data, nil
+}
+
+// GetBalance retrieves an actor's balance
+// If eventID is set, the balance also reports whether that event was applied
+func (c *RDBClient) GetBalance(ctx context.Context, actorID, eventID string) (*models.Balance, error) {
+	params := url.Values{}
+	params.Set("actor_id", actorID)
+	if eventID != "" {
+		params.Set("event_id", eventID)
+	}
+	reqURL := fmt.Sprintf("%s/balance?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication token
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  models.Balance `json:"data"`
+		Error string         `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDB Updater returned status %d: %s", resp.StatusCode, response.Error)
+	}
+
+	return &response.Data, nil
+}
+
+// HoldBalance reserves a withdrawal's amount against an actor's balance
+// until the withdrawal is sealed, or for ttl if it never is
+// Returns ErrInsufficientFunds, with the current balance, if the amount is
+// not available
+func (c *RDBClient) HoldBalance(ctx context.Context, eventID, actorID string, amount float64, ttl time.Duration) (*models.Balance, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"event_id":    eventID,
+		"actor_id":    actorID,
+		"amount":      amount,
+		"ttl_seconds": int(ttl.Seconds()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/balance/hold", c.baseURL), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Add authentication token
+	token, err := getIDToken(ctx, c.baseURL)
+	if err != nil {
+		fmt.Printf("Warning: failed to get ID token: %v\n", err)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data  models.Balance `json:"data"`
+		Error string         `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &response.Data, nil
+	case http.StatusUnprocessableEntity:
+		return &response.Data, ErrInsufficientFunds
+	default:
+		return nil, fmt.Errorf("RDB Updater returned status %d: %s", resp.StatusCode, response.Error)
+	}
+}
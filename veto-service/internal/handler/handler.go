@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/veto-service/internal/rules"
 	"github.com/veps-service-480701/veto-service/internal/validator"
 	"github.com/veps-service-480701/veto-service/pkg/models"
 )
@@ -14,12 +17,14 @@ import (
 // Handler manages HTTP requests for the Veto Service
 type Handler struct {
 	validator *validator.Validator
+	rules     *rules.Engine
 }
 
 // New creates a new HTTP handler
-func New(v *validator.Validator) *Handler {
+func New(v *validator.Validator, engine *rules.Engine) *Handler {
 	return &Handler{
 		validator: v,
+		rules:     engine,
 	}
 }
 
@@ -40,6 +45,9 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Success:   true,
 		Message:   "Veto Service is healthy",
 		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"ruleset_version": h.rules.Current().Version,
+		},
 	}
 	h.writeJSON(w, http.StatusOK, response)
 }
@@ -73,10 +81,15 @@ func (h *Handler) ValidateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := vetoRequest.Event.UpgradeSchema(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	log.Printf("[Handler] Validating event %s (type: %s)", vetoRequest.Event.ID, vetoRequest.Event.Type)
 
 	// Perform validation
-	passed, validationErrors, err := h.validator.Validate(r.Context(), vetoRequest.Event)
+	result, err := h.validator.Validate(r.Context(), vetoRequest.Event)
 	if err != nil {
 		log.Printf("[Handler] Validation error for event %s: %v", vetoRequest.Event.ID, err)
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("validation failed: %v", err))
@@ -88,12 +101,12 @@ func (h *Handler) ValidateEvent(w http.ResponseWriter, r *http.Request) {
 	// Prepare failed checks list
 	failedChecks := []string{}
 	reasons := []string{}
-	for _, verr := range validationErrors {
+	for _, verr := range result.Errors {
 		failedChecks = append(failedChecks, verr.Check)
 		reasons = append(reasons, fmt.Sprintf("%s: %s", verr.Check, verr.Reason))
 	}
 
-	if !passed {
+	if !result.Passed {
 		// Validation failed - veto the event
 		log.Printf("[Handler] Event %s VETOED: %v", vetoRequest.Event.ID, reasons)
 		
@@ -104,9 +117,11 @@ func (h *Handler) ValidateEvent(w http.ResponseWriter, r *http.Request) {
 			Timestamp: time.Now().UTC(),
 			Duration:  duration.String(),
 			Data: map[string]interface{}{
-				"passed":        false,
-				"failed_checks": failedChecks,
-				"reasons":       reasons,
+				"passed":          false,
+				"failed_checks":   failedChecks,
+				"reasons":         reasons,
+				"warnings":        result.Warnings,
+				"ruleset_version": result.RulesetVersion,
 			},
 		}
 		h.writeJSON(w, http.StatusPreconditionFailed, response)
@@ -123,13 +138,145 @@ func (h *Handler) ValidateEvent(w http.ResponseWriter, r *http.Request) {
 		Timestamp: time.Now().UTC(),
 		Duration:  duration.String(),
 		Data: map[string]interface{}{
-			"passed": true,
+			"passed":          true,
+			"warnings":        result.Warnings,
+			"ruleset_version": result.RulesetVersion,
 		},
 	}
 
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// ExplainEvent handles POST /validate/explain
+//
+// It runs the same checks as /validate without vetoing or recording
+// anything and returns a trace of every check. It always answers 200;
+// the verdict is in the data. A missing event ID or timestamp is filled in
+// so a bare payload can be explained
+func (h *Handler) ExplainEvent(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	var vetoRequest models.VetoRequest
+	if err := json.NewDecoder(r.Body).Decode(&vetoRequest); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	defer r.Body.Close()
+
+	event := vetoRequest.Event
+	if event.Type == "" {
+		h.writeError(w, http.StatusBadRequest, "event type is required")
+		return
+	}
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if err := event.UpgradeSchema(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.validator.Explain(r.Context(), event)
+	if err != nil {
+		log.Printf("[Handler] Explain error for event %s: %v", event.ID, err)
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("validation failed: %v", err))
+		return
+	}
+
+	failedChecks := []string{}
+	reasons := []string{}
+	for _, verr := range result.Errors {
+		failedChecks = append(failedChecks, verr.Check)
+		reasons = append(reasons, fmt.Sprintf("%s: %s", verr.Check, verr.Reason))
+	}
+
+	verdict := "accepted"
+	message := "Event would pass validation"
+	if !result.Passed {
+		verdict = "vetoed"
+		message = "Event would be VETOED"
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   message,
+		EventID:   event.ID.String(),
+		Timestamp: time.Now().UTC(),
+		Duration:  time.Since(startTime).String(),
+		Data: map[string]interface{}{
+			"passed":          result.Passed,
+			"verdict":         verdict,
+			"failed_checks":   failedChecks,
+			"reasons":         reasons,
+			"warnings":        result.Warnings,
+			"ruleset_version": result.RulesetVersion,
+			"event":           event,
+			"checks":          result.Checks,
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// GetRules returns the active ruleset
+func (h *Handler) GetRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	rs := h.rules.Current()
+	response := Response{
+		Success:   true,
+		Message:   fmt.Sprintf("Ruleset %s has %d rules", rs.Version, len(rs.Rules)),
+		Timestamp: time.Now().UTC(),
+		Data:      rs,
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// ReloadRules re-reads the rules file
+// An invalid file is rejected with 422 and the active ruleset is kept
+func (h *Handler) ReloadRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "only POST method is allowed")
+		return
+	}
+
+	previous := h.rules.Current()
+	rs, err := h.rules.Reload()
+	if errors.Is(err, rules.ErrInvalidRuleset) {
+		log.Printf("[Handler] Rules reload rejected, keeping version %s: %v", previous.Version, err)
+		h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] Rules reload failed, keeping version %s: %v", previous.Version, err)
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := Response{
+		Success:   true,
+		Message:   "Rules reloaded",
+		Timestamp: time.Now().UTC(),
+		Data: map[string]interface{}{
+			"previous_version": previous.Version,
+			"ruleset_version":  rs.Version,
+			"rules":            len(rs.Rules),
+			"source":           rs.Source,
+		},
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
 // writeJSON writes a JSON response
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -153,4 +300,7 @@ func (h *Handler) writeError(w http.ResponseWriter, status int, message string)
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/validate", h.ValidateEvent)
+	mux.HandleFunc("/validate/explain", h.ExplainEvent)
+	mux.HandleFunc("/rules", h.GetRules)
+	mux.HandleFunc("/rules/reload", h.ReloadRules)
 }
\ No newline at end of file
@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultRules reproduces the limits that used to be hard-coded in the
+// validator; it is active when no rules file is configured
+//
+//go:embed default_rules.yaml
+var defaultRules []byte
+
+// Engine holds the active ruleset and reloads it from its file
+type Engine struct {
+	path    string
+	current atomic.Pointer[Ruleset]
+
+	mu      sync.Mutex // serializes reloads
+	modTime time.Time
+	size    int64
+}
+
+// NewEngine loads the ruleset at path, or the built-in rules if path is empty
+// An invalid file at startup is an error; later reloads keep the old ruleset
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+
+	if path == "" {
+		rs, err := Parse(defaultRules, "yaml", "built-in")
+		if err != nil {
+			return nil, fmt.Errorf("built-in rules: %w", err)
+		}
+		e.current.Store(rs)
+		log.Printf("[Rules] Loaded %d built-in rules (version: %s)", len(rs.Rules), rs.Version)
+		return e, nil
+	}
+
+	if _, err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Current returns the active ruleset
+func (e *Engine) Current() *Ruleset {
+	return e.current.Load()
+}
+
+// Reload reads the rules file and activates it if it is valid
+// Rules are built in when no file is configured, and there is nothing to reload
+func (e *Engine) Reload() (*Ruleset, error) {
+	if e.path == "" {
+		return e.Current(), nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat rules file: %w", err)
+	}
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	// Remember the file even if it is invalid, so the watcher does not
+	// report the same broken edit every interval
+	e.modTime, e.size = info.ModTime(), info.Size()
+
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(e.path), ".json") {
+		format = "json"
+	}
+
+	rs, err := Parse(data, format, e.path)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := e.current.Swap(rs)
+	if previous == nil {
+		log.Printf("[Rules] Loaded %d rules from %s (version: %s)", len(rs.Rules), e.path, rs.Version)
+	} else {
+		log.Printf("[Rules] Reloaded %d rules from %s (version: %s -> %s)", len(rs.Rules), e.path, previous.Version, rs.Version)
+	}
+	return rs, nil
+}
+
+// Watch reloads the rules file whenever it changes until ctx is cancelled
+func (e *Engine) Watch(ctx context.Context, interval time.Duration) {
+	if e.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(e.path)
+		if err != nil {
+			log.Printf("[Rules] Warning: cannot stat rules file: %v", err)
+			continue
+		}
+
+		e.mu.Lock()
+		changed := !info.ModTime().Equal(e.modTime) || info.Size() != e.size
+		e.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		if _, err := e.Reload(); err != nil {
+			log.Printf("[Rules] ERROR: Rules file changed but was not loaded, keeping version %s: %v", e.Current().Version, err)
+		}
+	}
+}
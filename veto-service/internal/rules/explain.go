@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// RuleTrace explains how one rule was evaluated for an event
+type RuleTrace struct {
+	RuleID      string       `json:"rule_id"`
+	Severity    string       `json:"severity"`
+	When        string       `json:"when"`
+	Applies     bool         `json:"applies"` // false when the event type is not covered
+	Fired       bool         `json:"fired"`
+	Reason      string       `json:"reason,omitempty"`
+	Comparisons []Comparison `json:"comparisons,omitempty"`
+}
+
+// Comparison is one comparison or predicate inside a rule's expression,
+// with the values it was given
+type Comparison struct {
+	Expr   string `json:"expr"`
+	Op     string `json:"op"`
+	Left   any    `json:"left"`
+	Right  any    `json:"right,omitempty"`
+	Result any    `json:"result"`
+}
+
+// Explain evaluates the ruleset like Evaluate and also traces every rule
+func (rs *Ruleset) Explain(event models.Event) ([]Match, []RuleTrace) {
+	env := Env(event)
+
+	var matches []Match
+	traces := make([]RuleTrace, 0, len(rs.Rules))
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		trace := RuleTrace{
+			RuleID:   r.ID,
+			Severity: r.Severity,
+			When:     r.When,
+			Applies:  r.Applies(event.Type),
+		}
+
+		if trace.Applies {
+			trace.Fired = truthy(r.when.eval(env))
+			trace.Comparisons = comparisons(r.when, env, nil)
+			if trace.Fired {
+				trace.Reason = render(r.reason, env)
+				matches = append(matches, Match{RuleID: r.ID, Severity: r.Severity, Reason: trace.Reason})
+			}
+		}
+		traces = append(traces, trace)
+	}
+	return matches, traces
+}
+
+// comparisons collects the comparisons and predicate calls of an expression
+// Expressions are pure, so re-evaluating their parts has no effect
+func comparisons(n node, env map[string]any, out []Comparison) []Comparison {
+	switch n := n.(type) {
+	case *comparison:
+		out = append(out, Comparison{
+			Expr:   n.String(),
+			Op:     n.op,
+			Left:   traceValue(n.left.eval(env)),
+			Right:  traceValue(n.right.eval(env)),
+			Result: n.eval(env),
+		})
+	case *call:
+		c := Comparison{
+			Expr:   n.String(),
+			Op:     n.name,
+			Left:   traceValue(n.args[0].eval(env)),
+			Result: traceValue(n.eval(env)),
+		}
+		if len(n.args) > 1 {
+			c.Right = traceValue(n.args[1].eval(env))
+		}
+		out = append(out, c)
+	case *logical:
+		out = comparisons(n.left, env, out)
+		out = comparisons(n.right, env, out)
+	case *not:
+		out = comparisons(n.operand, env, out)
+	}
+	return out
+}
+
+// traceValue makes a value safe to encode as JSON
+func traceValue(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = traceValue(item)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,731 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Expressions are a small, side-effect free language over an event:
+//
+//	evidence.amount > 1000000 && source != "batch-import"
+//	!is_number(evidence.amount) || evidence.amount <= 0
+//	lower(actor.type) in ["service", "system"]
+//
+// Paths start at evidence, actor, source or type; a missing field is null.
+// Comparisons between mismatched types are false rather than errors, so a
+// rule can never fail at evaluation time once it has parsed
+
+// roots are the names a path may start with
+var roots = map[string]bool{
+	"evidence": true,
+	"actor":    true,
+	"source":   true,
+	"type":     true,
+}
+
+// limitRoots are the names a path in a limit's reason may start with
+var limitRoots = map[string]bool{
+	"evidence": true,
+	"actor":    true,
+	"source":   true,
+	"type":     true,
+	"limit":    true,
+}
+
+// functions maps each built-in to its arity
+var functions = map[string]int{
+	"exists":      1,
+	"is_number":   1,
+	"is_string":   1,
+	"len":         1,
+	"lower":       1,
+	"upper":       1,
+	"contains":    2,
+	"starts_with": 2,
+}
+
+// node is a parsed expression
+type node interface {
+	eval(env map[string]any) any
+	String() string
+}
+
+// parseExpr compiles an expression, rejecting unknown paths and functions
+func parseExpr(src string) (node, error) {
+	return parseExprWith(src, roots)
+}
+
+// parseExprWith compiles an expression whose paths start with one of names
+func parseExprWith(src string, names map[string]bool) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, roots: names}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+	}
+	return n, nil
+}
+
+// rootList names the roots for an error message, e.g. "a, b or c"
+func rootList(names map[string]bool) string {
+	list := make([]string, 0, len(names))
+	for _, name := range []string{"evidence", "actor", "source", "type", "limit"} {
+		if names[name] {
+			list = append(list, name)
+		}
+	}
+	return strings.Join(list[:len(list)-1], ", ") + " or " + list[len(list)-1]
+}
+
+// ---- lexer ----
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// twoCharOps are matched before single-character operators
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+// lex splits an expression into tokens; offsets are byte offsets into src
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case c == utf8.RuneError && size == 1:
+			return nil, fmt.Errorf("invalid UTF-8 at offset %d", i)
+
+		case unicode.IsSpace(c):
+			i += size
+
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(rune(src[i+1]))):
+			start := i
+			for i < len(src) && (isDigit(rune(src[i])) || src[i] == '.' || src[i] == '_') {
+				i++
+			}
+			text := strings.ReplaceAll(src[start:i], "_", "")
+			num, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", src[start:i], start)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[start:i], num: num, pos: start})
+
+		case c == '"' || c == '\'':
+			start := i
+			i += size
+			var sb strings.Builder
+			closed := false
+			for i < len(src) {
+				r, n := utf8.DecodeRuneInString(src[i:])
+				if r == utf8.RuneError && n == 1 {
+					return nil, fmt.Errorf("invalid UTF-8 at offset %d", i)
+				}
+				if r == c {
+					i += n
+					closed = true
+					break
+				}
+				if r == '\\' && i+n < len(src) {
+					i += n
+					r, n = utf8.DecodeRuneInString(src[i:])
+				}
+				sb.WriteRune(r)
+				i += n
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) {
+				r, n := utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				i += n
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+
+		default:
+			matched := false
+			for _, op := range twoCharOps {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+					i += 2
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if !strings.ContainsRune("<>!+-*/().,[]", c) {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: string(c), pos: i})
+			i += size
+		}
+	}
+	return append(tokens, token{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// isDigit matches the ASCII digits a number literal is written with
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+// ---- parser ----
+
+type parser struct {
+	tokens []token
+	pos    int
+	roots  map[string]bool
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes an operator or keyword if it is next
+func (p *parser) accept(text string) bool {
+	tok := p.peek()
+	if (tok.kind == tokOp || tok.kind == tokIdent) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at offset %d, got %q", text, tok.pos, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &not{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(op) {
+			right, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			return &comparison{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOp || (op != "+" && op != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOp || (op != "*" && op != "/") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithmetic{op: "-", left: &literal{value: 0.0}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return &literal{value: tok.num}, nil
+
+	case tokString:
+		return &literal{value: tok.text}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "null":
+			return &literal{value: nil}, nil
+		}
+
+		if arity, ok := functions[tok.text]; ok && p.peek().text == "(" {
+			return p.parseCall(tok, arity)
+		}
+
+		if !p.roots[tok.text] {
+			return nil, fmt.Errorf("unknown name %q at offset %d (paths start with %s)", tok.text, tok.pos, rootList(p.roots))
+		}
+		path := []string{tok.text}
+		for p.accept(".") {
+			field := p.next()
+			if field.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name at offset %d", field.pos)
+			}
+			path = append(path, field.text)
+		}
+		return &pathNode{path: path}, nil
+
+	case tokOp:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		case "[":
+			list := &listNode{}
+			if p.accept("]") {
+				return list, nil
+			}
+			for {
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if p.accept("]") {
+					return list, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+}
+
+func (p *parser) parseCall(name token, arity int) (node, error) {
+	p.next() // (
+	call := &call{name: name.text}
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(call.args) != arity {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", name.text, arity, len(call.args))
+	}
+	return call, nil
+}
+
+// ---- evaluation ----
+
+type literal struct{ value any }
+
+func (n *literal) eval(map[string]any) any { return n.value }
+func (n *literal) String() string {
+	if s, ok := n.value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return formatValue(n.value)
+}
+
+type pathNode struct{ path []string }
+
+func (n *pathNode) eval(env map[string]any) any {
+	var current any = env
+	for _, field := range n.path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[field]
+	}
+	return normalize(current)
+}
+func (n *pathNode) String() string { return strings.Join(n.path, ".") }
+
+type listNode struct{ items []node }
+
+func (n *listNode) eval(env map[string]any) any {
+	values := make([]any, len(n.items))
+	for i, item := range n.items {
+		values[i] = item.eval(env)
+	}
+	return values
+}
+func (n *listNode) String() string {
+	items := make([]string, len(n.items))
+	for i, item := range n.items {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+type not struct{ operand node }
+
+func (n *not) eval(env map[string]any) any { return !truthy(n.operand.eval(env)) }
+func (n *not) String() string              { return "!" + n.operand.String() }
+
+type logical struct {
+	op          string
+	left, right node
+}
+
+func (n *logical) eval(env map[string]any) any {
+	left := truthy(n.left.eval(env))
+	if n.op == "&&" {
+		return left && truthy(n.right.eval(env))
+	}
+	return left || truthy(n.right.eval(env))
+}
+func (n *logical) String() string {
+	return "(" + n.left.String() + " " + n.op + " " + n.right.String() + ")"
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (n *comparison) eval(env map[string]any) any {
+	left, right := n.left.eval(env), n.right.eval(env)
+
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		list, ok := right.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if equal(left, item) {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+func (n *comparison) String() string { return n.left.String() + " " + n.op + " " + n.right.String() }
+
+type arithmetic struct {
+	op          string
+	left, right node
+}
+
+func (n *arithmetic) eval(env map[string]any) any {
+	left, lok := n.left.eval(env).(float64)
+	right, rok := n.right.eval(env).(float64)
+	if !lok || !rok {
+		return nil
+	}
+	switch n.op {
+	case "+":
+		return left + right
+	case "-":
+		return left - right
+	case "*":
+		return left * right
+	default:
+		if right == 0 {
+			return nil
+		}
+		return left / right
+	}
+}
+func (n *arithmetic) String() string {
+	return "(" + n.left.String() + " " + n.op + " " + n.right.String() + ")"
+}
+
+type call struct {
+	name string
+	args []node
+}
+
+func (n *call) eval(env map[string]any) any {
+	arg := n.args[0].eval(env)
+	switch n.name {
+	case "exists":
+		return arg != nil
+	case "is_number":
+		_, ok := arg.(float64)
+		return ok
+	case "is_string":
+		_, ok := arg.(string)
+		return ok
+	case "len":
+		switch v := arg.(type) {
+		case string:
+			return float64(len(v))
+		case []any:
+			return float64(len(v))
+		case map[string]any:
+			return float64(len(v))
+		}
+		return nil
+	case "lower", "upper":
+		s, ok := arg.(string)
+		if !ok {
+			return nil
+		}
+		if n.name == "lower" {
+			return strings.ToLower(s)
+		}
+		return strings.ToUpper(s)
+	case "contains":
+		needle := n.args[1].eval(env)
+		switch v := arg.(type) {
+		case string:
+			s, ok := needle.(string)
+			return ok && strings.Contains(v, s)
+		case []any:
+			for _, item := range v {
+				if equal(item, needle) {
+					return true
+				}
+			}
+		}
+		return false
+	case "starts_with":
+		s, ok := arg.(string)
+		prefix, pok := n.args[1].eval(env).(string)
+		return ok && pok && strings.HasPrefix(s, prefix)
+	}
+	return nil
+}
+func (n *call) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return n.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// normalize converts decoded JSON and Go values to the expression types:
+// nil, bool, float64, string, []any and map[string]any
+func normalize(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case map[string]string:
+		m := make(map[string]any, len(v))
+		for key, s := range v {
+			m[key] = s
+		}
+		return m
+	}
+	return value
+}
+
+// truthy treats only true as true
+func truthy(value any) bool {
+	b, ok := value.(bool)
+	return ok && b
+}
+
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		bv, ok := b.(float64)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	}
+	return false
+}
+
+// compare orders two numbers or two strings
+func compare(a, b any) (int, bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok || math.IsNaN(av) || math.IsNaN(bv) {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	}
+	return 0, false
+}
+
+// formatValue renders a value for reasons and traces
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,216 @@
+package rules
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var testEnv = map[string]any{
+	"evidence": map[string]any{
+		"amount":   250.0,
+		"nan":      math.NaN(),
+		"currency": "EUR",
+		"note":     "café",
+		"tags":     []any{"a", "b"},
+		"flag":     true,
+	},
+	"actor": map[string]any{
+		"id":       "user-1",
+		"type":     "Service",
+		"metadata": map[string]any{"région": "eu"},
+	},
+	"source": "api",
+	"type":   "payment_processed",
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", `unexpected "end of expression" at offset 0`},
+		{"amount > 1", `unknown name "amount" at offset 0`},
+		{"evidence.amount >", `unexpected "end of expression" at offset 17`},
+		{"evidence.amount > 1 1", `unexpected "1" at offset 20`},
+		{"(evidence.amount > 1", `expected ")" at offset 20`},
+		{"evidence.", "expected field name at offset 9"},
+		{"len(evidence.a, evidence.b)", "len takes 1 argument(s), got 2"},
+		{"contains(evidence.a)", "contains takes 2 argument(s), got 1"},
+		{`source == "api`, "unterminated string at offset 10"},
+		{"evidence.amount # 1", `unexpected character '#' at offset 16`},
+		{"evidence.amount > 1.2.3", `invalid number "1.2.3" at offset 18`},
+		{"source == «api»", `unexpected character '«' at offset 10`},
+		{"source == \"\xff\"", "invalid UTF-8 at offset 11"},
+		{"limit.total > 1", `unknown name "limit"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := parseExpr(tt.src)
+			if err == nil {
+				t.Fatalf("parseExpr(%q) succeeded, want error containing %q", tt.src, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseExpr(%q) = %v, want error containing %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"1 + 2 * 3 == 7", "(1 + (2 * 3)) == 7"},
+		{"(1 + 2) * 3 == 9", "((1 + 2) * 3) == 9"},
+		{"10 - 4 - 3 == 3", "((10 - 4) - 3) == 3"},
+		{"-2 * 3 < 0", "((0 - 2) * 3) < 0"},
+		{"true || false && false", "(true || (false && false))"},
+		{"!true && false", "(!true && false)"},
+		{"!evidence.amount > 1", "!evidence.amount > 1"},
+		{"source in [\"api\", \"batch\"]", `source in ["api", "batch"]`},
+		{"1_000_000 == 1000000", "1000000 == 1000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			n, err := parseExpr(tt.src)
+			if err != nil {
+				t.Fatalf("parseExpr(%q): %v", tt.src, err)
+			}
+			if got := n.String(); got != tt.want {
+				t.Errorf("parseExpr(%q) = %s, want %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		// Arithmetic and precedence
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"10 - 4 - 3", 3.0},
+		{"7 / 2", 3.5},
+		{"1 / 0", nil},
+		{"evidence.amount * 2", 500.0},
+		{"evidence.currency + 1", nil},
+		{"!false && false", false},
+		{"true || false && false", true},
+
+		// Missing fields are null and compare false
+		{"evidence.missing", nil},
+		{"evidence.missing == null", true},
+		{"evidence.missing != null", false},
+		{"evidence.missing > 0", false},
+		{"evidence.missing <= 0", false},
+		{"actor.id.deeper", nil},
+
+		// NaN orders against nothing
+		{"evidence.nan > 0", false},
+		{"evidence.nan <= 0", false},
+		{"evidence.nan == evidence.nan", false},
+		{"is_number(evidence.nan)", true},
+
+		// Mismatched types are unequal and unordered, never an error
+		{`evidence.amount == "250"`, false},
+		{`evidence.amount != "250"`, true},
+		{`evidence.currency > 1`, false},
+		{`"b" > "a"`, true},
+		{"evidence.flag == true", true},
+		{"evidence.amount && true", false},
+
+		// Lists and functions
+		{`source in ["api", "batch"]`, true},
+		{`evidence.currency in evidence.missing`, false},
+		{`contains(evidence.tags, "b")`, true},
+		{`contains(evidence.currency, "U")`, true},
+		{`lower(actor.type) == "service"`, true},
+		{`upper(evidence.missing)`, nil},
+		{`starts_with(actor.id, "user-")`, true},
+		{`len(evidence.tags)`, 2.0},
+		{`len(evidence.note)`, 5.0},
+		{`exists(evidence.missing)`, false},
+		{`is_string(source)`, true},
+
+		// Identifiers and strings beyond ASCII
+		{`actor.metadata.région == "eu"`, true},
+		{`evidence.note == "café"`, true},
+		{`evidence.note == 'caf\é'`, true},
+		{`"naïve" < "naïvf"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			n, err := parseExpr(tt.src)
+			if err != nil {
+				t.Fatalf("parseExpr(%q): %v", tt.src, err)
+			}
+			if got := n.eval(testEnv); got != tt.want {
+				t.Errorf("eval(%q) = %#v, want %#v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"amount {{evidence.amount}}", "amount 250"},
+		{"amount {{evidence.amount:%.2f}}", "amount 250.00"},
+		{"{{evidence.amount * 2:%08.1f}} doubled", "000500.0 doubled"},
+		{"{{evidence.missing:%.2f}}", "null"},
+		{"{{evidence.currency:%.2f}} is not a number", "EUR is not a number"},
+		{"{{evidence.tags}} and {{evidence.flag}}", `["a","b"] and true`},
+		{"note: {{evidence.note}}", "note: café"},
+		{"{{actor.metadata.région}}", "eu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			segments, err := parseTemplate(tt.src, roots)
+			if err != nil {
+				t.Fatalf("parseTemplate(%q): %v", tt.src, err)
+			}
+			if got := render(segments, testEnv); got != tt.want {
+				t.Errorf("render(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"amount {{evidence.amount", "unclosed {{"},
+		{"{{evidence.amount:%d}}", `invalid number format "%d"`},
+		{"{{amount}}", `unknown name "amount"`},
+		{"{{limit.total}}", `unknown name "limit"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := parseTemplate(tt.src, roots)
+			if err == nil {
+				t.Fatalf("parseTemplate(%q) succeeded, want error containing %q", tt.src, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseTemplate(%q) = %v, want error containing %q", tt.src, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseTemplate("{{limit.total:%.2f}}", limitRoots); err != nil {
+		t.Errorf("limit reason: %v", err)
+	}
+}
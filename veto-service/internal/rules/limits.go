@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"time"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// LimitResult is a limit checked against an actor's recent activity
+type LimitResult struct {
+	LimitID string  `json:"limit_id"`
+	Prior   float64 `json:"prior"`  // count or sum of the actor's earlier events in the window
+	Amount  float64 `json:"amount"` // what the checked event adds
+	Total   float64 `json:"total"`
+	Max     float64 `json:"max"`
+	Fired   bool    `json:"fired"`
+	Match   *Match  `json:"-"`
+}
+
+// LimitsFor returns the limits covering an event type, in file order
+func (rs *Ruleset) LimitsFor(eventType string) []*Limit {
+	var limits []*Limit
+	for i := range rs.Limits {
+		if rs.Limits[i].Applies(eventType) {
+			limits = append(limits, &rs.Limits[i])
+		}
+	}
+	return limits
+}
+
+// Applies reports whether the limit covers an event type
+func (l *Limit) Applies(eventType string) bool {
+	for _, t := range l.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WindowDuration returns the parsed window
+func (l *Limit) WindowDuration() time.Duration {
+	return l.window
+}
+
+// Amount returns what an event adds to the limit's total: one for a count,
+// or the summed evidence field, which adds nothing if it is not a number
+func (l *Limit) Amount(event models.Event) float64 {
+	if l.Sum == "" {
+		return 1
+	}
+	if f, ok := normalize(event.Evidence[l.Sum]).(float64); ok {
+		return f
+	}
+	return 0
+}
+
+// Check adds the event to the actor's prior activity and compares the total
+// against the limit
+func (l *Limit) Check(event models.Event, prior float64) LimitResult {
+	result := LimitResult{
+		LimitID: l.ID,
+		Prior:   prior,
+		Amount:  l.Amount(event),
+		Max:     l.Max,
+	}
+	result.Total = result.Prior + result.Amount
+	result.Fired = result.Total > l.Max
+
+	if result.Fired {
+		env := Env(event)
+		env["limit"] = map[string]any{
+			"total":  result.Total,
+			"prior":  result.Prior,
+			"max":    result.Max,
+			"window": l.Window,
+		}
+		result.Match = &Match{RuleID: l.ID, Severity: l.Severity, Reason: render(l.reason, env)}
+	}
+	return result
+}
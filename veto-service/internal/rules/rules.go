@@ -0,0 +1,405 @@
+// Package rules evaluates declarative business rules against events
+// A ruleset is loaded from a YAML or JSON file, validated as a whole, and
+// swapped in atomically so a bad edit never replaces a working ruleset
+package rules
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities
+const (
+	SeverityVeto = "veto" // the event is rejected
+	SeverityWarn = "warn" // the event passes; the match is reported
+)
+
+// Actor registration policies
+const (
+	RegistrationAuto          = "auto"          // unknown actors are registered on their first accepted event
+	RegistrationPreregistered = "preregistered" // unknown actors are rejected
+)
+
+// MaxLimitWindow is the longest window a limit may use
+const MaxLimitWindow = 7 * 24 * time.Hour
+
+// ErrInvalidRuleset is returned for a ruleset that fails validation
+var ErrInvalidRuleset = errors.New("invalid ruleset")
+
+// Rule is one rule as written in a rules file
+type Rule struct {
+	ID          string   `yaml:"id" json:"id"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	EventTypes  []string `yaml:"event_types,omitempty" json:"event_types,omitempty"` // empty matches every type
+	When        string   `yaml:"when" json:"when"`                                   // the rule fires when this is true
+	Severity    string   `yaml:"severity,omitempty" json:"severity,omitempty"`       // veto (default) or warn
+	Reason      string   `yaml:"reason" json:"reason"`                               // template, e.g. "amount {{evidence.amount:%.2f}}"
+
+	when   node
+	reason []segment
+}
+
+// Limit caps an actor's activity over a sliding window: the number of
+// events, or the sum of an evidence field, including the event being checked
+type Limit struct {
+	ID          string   `yaml:"id" json:"id"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	EventTypes  []string `yaml:"event_types" json:"event_types"`     // events of these types are counted together
+	Window      string   `yaml:"window" json:"window"`               // e.g. 1h or 24h
+	Sum         string   `yaml:"sum,omitempty" json:"sum,omitempty"` // evidence field to sum; empty counts events
+	Max         float64  `yaml:"max" json:"max"`                     // the limit fires when the total exceeds this
+	Severity    string   `yaml:"severity,omitempty" json:"severity,omitempty"`
+	Reason      string   `yaml:"reason" json:"reason"` // template; limit.total, limit.prior, limit.max and limit.window are available
+
+	window time.Duration
+	reason []segment
+}
+
+// ActorRegistration decides, per event type, whether an unknown actor is
+// registered on first sight or has to be registered beforehand
+type ActorRegistration struct {
+	Default    string            `yaml:"default,omitempty" json:"default"`                   // auto (default) or preregistered
+	EventTypes map[string]string `yaml:"event_types,omitempty" json:"event_types,omitempty"` // overrides by event type
+}
+
+// Policy returns the registration policy for an event type
+func (a ActorRegistration) Policy(eventType string) string {
+	if policy, ok := a.EventTypes[eventType]; ok {
+		return policy
+	}
+	return a.Default
+}
+
+// File is the layout of a rules file
+type File struct {
+	Version           string            `yaml:"version,omitempty" json:"version,omitempty"`
+	ActorRegistration ActorRegistration `yaml:"actor_registration,omitempty" json:"actor_registration,omitempty"`
+	Rules             []Rule            `yaml:"rules" json:"rules"`
+	Limits            []Limit           `yaml:"limits,omitempty" json:"limits,omitempty"`
+}
+
+// Ruleset is a validated, immutable set of rules
+type Ruleset struct {
+	Version           string            `json:"version"`
+	Checksum          string            `json:"checksum"`
+	Source            string            `json:"source"`
+	ActorRegistration ActorRegistration `json:"actor_registration"`
+	Rules             []Rule            `json:"rules"`
+	Limits            []Limit           `json:"limits"`
+}
+
+// Match is a rule that fired for an event
+type Match struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+// Parse decodes and validates a ruleset; format is "yaml" or "json"
+// The version is the file's own version, or a checksum of its content
+func Parse(data []byte, format, source string) (*Ruleset, error) {
+	var file File
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRuleset, err)
+		}
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRuleset, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidRuleset, format)
+	}
+
+	if err := compile(file.Rules, file.Limits, &file.ActorRegistration); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	rs := &Ruleset{
+		Version:           file.Version,
+		Checksum:          hex.EncodeToString(sum[:])[:12],
+		Source:            source,
+		ActorRegistration: file.ActorRegistration,
+		Rules:             file.Rules,
+		Limits:            file.Limits,
+	}
+	if rs.Limits == nil {
+		rs.Limits = []Limit{}
+	}
+	if rs.Version == "" {
+		rs.Version = "sha256:" + rs.Checksum
+	}
+	return rs, nil
+}
+
+// compile validates every rule, limit and the registration policy, and
+// reports all problems at once
+func compile(rules []Rule, limits []Limit, registration *ActorRegistration) error {
+	var problems []string
+	seen := make(map[string]bool, len(rules))
+
+	if registration.Default == "" {
+		registration.Default = RegistrationAuto
+	}
+	validPolicy := func(policy string) bool {
+		return policy == RegistrationAuto || policy == RegistrationPreregistered
+	}
+	if !validPolicy(registration.Default) {
+		problems = append(problems, fmt.Sprintf("actor_registration: unknown default %q (expected: auto or preregistered)", registration.Default))
+	}
+	eventTypes := make([]string, 0, len(registration.EventTypes))
+	for eventType := range registration.EventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	for _, eventType := range eventTypes {
+		if policy := registration.EventTypes[eventType]; !validPolicy(policy) {
+			problems = append(problems, fmt.Sprintf("actor_registration: unknown policy %q for %s (expected: auto or preregistered)", policy, eventType))
+		}
+	}
+
+	if len(rules) == 0 && len(limits) == 0 {
+		problems = append(problems, "no rules defined")
+	}
+
+	for i := range rules {
+		r := &rules[i]
+		name := fmt.Sprintf("rule %d", i+1)
+		if r.ID != "" {
+			name = fmt.Sprintf("rule %q", r.ID)
+		}
+
+		switch {
+		case r.ID == "":
+			problems = append(problems, name+": id is required")
+		case seen[r.ID]:
+			problems = append(problems, name+": duplicate id")
+		}
+		seen[r.ID] = true
+
+		for _, t := range r.EventTypes {
+			if strings.TrimSpace(t) == "" {
+				problems = append(problems, name+": empty event type")
+			}
+		}
+
+		if r.Severity == "" {
+			r.Severity = SeverityVeto
+		}
+		if r.Severity != SeverityVeto && r.Severity != SeverityWarn {
+			problems = append(problems, fmt.Sprintf("%s: unknown severity %q (expected: veto or warn)", name, r.Severity))
+		}
+
+		if strings.TrimSpace(r.When) == "" {
+			problems = append(problems, name+": when is required")
+		} else if when, err := parseExpr(r.When); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: when: %v", name, err))
+		} else {
+			r.when = when
+		}
+
+		if strings.TrimSpace(r.Reason) == "" {
+			problems = append(problems, name+": reason is required")
+		} else if reason, err := parseTemplate(r.Reason, roots); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: reason: %v", name, err))
+		} else {
+			r.reason = reason
+		}
+	}
+
+	for i := range limits {
+		l := &limits[i]
+		name := fmt.Sprintf("limit %d", i+1)
+		if l.ID != "" {
+			name = fmt.Sprintf("limit %q", l.ID)
+		}
+
+		// Rules and limits share one namespace, as both are reported by ID
+		switch {
+		case l.ID == "":
+			problems = append(problems, name+": id is required")
+		case seen[l.ID]:
+			problems = append(problems, name+": duplicate id")
+		}
+		seen[l.ID] = true
+
+		if len(l.EventTypes) == 0 {
+			problems = append(problems, name+": event_types is required")
+		}
+		for _, t := range l.EventTypes {
+			if strings.TrimSpace(t) == "" {
+				problems = append(problems, name+": empty event type")
+			}
+		}
+
+		if window, err := time.ParseDuration(l.Window); err != nil || window < time.Minute {
+			problems = append(problems, fmt.Sprintf("%s: window must be a duration of at least 1m, got %q", name, l.Window))
+		} else if window > MaxLimitWindow {
+			problems = append(problems, fmt.Sprintf("%s: window must not exceed %s", name, MaxLimitWindow))
+		} else {
+			l.window = window
+		}
+
+		if l.Max < 0 {
+			problems = append(problems, name+": max must not be negative")
+		}
+
+		if l.Severity == "" {
+			l.Severity = SeverityVeto
+		}
+		if l.Severity != SeverityVeto && l.Severity != SeverityWarn {
+			problems = append(problems, fmt.Sprintf("%s: unknown severity %q (expected: veto or warn)", name, l.Severity))
+		}
+
+		if strings.TrimSpace(l.Reason) == "" {
+			problems = append(problems, name+": reason is required")
+		} else if reason, err := parseTemplate(l.Reason, limitRoots); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: reason: %v", name, err))
+		} else {
+			l.reason = reason
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidRuleset, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Applies reports whether the rule covers an event type
+func (r *Rule) Applies(eventType string) bool {
+	if len(r.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range r.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate returns the rules that fire for an event, in file order
+func (rs *Ruleset) Evaluate(event models.Event) []Match {
+	env := Env(event)
+
+	var matches []Match
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if !r.Applies(event.Type) || !truthy(r.when.eval(env)) {
+			continue
+		}
+		matches = append(matches, Match{
+			RuleID:   r.ID,
+			Severity: r.Severity,
+			Reason:   render(r.reason, env),
+		})
+	}
+	return matches
+}
+
+// Env exposes an event to expressions
+func Env(event models.Event) map[string]any {
+	evidence := make(map[string]any, len(event.Evidence))
+	for key, value := range event.Evidence {
+		evidence[key] = normalize(value)
+	}
+
+	actor := map[string]any{
+		"id":   event.Actor.ID,
+		"name": event.Actor.Name,
+		"type": event.Actor.Type,
+	}
+	if event.Actor.Metadata != nil {
+		actor["metadata"] = normalize(event.Actor.Metadata)
+	}
+
+	return map[string]any{
+		"evidence": evidence,
+		"actor":    actor,
+		"source":   event.Source,
+		"type":     event.Type,
+	}
+}
+
+// segment is literal text or an interpolated expression of a reason template
+type segment struct {
+	text   string
+	expr   node
+	format string // fmt verb applied to numbers, e.g. %.2f
+}
+
+// parseTemplate splits a reason into text and {{expression}} or
+// {{expression:%verb}} segments; expression paths start with one of names
+func parseTemplate(src string, names map[string]bool) ([]segment, error) {
+	var segments []segment
+	rest := src
+	for {
+		open := strings.Index(rest, "{{")
+		if open < 0 {
+			if rest != "" {
+				segments = append(segments, segment{text: rest})
+			}
+			return segments, nil
+		}
+		if open > 0 {
+			segments = append(segments, segment{text: rest[:open]})
+		}
+
+		end := strings.Index(rest[open:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed {{ in %q", src)
+		}
+		inner := rest[open+2 : open+end]
+		rest = rest[open+end+2:]
+
+		seg := segment{}
+		if colon := strings.LastIndex(inner, ":%"); colon >= 0 {
+			seg.format = strings.TrimSpace(inner[colon+1:])
+			inner = inner[:colon]
+			if check := fmt.Sprintf(seg.format, 1.0); strings.Contains(check, "%!") {
+				return nil, fmt.Errorf("invalid number format %q", seg.format)
+			}
+		}
+		expr, err := parseExprWith(inner, names)
+		if err != nil {
+			return nil, fmt.Errorf("{{%s}}: %v", inner, err)
+		}
+		seg.expr = expr
+		segments = append(segments, seg)
+	}
+}
+
+// render fills in a reason template
+func render(segments []segment, env map[string]any) string {
+	var sb strings.Builder
+	for _, seg := range segments {
+		if seg.expr == nil {
+			sb.WriteString(seg.text)
+			continue
+		}
+		value := seg.expr.eval(env)
+		if f, ok := value.(float64); ok && seg.format != "" {
+			sb.WriteString(fmt.Sprintf(seg.format, f))
+			continue
+		}
+		sb.WriteString(formatValue(value))
+	}
+	return sb.String()
+}
@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// The built-in ruleset replaced the validator's hard-coded payment and
+// withdrawal checks; these are the verdicts those checks gave, one reason at
+// most, as the first failing check ended validation
+
+func TestBuiltinRulesetVerdicts(t *testing.T) {
+	rs, err := Parse(defaultRules, "yaml", "built-in")
+	if err != nil {
+		t.Fatalf("built-in rules: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		eventType string
+		evidence  map[string]any
+		reason    string // empty passes
+	}{
+		{"payment", "payment_processed", map[string]any{"amount": 250.0}, ""},
+		{"payment at limit", "payment_processed", map[string]any{"amount": 1000000.0}, ""},
+		{"payment over limit", "payment_processed", map[string]any{"amount": 1000000.01}, "payment amount exceeds limit: 1000000.01"},
+		{"payment zero", "payment_processed", map[string]any{"amount": 0.0}, "payment amount must be positive, got: 0.00"},
+		{"payment negative", "payment_processed", map[string]any{"amount": -12.5}, "payment amount must be positive, got: -12.50"},
+		{"payment without amount", "payment_processed", map[string]any{}, "payment amount is missing or invalid"},
+		{"payment string amount", "payment_processed", map[string]any{"amount": "100"}, "payment amount is missing or invalid"},
+		{"payment null amount", "payment_processed", map[string]any{"amount": nil}, "payment amount is missing or invalid"},
+		{"payment NaN amount", "payment_processed", map[string]any{"amount": math.NaN()}, ""},
+
+		{"withdrawal", "withdrawal", map[string]any{"amount": 500.0}, ""},
+		{"withdrawal at limit", "withdrawal", map[string]any{"amount": 10000.0}, ""},
+		{"withdrawal over limit", "withdrawal", map[string]any{"amount": 10000.5}, "withdrawal amount exceeds daily limit: 10000.50"},
+		{"withdrawal zero", "withdrawal", map[string]any{"amount": 0.0}, "withdrawal amount must be positive"},
+		{"withdrawal negative", "withdrawal", map[string]any{"amount": -1.0}, "withdrawal amount must be positive"},
+		{"withdrawal without amount", "withdrawal", map[string]any{}, "withdrawal amount is missing or invalid"},
+		{"withdrawal bool amount", "withdrawal", map[string]any{"amount": true}, "withdrawal amount is missing or invalid"},
+
+		{"other type", "login", map[string]any{"amount": -1.0}, ""},
+		{"other type without evidence", "login", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := models.Event{Type: tt.eventType, Evidence: tt.evidence}
+
+			var reasons []string
+			for _, match := range rs.Evaluate(event) {
+				if match.Severity == SeverityVeto {
+					reasons = append(reasons, match.Reason)
+				}
+			}
+			if got := strings.Join(reasons, "; "); got != tt.reason {
+				t.Errorf("reason = %q, want %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+func TestBuiltinRulesetDecodedAmounts(t *testing.T) {
+	rs, err := Parse(defaultRules, "yaml", "built-in")
+	if err != nil {
+		t.Fatalf("built-in rules: %v", err)
+	}
+
+	// Events reach the validator as JSON; amounts decode the same either way
+	for _, body := range []string{`{"amount": 20000}`, `{"amount": 2e4}`} {
+		dec := json.NewDecoder(strings.NewReader(body))
+		dec.UseNumber()
+		var evidence map[string]any
+		if err := dec.Decode(&evidence); err != nil {
+			t.Fatal(err)
+		}
+
+		matches := rs.Evaluate(models.Event{Type: "withdrawal", Evidence: evidence})
+		if len(matches) != 1 || matches[0].Reason != "withdrawal amount exceeds daily limit: 20000.00" {
+			t.Errorf("%s: matches = %+v", body, matches)
+		}
+	}
+}
+
+func TestBuiltinLimits(t *testing.T) {
+	rs, err := Parse(defaultRules, "yaml", "built-in")
+	if err != nil {
+		t.Fatalf("built-in rules: %v", err)
+	}
+
+	limits := rs.LimitsFor("withdrawal")
+	if len(limits) != 2 || limits[0].ID != "withdrawal-daily-total" || limits[1].ID != "withdrawal-velocity" {
+		t.Fatalf("withdrawal limits = %+v", limits)
+	}
+	if len(rs.LimitsFor("payment_processed")) != 0 {
+		t.Errorf("payments are not limited")
+	}
+
+	event := models.Event{Type: "withdrawal", Evidence: map[string]any{"amount": 4000.0}}
+	if result := limits[0].Check(event, 6000); result.Fired {
+		t.Errorf("total of exactly 10000 fired: %+v", result)
+	}
+	result := limits[0].Check(event, 6000.01)
+	if !result.Fired || result.Match.Reason != "daily withdrawals would reach 10000.01, limit is 10000.00" {
+		t.Errorf("over the daily total: %+v %+v", result, result.Match)
+	}
+
+	result = limits[1].Check(event, 10)
+	if !result.Fired || result.Match.Reason != "too many withdrawals: 11 in 1h, limit is 10" {
+		t.Errorf("over the velocity: %+v %+v", result, result.Match)
+	}
+}
+
+func TestParseRejectsInvalidRulesets(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{
+			name: "every problem reported",
+			file: `
+rules:
+  - id: a
+    when: "evidence.amount >"
+    reason: x
+  - id: a
+    severity: block
+    when: "true"
+    reason: "{{evidence.amount"
+limits:
+  - id: l
+    event_types: [withdrawal]
+    window: 30s
+    max: -1
+    reason: ok
+`,
+			want: []string{
+				`rule "a": when:`,
+				`rule "a": duplicate id`,
+				`unknown severity "block"`,
+				`rule "a": reason: unclosed {{`,
+				`limit "l": window must be a duration of at least 1m`,
+				`limit "l": max must not be negative`,
+			},
+		},
+		{
+			name: "unknown field",
+			file: "rules:\n  - id: a\n    when: \"true\"\n    reason: x\n    action: drop\n",
+			want: []string{"field action not found"},
+		},
+		{
+			name: "empty",
+			file: "version: x\n",
+			want: []string{"no rules defined"},
+		},
+		{
+			name: "registration policy",
+			file: "actor_registration:\n  event_types:\n    withdrawal: manual\nrules:\n  - id: a\n    when: \"true\"\n    reason: x\n",
+			want: []string{`unknown policy "manual" for withdrawal`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.file), "yaml", "test")
+			if err == nil {
+				t.Fatal("Parse succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
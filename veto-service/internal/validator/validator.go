@@ -2,23 +2,32 @@ package validator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/veps-service-480701/veto-service/internal/client"
+	"github.com/veps-service-480701/veto-service/internal/rules"
+	"github.com/veps-service-480701/veto-service/internal/velocity"
 	"github.com/veps-service-480701/veto-service/pkg/models"
 )
 
 // Validator performs integrity and feasibility checks on events
 type Validator struct {
 	rdbClient *client.RDBClient
+	rules     *rules.Engine
+	velocity  *velocity.Tracker
 }
 
 // New creates a new Validator instance
-func New(rdbClient *client.RDBClient) *Validator {
+func New(rdbClient *client.RDBClient, engine *rules.Engine, tracker *velocity.Tracker) *Validator {
 	return &Validator{
 		rdbClient: rdbClient,
+		rules:     engine,
+		velocity:  tracker,
 	}
 }
 
@@ -28,216 +37,506 @@ type ValidationError struct {
 	Reason string
 }
 
+// Result is the outcome of validating one event
+type Result struct {
+	Passed         bool
+	Errors         []ValidationError
+	Warnings       []rules.Match // warn rules that fired; they do not veto
+	RulesetVersion string        // ruleset the event was checked against
+	Checks         []CheckTrace  // one trace per check, in the order they ran
+}
+
+// CheckTrace explains how one check reached its result
+type CheckTrace struct {
+	Check    string             `json:"check"`
+	Passed   bool               `json:"passed"`
+	Reason   string             `json:"reason,omitempty"`
+	Note     string             `json:"note,omitempty"`
+	Inputs   map[string]any     `json:"inputs"`
+	Compared []rules.Comparison `json:"comparisons,omitempty"`
+	Rules    []rules.RuleTrace  `json:"rules,omitempty"`
+	Duration string             `json:"duration"`
+}
+
 // Validate performs all validation checks on an event
-// Returns true if all checks pass, false with reasons if any fail
-func (v *Validator) Validate(ctx context.Context, event models.Event) (bool, []ValidationError, error) {
-	startTime := time.Now()
-	var errors []ValidationError
+// The result passes if all checks pass, and lists the reasons if any fail
+func (v *Validator) Validate(ctx context.Context, event models.Event) (*Result, error) {
+	return v.validate(ctx, event, false)
+}
 
-	log.Printf("[Validator] Starting validation for event %s (type: %s)", event.ID, event.Type)
+// Explain runs the same checks as Validate and also traces every rule
+// It only reads, so an explained event is never counted or recorded
+func (v *Validator) Explain(ctx context.Context, event models.Event) (*Result, error) {
+	return v.validate(ctx, event, true)
+}
 
-	// Check 1: Causality Check
-	causalityPassed, causalityErr := v.checkCausality(ctx, event)
-	if causalityErr != nil {
-		return false, nil, fmt.Errorf("causality check error: %w", causalityErr)
-	}
-	if !causalityPassed.Passed {
-		errors = append(errors, ValidationError{
-			Check:  "causality",
-			Reason: causalityPassed.Reason,
-		})
-	}
+func (v *Validator) validate(ctx context.Context, event models.Event, explain bool) (*Result, error) {
+	startTime := time.Now()
 
-	// Check 2: Actor Existence Check
-	actorPassed, actorErr := v.checkActorExists(ctx, event)
-	if actorErr != nil {
-		return false, nil, fmt.Errorf("actor check error: %w", actorErr)
-	}
-	if !actorPassed.Passed {
-		errors = append(errors, ValidationError{
-			Check:  "actor_existence",
-			Reason: actorPassed.Reason,
-		})
+	// A reload mid-validation must not mix two rulesets
+	rs := v.rules.Current()
+	result := &Result{RulesetVersion: rs.Version}
+	registerActor := false
+
+	log.Printf("[Validator] Starting validation for event %s (type: %s, explain: %v)", event.ID, event.Type, explain)
+
+	checks := []struct {
+		name string
+		run  func() (CheckResult, error)
+	}{
+		// Check 1: Causality Check
+		{"causality", func() (CheckResult, error) { return v.checkCausality(ctx, event) }},
+		// Check 2: Actor Existence Check
+		{"actor_existence", func() (CheckResult, error) {
+			check, register, err := v.checkActorExists(ctx, event, rs)
+			registerActor = register
+			return check, err
+		}},
+		// Check 3: Business Rules Check (declarative rules for the event type)
+		{"business_rules", func() (CheckResult, error) {
+			check, warnings := v.checkBusinessRules(ctx, event, rs, explain)
+			result.Warnings = warnings
+			return check, nil
+		}},
+		// Check 4: Velocity Check (per-actor limits over a sliding window)
+		{"velocity", func() (CheckResult, error) {
+			check, warnings, err := v.checkVelocity(ctx, event, rs)
+			result.Warnings = append(result.Warnings, warnings...)
+			return check, err
+		}},
+		// Check 5: Temporal Check (timestamp sanity)
+		{"temporal", func() (CheckResult, error) { return v.checkTemporal(ctx, event) }},
+		// Check 6: Balance Check (withdrawals only); it runs last because an
+		// accepted withdrawal holds its funds
+		{"balance", func() (CheckResult, error) {
+			return v.checkBalance(ctx, event, !explain && len(result.Errors) == 0)
+		}},
 	}
 
-	// Check 3: Business Rules Check (type-specific validation)
-	businessPassed, businessErr := v.checkBusinessRules(ctx, event)
-	if businessErr != nil {
-		return false, nil, fmt.Errorf("business rules check error: %w", businessErr)
-	}
-	if !businessPassed.Passed {
-		errors = append(errors, ValidationError{
-			Check:  "business_rules",
-			Reason: businessPassed.Reason,
+	for _, c := range checks {
+		checkStart := time.Now()
+		check, err := c.run()
+		if err != nil {
+			return nil, fmt.Errorf("%s check error: %w", c.name, err)
+		}
+
+		if !check.Passed {
+			result.Errors = append(result.Errors, ValidationError{
+				Check:  c.name,
+				Reason: check.Reason,
+			})
+		}
+		result.Checks = append(result.Checks, CheckTrace{
+			Check:    c.name,
+			Passed:   check.Passed,
+			Reason:   check.Reason,
+			Note:     check.Note,
+			Inputs:   check.Inputs,
+			Compared: check.Compared,
+			Rules:    check.Rules,
+			Duration: time.Since(checkStart).String(),
 		})
 	}
 
-	// Check 4: Temporal Check (timestamp sanity)
-	temporalPassed, temporalErr := v.checkTemporal(ctx, event)
-	if temporalErr != nil {
-		return false, nil, fmt.Errorf("temporal check error: %w", temporalErr)
-	}
-	if !temporalPassed.Passed {
-		errors = append(errors, ValidationError{
-			Check:  "temporal",
-			Reason: temporalPassed.Reason,
-		})
+	result.Passed = len(result.Errors) == 0
+
+	// Unknown actors are only registered, and events only counted towards
+	// limits, when the event is accepted; never by an explain
+	if result.Passed && !explain {
+		if registerActor {
+			if _, err := v.rdbClient.RegisterActor(ctx, event.Actor); err != nil {
+				log.Printf("[Validator] Warning: failed to auto-register actor %s: %v", event.Actor.ID, err)
+			} else {
+				log.Printf("[Validator] Actor %s auto-registered by event %s", event.Actor.ID, event.ID)
+			}
+		}
+		v.velocity.Record(event)
 	}
 
 	duration := time.Since(startTime)
-	passed := len(errors) == 0
 
-	log.Printf("[Validator] Validation complete for event %s: passed=%v, duration=%s", 
-		event.ID, passed, duration)
+	log.Printf("[Validator] Validation complete for event %s: passed=%v, rules=%s, duration=%s",
+		event.ID, result.Passed, rs.Version, duration)
 
-	return passed, errors, nil
+	return result, nil
 }
 
 // CheckResult represents the result of a single validation check
+// Inputs, Compared, Note and Rules feed the check's trace
 type CheckResult struct {
-	Passed bool
-	Reason string
+	Passed   bool
+	Reason   string
+	Note     string
+	Inputs   map[string]any
+	Compared []rules.Comparison
+	Rules    []rules.RuleTrace
 }
 
 // checkCausality verifies that all causal dependencies are satisfied
 func (v *Validator) checkCausality(ctx context.Context, event models.Event) (CheckResult, error) {
-	// If vector clock is empty or only has current node, no dependencies to check
-	if len(event.VectorClock) <= 1 {
-		return CheckResult{Passed: true}, nil
+	inputs := map[string]any{"vector_clock": event.VectorClock}
+
+	// An empty vector clock carries no dependencies to check
+	if len(event.VectorClock) == 0 {
+		return CheckResult{Passed: true, Inputs: inputs, Note: "empty vector clock, no dependencies to check"}, nil
 	}
 
-	// Check if all events referenced in the vector clock exist in the database
-	satisfied, missingNodes, err := v.rdbClient.CheckCausality(ctx, event.VectorClock)
+	// Check that the direct predecessor of every foreign vector clock entry exists in
+	// the database. The event's own node is skipped: its predecessor was ingested
+	// by the same boundary adapter, whose outbox stores it asynchronously
+	originNode := event.Metadata.BoundaryNode
+	satisfied, missing, err := v.rdbClient.CheckCausality(ctx, event.VectorClock, originNode)
 	if err != nil {
 		return CheckResult{Passed: false}, err
 	}
 
+	missingByNode := make(map[string]int64, len(missing))
+	for _, dep := range missing {
+		missingByNode[dep.Node] = dep.ExpectedCounter
+	}
+	var compared []rules.Comparison
+	for node, counter := range event.VectorClock {
+		if node == originNode {
+			continue
+		}
+		expected, isMissing := missingByNode[node]
+		compared = append(compared, rules.Comparison{
+			Expr:   fmt.Sprintf("predecessor of %s@%d is stored", node, counter),
+			Op:     "stored",
+			Left:   node,
+			Right:  expected,
+			Result: !isMissing,
+		})
+	}
+	sort.Slice(compared, func(i, j int) bool { return compared[i].Expr < compared[j].Expr })
+
 	if !satisfied {
-		reason := fmt.Sprintf("causal dependencies not satisfied, missing nodes: %v", missingNodes)
+		deps := make([]string, 0, len(missing))
+		for _, dep := range missing {
+			deps = append(deps, fmt.Sprintf("%s@%d", dep.Node, dep.ExpectedCounter))
+		}
+		reason := fmt.Sprintf("causal dependencies not satisfied, missing: %s", strings.Join(deps, ", "))
 		log.Printf("[Validator] Causality check failed for event %s: %s", event.ID, reason)
 		return CheckResult{
-			Passed: false,
-			Reason: reason,
+			Passed:   false,
+			Reason:   reason,
+			Inputs:   inputs,
+			Compared: compared,
 		}, nil
 	}
 
-	return CheckResult{Passed: true}, nil
+	return CheckResult{Passed: true, Inputs: inputs, Compared: compared}, nil
 }
 
-// checkActorExists verifies the actor has previous activity in the system
-func (v *Validator) checkActorExists(ctx context.Context, event models.Event) (CheckResult, error) {
-	// For now, we'll do a simple check: if this is not the first event from this actor,
-	// they should have history. For MVP, we'll be lenient and just log.
-	
-	// In production, you might query for previous events from this actor
-	// For now, we'll pass this check (trust but verify pattern)
-	
-	log.Printf("[Validator] Actor check for %s: accepting (lenient mode)", event.Actor.ID)
-	return CheckResult{Passed: true}, nil
-}
+// checkActorExists verifies the actor is registered and not suspended
+// An unknown actor passes if the ruleset lets its event type auto-register
+// actors; the returned flag asks for it to be registered once the event is
+// accepted
+func (v *Validator) checkActorExists(ctx context.Context, event models.Event, rs *rules.Ruleset) (CheckResult, bool, error) {
+	policy := rs.ActorRegistration.Policy(event.Type)
+	check := CheckResult{
+		Inputs: map[string]any{
+			"actor_id":     event.Actor.ID,
+			"actor_type":   event.Actor.Type,
+			"registration": policy,
+		},
+	}
+
+	if event.Actor.ID == "" {
+		check.Reason = "actor ID is missing"
+		return check, false, nil
+	}
+
+	actor, err := v.rdbClient.GetActor(ctx, event.Actor.ID)
+	if err != nil {
+		return CheckResult{Passed: false}, false, err
+	}
+
+	if actor == nil {
+		if policy == rules.RegistrationAuto {
+			log.Printf("[Validator] Actor %s is not registered; registering on acceptance", event.Actor.ID)
+			check.Passed = true
+			check.Note = "actor is not registered and will be registered automatically if the event is accepted"
+			return check, true, nil
+		}
+		check.Reason = fmt.Sprintf("actor %s is not registered (%s events require a pre-registered actor)", event.Actor.ID, event.Type)
+		log.Printf("[Validator] Actor check failed for event %s: %s", event.ID, check.Reason)
+		return check, false, nil
+	}
 
-// checkBusinessRules applies type-specific business logic
-func (v *Validator) checkBusinessRules(ctx context.Context, event models.Event) (CheckResult, error) {
-	// Apply different rules based on event type
-	switch event.Type {
-	case "payment_processed":
-		return v.validatePayment(ctx, event)
-	case "user_login":
-		return v.validateLogin(ctx, event)
-	case "withdrawal":
-		return v.validateWithdrawal(ctx, event)
+	check.Inputs["registered"] = actor
+	check.Compared = []rules.Comparison{
+		{Expr: "status == \"active\"", Op: "==", Left: actor.Status, Right: "active", Result: actor.Status == "active"},
+	}
+	if event.Actor.Type != "" && actor.Type != "" {
+		check.Compared = append(check.Compared, rules.Comparison{
+			Expr: "actor.type == registered type", Op: "==", Left: event.Actor.Type, Right: actor.Type, Result: event.Actor.Type == actor.Type,
+		})
+	}
+
+	switch {
+	case actor.Status != "active":
+		check.Reason = fmt.Sprintf("actor %s is %s", actor.ID, actor.Status)
+		if actor.SuspendedReason != "" {
+			check.Reason += ": " + actor.SuspendedReason
+		}
+	case event.Actor.Type != "" && actor.Type != "" && event.Actor.Type != actor.Type:
+		check.Reason = fmt.Sprintf("actor %s is registered as type %q, event has %q", actor.ID, actor.Type, event.Actor.Type)
 	default:
-		// Unknown types pass by default (permissive for MVP)
-		log.Printf("[Validator] No specific business rules for type: %s", event.Type)
-		return CheckResult{Passed: true}, nil
+		check.Passed = true
+		return check, false, nil
 	}
+
+	log.Printf("[Validator] Actor check failed for event %s: %s", event.ID, check.Reason)
+	return check, false, nil
 }
 
-// validatePayment checks payment-specific rules
-func (v *Validator) validatePayment(ctx context.Context, event models.Event) (CheckResult, error) {
-	// Check if amount exists and is positive
-	amount, ok := event.Evidence["amount"].(float64)
-	if !ok {
-		return CheckResult{
-			Passed: false,
-			Reason: "payment amount is missing or invalid",
-		}, nil
+// checkBusinessRules evaluates the ruleset against the event
+// Every veto rule that fires contributes to the reason; warn rules only log
+func (v *Validator) checkBusinessRules(ctx context.Context, event models.Event, rs *rules.Ruleset, explain bool) (CheckResult, []rules.Match) {
+	check := CheckResult{
+		Inputs: map[string]any{
+			"type":            event.Type,
+			"source":          event.Source,
+			"actor":           event.Actor,
+			"evidence":        event.Evidence,
+			"ruleset_version": rs.Version,
+		},
 	}
 
-	if amount <= 0 {
-		return CheckResult{
-			Passed: false,
-			Reason: fmt.Sprintf("payment amount must be positive, got: %.2f", amount),
-		}, nil
+	var matches []rules.Match
+	if explain {
+		matches, check.Rules = rs.Explain(event)
+	} else {
+		matches = rs.Evaluate(event)
 	}
 
-	// Check if amount exceeds reasonable limits (e.g., $1M per transaction)
-	if amount > 1000000 {
-		return CheckResult{
-			Passed: false,
-			Reason: fmt.Sprintf("payment amount exceeds limit: %.2f", amount),
-		}, nil
+	var reasons []string
+	warnings := []rules.Match{}
+	for _, match := range matches {
+		if match.Severity == rules.SeverityWarn {
+			log.Printf("[Validator] Rule %s warned for event %s: %s", match.RuleID, event.ID, match.Reason)
+			warnings = append(warnings, match)
+			continue
+		}
+		log.Printf("[Validator] Rule %s vetoed event %s: %s", match.RuleID, event.ID, match.Reason)
+		reasons = append(reasons, match.Reason)
 	}
 
-	return CheckResult{Passed: true}, nil
+	check.Passed = len(reasons) == 0
+	check.Reason = strings.Join(reasons, "; ")
+	return check, warnings
 }
 
-// validateLogin checks login-specific rules
-func (v *Validator) validateLogin(ctx context.Context, event models.Event) (CheckResult, error) {
-	// Could check for rate limiting, suspicious IPs, etc.
-	// For MVP, just accept
-	return CheckResult{Passed: true}, nil
+// checkVelocity checks the actor's recent activity against every limit
+// covering the event type
+// Every veto limit the event would exceed contributes to the reason; warn
+// limits are returned as warnings
+func (v *Validator) checkVelocity(ctx context.Context, event models.Event, rs *rules.Ruleset) (CheckResult, []rules.Match, error) {
+	limits := rs.LimitsFor(event.Type)
+	check := CheckResult{
+		Passed: true,
+		Inputs: map[string]any{"actor_id": event.Actor.ID},
+	}
+	if len(limits) == 0 {
+		check.Note = "no limits cover this event type"
+		return check, nil, nil
+	}
+
+	var reasons []string
+	warnings := []rules.Match{}
+	results := make([]rules.LimitResult, 0, len(limits))
+	windows := make(map[string]any, len(limits))
+	for _, limit := range limits {
+		// A limit counts events of all its types together
+		var prior float64
+		perType := make(map[string]velocity.Totals, len(limit.EventTypes))
+		for _, eventType := range limit.EventTypes {
+			totals, err := v.velocity.Totals(ctx, event.Actor.ID, eventType, limit.Sum, limit.WindowDuration())
+			if err != nil {
+				return CheckResult{Passed: false}, nil, fmt.Errorf("limit %s: %w", limit.ID, err)
+			}
+			perType[eventType] = totals
+			if limit.Sum == "" {
+				prior += float64(totals.Count)
+			} else {
+				prior += totals.Sum
+			}
+		}
+		windows[limit.ID] = perType
+
+		lr := limit.Check(event, prior)
+		results = append(results, lr)
+
+		measure := "count"
+		if limit.Sum != "" {
+			measure = fmt.Sprintf("sum(evidence.%s)", limit.Sum)
+		}
+		check.Compared = append(check.Compared, rules.Comparison{
+			Expr:   fmt.Sprintf("%s: %s over %s > %g", limit.ID, measure, limit.Window, limit.Max),
+			Op:     ">",
+			Left:   lr.Total,
+			Right:  lr.Max,
+			Result: lr.Fired,
+		})
+
+		if !lr.Fired {
+			continue
+		}
+		if lr.Match.Severity == rules.SeverityWarn {
+			log.Printf("[Validator] Limit %s warned for event %s: %s", limit.ID, event.ID, lr.Match.Reason)
+			warnings = append(warnings, *lr.Match)
+			continue
+		}
+		log.Printf("[Validator] Limit %s vetoed event %s: %s", limit.ID, event.ID, lr.Match.Reason)
+		reasons = append(reasons, lr.Match.Reason)
+	}
+	check.Inputs["limits"] = results
+	check.Inputs["windows"] = windows
+
+	check.Passed = len(reasons) == 0
+	check.Reason = strings.Join(reasons, "; ")
+	return check, warnings, nil
 }
 
-// validateWithdrawal checks withdrawal-specific rules
-func (v *Validator) validateWithdrawal(ctx context.Context, event models.Event) (CheckResult, error) {
-	// In a real system, you'd check account balance here by querying RDB
-	// For MVP, we'll do a simple amount check
+// holdTTL is how long an accepted withdrawal holds its funds if it is never
+// sealed and its hold is not released; it covers the seal reaching the RDB
+// through the Boundary Adapter's outbox
+const holdTTL = 15 * time.Minute
+
+// Balance-moving event types, as the RDB applies them when they are sealed
+const (
+	eventTypePayment    = "payment_processed"
+	eventTypeWithdrawal = "withdrawal"
+)
+
+// checkBalance verifies a withdrawal does not overdraw the actor's balance,
+// and that neither a withdrawal nor a payment moves it by a non-positive amount.
+// Funds held by other accepted withdrawals that are not sealed yet are not
+// available. With reserve, the amount is held right away; the RDB places
+// holds of one actor one at a time, so two withdrawals validated at once
+// cannot both spend the same funds. The hold becomes a debit when the
+// withdrawal is sealed
+func (v *Validator) checkBalance(ctx context.Context, event models.Event, reserve bool) (CheckResult, error) {
+	check := CheckResult{
+		Passed: true,
+		Inputs: map[string]any{"actor_id": event.Actor.ID},
+	}
+	if event.Type != eventTypeWithdrawal && event.Type != eventTypePayment {
+		check.Note = "only withdrawals and payments move the balance"
+		return check, nil
+	}
+
 	amount, ok := event.Evidence["amount"].(float64)
 	if !ok {
-		return CheckResult{
-			Passed: false,
-			Reason: "withdrawal amount is missing or invalid",
-		}, nil
+		check.Note = "no numeric amount, so the balance is not moved; the business rules judge the amount"
+		return check, nil
 	}
+	check.Inputs["amount"] = amount
 
+	// Rulesets are configurable, so a negative amount is refused here whatever
+	// the active one says: it would turn a withdrawal into a credit
+	check.Compared = []rules.Comparison{
+		{Expr: "evidence.amount > 0", Op: ">", Left: amount, Right: 0, Result: amount > 0},
+	}
 	if amount <= 0 {
-		return CheckResult{
-			Passed: false,
-			Reason: "withdrawal amount must be positive",
-		}, nil
+		check.Passed = false
+		check.Reason = fmt.Sprintf("%s amount must be positive, got %.2f", event.Type, amount)
+		log.Printf("[Validator] Balance check failed for event %s: %s", event.ID, check.Reason)
+		return check, nil
+	}
+	if event.Type == eventTypePayment {
+		check.Note = "payments credit the balance and are not checked against it"
+		return check, nil
 	}
 
-	// For demo purposes, reject withdrawals over $10,000
-	if amount > 10000 {
-		return CheckResult{
-			Passed: false,
-			Reason: fmt.Sprintf("withdrawal amount exceeds daily limit: %.2f", amount),
-		}, nil
+	if !reserve {
+		balance, err := v.rdbClient.GetBalance(ctx, event.Actor.ID, event.ID.String())
+		if err != nil {
+			return CheckResult{Passed: false}, err
+		}
+		setBalanceInputs(&check, balance)
+
+		if balance.EventApplied != nil && *balance.EventApplied {
+			check.Note = "this withdrawal was already sealed and debited"
+			return check, nil
+		}
+		if compareAvailable(&check, event, amount, balance) {
+			check.Note = "funds are only held for an accepted withdrawal"
+		}
+		return check, nil
+	}
+
+	// The hold checks the available funds itself; a retried withdrawal finds
+	// its own hold, or its debit if it was sealed meanwhile, and passes again
+	held, err := v.rdbClient.HoldBalance(ctx, event.ID.String(), event.Actor.ID, amount, holdTTL)
+	switch {
+	case errors.Is(err, client.ErrInsufficientFunds):
+		setBalanceInputs(&check, held)
+		compareAvailable(&check, event, amount, held)
+		return check, nil
+	case err != nil:
+		return CheckResult{Passed: false}, err
+	}
+
+	setBalanceInputs(&check, held)
+	check.Note = fmt.Sprintf("funds held until the withdrawal is sealed, %.2f still available", held.Available)
+	return check, nil
+}
+
+// setBalanceInputs records a balance in a check's trace
+func setBalanceInputs(check *CheckResult, balance *models.Balance) {
+	check.Inputs["balance"] = balance.Balance
+	check.Inputs["held"] = balance.Held
+	check.Inputs["available"] = balance.Available
+}
+
+// compareAvailable compares a withdrawal against the available funds and
+// fails the check if they do not cover it
+func compareAvailable(check *CheckResult, event models.Event, amount float64, balance *models.Balance) bool {
+	covered := amount <= balance.Available
+	check.Compared = append(check.Compared, rules.Comparison{
+		Expr: "evidence.amount <= available", Op: "<=", Left: amount, Right: balance.Available, Result: covered,
+	})
+	if covered {
+		return true
 	}
 
-	return CheckResult{Passed: true}, nil
+	check.Passed = false
+	check.Reason = fmt.Sprintf("insufficient funds: available %.2f (balance %.2f, held %.2f), withdrawal %.2f",
+		balance.Available, balance.Balance, balance.Held, amount)
+	log.Printf("[Validator] Balance check failed for event %s: %s", event.ID, check.Reason)
+	return false
 }
 
 // checkTemporal verifies the timestamp is reasonable
 func (v *Validator) checkTemporal(ctx context.Context, event models.Event) (CheckResult, error) {
 	now := time.Now().UTC()
+	oldest := now.Add(-1 * time.Hour)
+	newest := now.Add(5 * time.Minute)
+
+	check := CheckResult{
+		Passed: true,
+		Inputs: map[string]any{"timestamp": event.Timestamp, "now": now},
+		Compared: []rules.Comparison{
+			{Expr: "timestamp >= now - 1h", Op: ">=", Left: event.Timestamp, Right: oldest, Result: !event.Timestamp.Before(oldest)},
+			{Expr: "timestamp <= now + 5m", Op: "<=", Left: event.Timestamp, Right: newest, Result: !event.Timestamp.After(newest)},
+		},
+	}
 
 	// Check if timestamp is too far in the past (more than 1 hour)
-	if event.Timestamp.Before(now.Add(-1 * time.Hour)) {
-		return CheckResult{
-			Passed: false,
-			Reason: "event timestamp is too old (more than 1 hour in the past)",
-		}, nil
+	if event.Timestamp.Before(oldest) {
+		check.Passed = false
+		check.Reason = "event timestamp is too old (more than 1 hour in the past)"
+		return check, nil
 	}
 
 	// Check if timestamp is in the future (allow 5 minute clock skew)
-	if event.Timestamp.After(now.Add(5 * time.Minute)) {
-		return CheckResult{
-			Passed: false,
-			Reason: "event timestamp is in the future",
-		}, nil
+	if event.Timestamp.After(newest) {
+		check.Passed = false
+		check.Reason = "event timestamp is in the future"
+		return check, nil
 	}
 
-	return CheckResult{Passed: true}, nil
-}
\ No newline at end of file
+	return check, nil
+}
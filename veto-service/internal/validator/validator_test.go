@@ -0,0 +1,267 @@
+package validator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/veps-service-480701/veto-service/internal/client"
+	"github.com/veps-service-480701/veto-service/internal/rules"
+	"github.com/veps-service-480701/veto-service/internal/velocity"
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// fakeRDB serves the RDB Updater endpoints the validator calls for a single
+// registered actor. Holds are placed one at a time, as the RDB does under its
+// balance row lock
+type fakeRDB struct {
+	balance float64
+
+	// Hold requests wait until this many have arrived, so the test knows
+	// they were in flight at the same time
+	concurrent int
+	arrived    int
+	allArrived chan struct{}
+
+	mu    sync.Mutex
+	holds map[string]float64
+}
+
+func newFakeRDB(t *testing.T, balance float64, concurrentHolds int) *httptest.Server {
+	f := &fakeRDB{
+		balance:    balance,
+		concurrent: concurrentHolds,
+		allArrived: make(chan struct{}),
+		holds:      make(map[string]float64),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/actors", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, http.StatusOK, models.ActorRecord{ID: r.URL.Query().Get("id"), Type: "user", Status: "active"})
+	})
+	mux.HandleFunc("/activity", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, http.StatusOK, models.Activity{Buckets: []models.ActivityBucket{}})
+	})
+	mux.HandleFunc("/balance", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeData(w, http.StatusOK, f.current())
+	})
+	mux.HandleFunc("/balance/hold", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			EventID string  `json:"event_id"`
+			Amount  float64 `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("hold request: %v", err)
+		}
+
+		f.arrive()
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.holds[req.EventID]; !ok {
+			if req.Amount > f.current().Available {
+				writeData(w, http.StatusUnprocessableEntity, f.current())
+				return
+			}
+			f.holds[req.EventID] = req.Amount
+		}
+		writeData(w, http.StatusOK, f.current())
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// arrive waits, briefly, for the other concurrent hold requests
+func (f *fakeRDB) arrive() {
+	f.mu.Lock()
+	f.arrived++
+	if f.arrived == f.concurrent {
+		close(f.allArrived)
+	}
+	f.mu.Unlock()
+
+	if f.concurrent == 0 {
+		return
+	}
+	select {
+	case <-f.allArrived:
+	case <-time.After(2 * time.Second):
+	}
+}
+
+// current returns the balance with its holds (caller holds mu)
+func (f *fakeRDB) current() models.Balance {
+	var held float64
+	for _, amount := range f.holds {
+		held += amount
+	}
+	return models.Balance{ActorID: "actor-1", Balance: f.balance, Held: held, Available: f.balance - held}
+}
+
+func writeData(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func newTestValidator(t *testing.T, server *httptest.Server) *Validator {
+	engine, err := rules.NewEngine("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdb := client.NewRDBClient(server.URL, 5*time.Second)
+	return New(rdb, engine, velocity.NewTracker(rdb, time.Minute))
+}
+
+func withdrawal(amount float64) models.Event {
+	return models.Event{
+		ID:        uuid.New(),
+		Type:      "withdrawal",
+		Source:    "test",
+		Timestamp: time.Now().UTC(),
+		Actor:     models.Actor{ID: "actor-1", Type: "user"},
+		Evidence:  map[string]any{"amount": amount},
+	}
+}
+
+func TestConcurrentWithdrawalsCannotOverdraw(t *testing.T) {
+	v := newTestValidator(t, newFakeRDB(t, 100, 2))
+
+	// Each withdrawal is covered by the balance on its own, not both together
+	events := []models.Event{withdrawal(80), withdrawal(80)}
+	results := make([]*Result, len(events))
+	errs := make([]error, len(events))
+
+	var wg sync.WaitGroup
+	for i := range events {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = v.Validate(t.Context(), events[i])
+		}()
+	}
+	wg.Wait()
+
+	passed := 0
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("withdrawal %d: %v", i, errs[i])
+		}
+		if result.Passed {
+			passed++
+			continue
+		}
+		if len(result.Errors) != 1 || result.Errors[0].Check != "balance" || !strings.Contains(result.Errors[0].Reason, "insufficient funds: available 20.00") {
+			t.Errorf("withdrawal %d vetoed for %+v, want insufficient funds", i, result.Errors)
+		}
+	}
+	if passed != 1 {
+		t.Errorf("%d withdrawals passed, want exactly 1", passed)
+	}
+}
+
+func TestRetriedWithdrawalKeepsItsHold(t *testing.T) {
+	v := newTestValidator(t, newFakeRDB(t, 100, 0))
+
+	event := withdrawal(80)
+	for attempt := 1; attempt <= 2; attempt++ {
+		result, err := v.Validate(t.Context(), event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Passed {
+			t.Fatalf("attempt %d vetoed: %+v", attempt, result.Errors)
+		}
+	}
+
+	// The retry did not hold the funds twice
+	result, err := v.Validate(t.Context(), withdrawal(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("withdrawal of the remaining funds vetoed: %+v", result.Errors)
+	}
+}
+
+func TestExplainDoesNotHoldFunds(t *testing.T) {
+	v := newTestValidator(t, newFakeRDB(t, 100, 0))
+
+	explained, err := v.Explain(t.Context(), withdrawal(80))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explained.Passed {
+		t.Fatalf("explain vetoed: %+v", explained.Errors)
+	}
+
+	result, err := v.Validate(t.Context(), withdrawal(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("withdrawal after an explain vetoed: %+v", result.Errors)
+	}
+}
+
+func TestBalanceCheckRefusesNonPositiveAmounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		amount    float64
+		want      bool
+	}{
+		{name: "negative withdrawal", eventType: "withdrawal", amount: -50, want: false},
+		{name: "zero withdrawal", eventType: "withdrawal", amount: 0, want: false},
+		{name: "negative payment", eventType: "payment_processed", amount: -50, want: false},
+		{name: "zero payment", eventType: "payment_processed", amount: 0, want: false},
+		{name: "payment", eventType: "payment_processed", amount: 500, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestValidator(t, newFakeRDB(t, 100, 0))
+			event := withdrawal(tt.amount)
+			event.Type = tt.eventType
+
+			result, err := v.Validate(t.Context(), event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var balance *CheckTrace
+			for i := range result.Checks {
+				if result.Checks[i].Check == "balance" {
+					balance = &result.Checks[i]
+				}
+			}
+			if balance == nil {
+				t.Fatal("no balance check in the trace")
+			}
+			if balance.Passed != tt.want {
+				t.Errorf("balance check passed = %v (%s), want %v", balance.Passed, balance.Reason, tt.want)
+			}
+		})
+	}
+
+	// A refused withdrawal holds nothing
+	v := newTestValidator(t, newFakeRDB(t, 100, 0))
+	if _, err := v.Validate(t.Context(), withdrawal(-50)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := v.Validate(t.Context(), withdrawal(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed {
+		t.Errorf("withdrawal of the whole balance vetoed: %+v", result.Errors)
+	}
+}
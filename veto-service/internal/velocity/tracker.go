@@ -0,0 +1,206 @@
+// Package velocity tracks per-actor activity for the ruleset's limits
+// An actor's events are loaded from the RDB Updater in one-minute buckets and
+// cached; events accepted by this instance are added locally until the RDB
+// catches up, so a busy actor costs one RDB query per cache TTL instead of
+// one per validation
+package velocity
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// Bucket is the width of the buckets activity is loaded in
+// A window is widened to whole buckets, so a limit may count up to one
+// bucket of activity older than its window
+const Bucket = time.Minute
+
+// maxEntries bounds the cache; expired entries are dropped beyond it
+const maxEntries = 10000
+
+// Source loads an actor's activity
+type Source interface {
+	GetActivity(ctx context.Context, actorID, eventType, field string, since time.Time, bucket time.Duration) (*models.Activity, error)
+}
+
+// Tracker caches per-actor activity windows
+type Tracker struct {
+	source Source
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[key]*entry
+}
+
+// Totals is an actor's activity within a window
+type Totals struct {
+	Count    int64     `json:"count"`
+	Sum      float64   `json:"sum"`
+	Cached   bool      `json:"cached"` // served without querying the RDB
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// key identifies one cached window: the summed field is part of it because
+// the RDB sums one field per query
+type key struct {
+	actorID   string
+	eventType string
+	field     string
+}
+
+type entry struct {
+	since          time.Time // start of the range loaded from the RDB
+	loadedAt       time.Time
+	persistedUntil time.Time // timestamp of the newest event the RDB returned
+	buckets        []models.ActivityBucket
+	recorded       []recorded // events accepted here that the RDB did not have yet
+}
+
+// recorded is an event accepted by this instance
+type recorded struct {
+	at     time.Time
+	amount float64
+}
+
+// NewTracker creates a Tracker
+// ttl bounds how stale a cached window may be, and so how long events
+// accepted by other instances can go unseen
+func NewTracker(source Source, ttl time.Duration) *Tracker {
+	return &Tracker{
+		source:  source,
+		ttl:     ttl,
+		entries: make(map[key]*entry),
+	}
+}
+
+// Totals returns how many events of a type an actor had in the window ending
+// now, and the sum of an evidence field over them (empty field: count only)
+func (t *Tracker) Totals(ctx context.Context, actorID, eventType, field string, window time.Duration) (Totals, error) {
+	now := time.Now()
+	start := now.Add(-window)
+	k := key{actorID: actorID, eventType: eventType, field: field}
+
+	t.mu.Lock()
+	e := t.entries[k]
+	fresh := e != nil && now.Sub(e.loadedAt) < t.ttl && !e.since.After(start)
+	if fresh {
+		totals := e.totals(start)
+		t.mu.Unlock()
+		return totals, nil
+	}
+	since := start.Truncate(Bucket)
+	if e != nil && e.since.Before(since) {
+		// Keep covering the longest window seen for this key
+		since = e.since
+	}
+	t.mu.Unlock()
+
+	// The query runs unlocked; a concurrent load of the same key only costs
+	// a second query
+	activity, err := t.source.GetActivity(ctx, actorID, eventType, field, since, Bucket)
+	if err != nil {
+		return Totals{}, err
+	}
+
+	loaded := &entry{
+		since:    since,
+		loadedAt: now,
+		buckets:  activity.Buckets,
+	}
+	if activity.LastEventAt != nil {
+		loaded.persistedUntil = *activity.LastEventAt
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Keep locally accepted events the RDB has not stored yet
+	if old := t.entries[k]; old != nil {
+		for _, r := range old.recorded {
+			if r.at.After(loaded.persistedUntil) {
+				loaded.recorded = append(loaded.recorded, r)
+			}
+		}
+	}
+
+	if len(t.entries) >= maxEntries {
+		t.evict(now)
+	}
+	t.entries[k] = loaded
+
+	totals := loaded.totals(start)
+	totals.Cached = false
+	return totals, nil
+}
+
+// Record adds an accepted event to every cached window of its actor and type
+func (t *Tracker) Record(event models.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, e := range t.entries {
+		if k.actorID != event.Actor.ID || k.eventType != event.Type {
+			continue
+		}
+		r := recorded{at: event.Timestamp}
+		if k.field != "" {
+			r.amount, _ = number(event.Evidence[k.field])
+		}
+		e.recorded = append(e.recorded, r)
+	}
+}
+
+// evict drops expired entries, and every entry if none have expired
+func (t *Tracker) evict(now time.Time) {
+	before := len(t.entries)
+	for k, e := range t.entries {
+		if now.Sub(e.loadedAt) >= t.ttl {
+			delete(t.entries, k)
+		}
+	}
+	if len(t.entries) >= maxEntries {
+		t.entries = make(map[key]*entry)
+	}
+	log.Printf("[Velocity] Evicted %d cached windows", before-len(t.entries))
+}
+
+// totals sums the buckets overlapping the window and the events recorded in it
+func (e *entry) totals(start time.Time) Totals {
+	totals := Totals{Cached: true, LoadedAt: e.loadedAt}
+	for _, b := range e.buckets {
+		if b.Start.Add(Bucket).After(start) {
+			totals.Count += b.Count
+			totals.Sum += b.Sum
+		}
+	}
+	for _, r := range e.recorded {
+		if !r.at.Before(start) {
+			totals.Count++
+			totals.Sum += r.amount
+		}
+	}
+	return totals
+}
+
+// number reads an evidence value as a number
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,157 @@
+package velocity
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/veps-service-480701/veto-service/pkg/models"
+)
+
+// fakeSource serves the events the RDB has stored, in one-minute buckets
+type fakeSource struct {
+	mu      sync.Mutex
+	stored  []models.Event
+	queries int
+}
+
+func (s *fakeSource) GetActivity(ctx context.Context, actorID, eventType, field string, since time.Time, bucket time.Duration) (*models.Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries++
+
+	activity := &models.Activity{Buckets: []models.ActivityBucket{}}
+	for _, e := range s.stored {
+		if e.Actor.ID != actorID || e.Type != eventType || e.Timestamp.Before(since) {
+			continue
+		}
+		amount, _ := number(e.Evidence[field])
+		activity.Count++
+		activity.Sum += amount
+		activity.Buckets = append(activity.Buckets, models.ActivityBucket{Start: e.Timestamp.Truncate(bucket), Count: 1, Sum: amount})
+		if activity.LastEventAt == nil || e.Timestamp.After(*activity.LastEventAt) {
+			at := e.Timestamp
+			activity.LastEventAt = &at
+		}
+	}
+	return activity, nil
+}
+
+func (s *fakeSource) store(events ...models.Event) {
+	s.mu.Lock()
+	s.stored = append(s.stored, events...)
+	s.mu.Unlock()
+}
+
+func withdrawal(actorID string, at time.Time, amount float64) models.Event {
+	return models.Event{
+		Type:      "withdrawal",
+		Actor:     models.Actor{ID: actorID},
+		Timestamp: at,
+		Evidence:  map[string]interface{}{"amount": amount},
+	}
+}
+
+func totals(t *testing.T, tracker *Tracker, actorID string) Totals {
+	t.Helper()
+	totals, err := tracker.Totals(context.Background(), actorID, "withdrawal", "amount", time.Hour)
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	return totals
+}
+
+func TestTrackerCountsRecordedEventsUntilReload(t *testing.T) {
+	now := time.Now()
+	source := &fakeSource{}
+	source.store(withdrawal("alice", now.Add(-10*time.Minute), 5))
+	tracker := NewTracker(source, time.Hour)
+
+	if got := totals(t, tracker, "alice"); got.Count != 1 || got.Sum != 5 || got.Cached {
+		t.Fatalf("first totals = %+v, want 1 withdrawal of 5 loaded from the source", got)
+	}
+
+	tracker.Record(withdrawal("alice", now, 7))
+	tracker.Record(withdrawal("bob", now, 100))
+	tracker.Record(models.Event{Type: "deposit", Actor: models.Actor{ID: "alice"}, Timestamp: now})
+
+	got := totals(t, tracker, "alice")
+	if got.Count != 2 || got.Sum != 12 || !got.Cached {
+		t.Errorf("totals after recording = %+v, want 2 withdrawals of 12 from the cache", got)
+	}
+	if source.queries != 1 {
+		t.Errorf("source queried %d times, want 1", source.queries)
+	}
+}
+
+func TestTrackerReconcilesRecordedEventsOnReload(t *testing.T) {
+	now := time.Now()
+	source := &fakeSource{}
+	source.store(withdrawal("alice", now.Add(-10*time.Minute), 5))
+
+	// A zero TTL reloads the window on every call
+	tracker := NewTracker(source, 0)
+	totals(t, tracker, "alice")
+
+	stored := withdrawal("alice", now.Add(-time.Second), 7)
+	pending := withdrawal("alice", now, 11)
+	tracker.Record(stored)
+	tracker.Record(pending)
+
+	// The RDB catches up on the first event only
+	source.store(stored)
+
+	tests := []struct {
+		name  string
+		count int64
+		sum   float64
+	}{
+		{name: "stored event counted once", count: 3, sum: 23},
+		{name: "pending event kept across reloads", count: 3, sum: 23},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := totals(t, tracker, "alice")
+			if got.Count != tt.count || got.Sum != tt.sum {
+				t.Errorf("totals = %+v, want %d withdrawals of %v", got, tt.count, tt.sum)
+			}
+		})
+	}
+
+	// Once the RDB has every event, nothing is kept locally
+	source.store(pending)
+	if got := totals(t, tracker, "alice"); got.Count != 3 || got.Sum != 23 {
+		t.Errorf("totals once stored = %+v, want 3 withdrawals of 23", got)
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	for k, e := range tracker.entries {
+		if len(e.recorded) != 0 {
+			t.Errorf("%+v still holds %d recorded events the RDB has", k, len(e.recorded))
+		}
+	}
+}
+
+func TestTrackerWidensToTheLongestWindow(t *testing.T) {
+	now := time.Now()
+	source := &fakeSource{}
+	source.store(withdrawal("alice", now.Add(-3*time.Hour), 1), withdrawal("alice", now.Add(-time.Minute), 2))
+	tracker := NewTracker(source, time.Hour)
+
+	day, err := tracker.Totals(context.Background(), "alice", "withdrawal", "amount", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Totals: %v", err)
+	}
+	if day.Count != 2 || day.Sum != 3 {
+		t.Errorf("day totals = %+v, want 2 withdrawals of 3", day)
+	}
+
+	// The shorter window is served from the day's buckets
+	if got := totals(t, tracker, "alice"); got.Count != 1 || got.Sum != 2 || !got.Cached {
+		t.Errorf("hour totals = %+v, want 1 cached withdrawal of 2", got)
+	}
+	if source.queries != 1 {
+		t.Errorf("source queried %d times, want 1", source.queries)
+	}
+}
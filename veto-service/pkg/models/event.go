@@ -4,40 +4,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/veps-service-480701/veps-common/model"
 )
 
-// Event represents a normalized event from VEPS Boundary Adapter
-type Event struct {
-	ID          uuid.UUID              `json:"id"`
-	Type        string                 `json:"type"`
-	Source      string                 `json:"source"`
-	Timestamp   time.Time              `json:"timestamp"`
-	Actor       Actor                  `json:"actor"`
-	Evidence    map[string]interface{} `json:"evidence"`
-	VectorClock VectorClock            `json:"vector_clock"`
-	Metadata    EventMetadata          `json:"metadata,omitempty"`
-}
-
-// Actor represents the entity that triggered the event
-type Actor struct {
-	ID       string            `json:"id"`
-	Name     string            `json:"name"`
-	Type     string            `json:"type,omitempty"`
-	Metadata map[string]string `json:"metadata,omitempty"`
-}
-
-// VectorClock tracks causality for distributed event ordering
-type VectorClock map[string]int64
-
-// EventMetadata contains additional context
-type EventMetadata struct {
-	ReceivedAt    time.Time `json:"received_at"`
-	ProcessedAt   time.Time `json:"processed_at,omitempty"`
-	BoundaryNode  string    `json:"boundary_node"`
-	CorrelationID string    `json:"correlation_id"`
-	RetryCount    int       `json:"retry_count"`
-	SchemaVersion string    `json:"schema_version"`
-}
+// Event and its parts come from veps-common, so the Veto Service validates
+// exactly what the Boundary Adapter produced
+type (
+	Event         = model.Event
+	Actor         = model.Actor
+	VectorClock   = model.VectorClock
+	EventMetadata = model.EventMetadata
+)
 
 // ValidationResult represents the outcome of veto validation
 type ValidationResult struct {
@@ -53,4 +30,48 @@ type ValidationResult struct {
 type VetoRequest struct {
 	Event Event  `json:"event"`
 	Route string `json:"route"` // "veto_service"
-}
\ No newline at end of file
+}
+
+// MissingDependency identifies a causal predecessor that has not been stored yet
+type MissingDependency struct {
+	Node            string `json:"node"`
+	ExpectedCounter int64  `json:"expected_counter"`
+}
+
+// ActorRecord is an actor as stored in the RDB actor registry
+type ActorRecord struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Name            string `json:"name,omitempty"`
+	Status          string `json:"status"` // "active" or "suspended"
+	RegisteredBy    string `json:"registered_by"`
+	SuspendedReason string `json:"suspended_reason,omitempty"`
+}
+
+// Activity is an actor's event count and total over a time range, as
+// aggregated by the RDB Updater
+type Activity struct {
+	Count       int64            `json:"count"`
+	Sum         float64          `json:"sum"`
+	LastEventAt *time.Time       `json:"last_event_at,omitempty"`
+	Buckets     []ActivityBucket `json:"buckets"`
+}
+
+// ActivityBucket is the part of an Activity that falls in one bucket
+type ActivityBucket struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+	Sum   float64   `json:"sum"`
+}
+
+// Balance is an actor's balance in the RDB balance projection
+// Balance moves when an event is sealed; Held is reserved by accepted
+// withdrawals awaiting their seal
+type Balance struct {
+	ActorID      string  `json:"actor_id"`
+	Balance      float64 `json:"balance"`
+	Held         float64 `json:"held"`
+	Available    float64 `json:"available"`
+	Version      int64   `json:"version"`
+	EventApplied *bool   `json:"event_applied,omitempty"`
+}